@@ -123,6 +123,31 @@ public actor GroceriesAPIClient {
         }
     }
 
+    /// Reads every page of a listing, following `meta.next_cursor` until the
+    /// server reports no further pages, and returns the entries of all pages.
+    func performPaged<Element: Decodable>(
+        path: String,
+        queryItems: [URLQueryItem] = []
+    ) async throws -> [Element] {
+        var elements: [Element] = []
+        var cursor: String?
+
+        repeat {
+            var pageQuery = queryItems
+            if let cursor {
+                pageQuery.append(URLQueryItem(name: "cursor", value: cursor))
+            }
+
+            let req = try request(method: "GET", path: path, queryItems: pageQuery)
+            let page: Page<Element> = try await perform(req)
+            elements.append(contentsOf: page.data)
+
+            cursor = page.meta.hasMore ? page.meta.nextCursor : nil
+        } while cursor != nil
+
+        return elements
+    }
+
     /// Executes a request that returns no body (e.g. 204 No Content).
     func performVoid(_ urlRequest: URLRequest) async throws {
         let (data, response) = try await session.data(for: urlRequest)
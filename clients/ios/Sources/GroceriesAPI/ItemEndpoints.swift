@@ -16,12 +16,7 @@ extension GroceriesAPIClient {
             queryItems.append(URLQueryItem(name: "in_list", value: inList ? "true" : "false"))
         }
 
-        let req = try request(
-            method: "GET",
-            path: "/api/v1/items",
-            queryItems: queryItems.isEmpty ? nil : queryItems
-        )
-        return try await perform(req)
+        return try await performPaged(path: "/api/v1/items", queryItems: queryItems)
     }
 
     /// Creates a new grocery item.
@@ -208,6 +208,26 @@ public struct UpdateListItemRequest: Encodable, Sendable {
     }
 }
 
+// MARK: - Pagination
+
+/// One page of a listing such as `GET /api/v1/items`.
+struct Page<Element: Decodable>: Decodable {
+    let data: [Element]
+    let meta: PageMeta
+}
+
+/// Describes a page of a listing. While `hasMore` is true, `nextCursor` is
+/// passed as the `cursor` query parameter to read the following page.
+struct PageMeta: Decodable {
+    let hasMore: Bool
+    let nextCursor: String?
+
+    enum CodingKeys: String, CodingKey {
+        case hasMore = "has_more"
+        case nextCursor = "next_cursor"
+    }
+}
+
 // MARK: - Errors
 
 /// A structured error returned by the API.
@@ -316,20 +316,23 @@ final class GroceriesAPIClientTests: XCTestCase {
 
     func testListItems_returnsAllItems() async throws {
         let responseJSON = """
-            [
-                {
-                    "id": 1,
-                    "category_id": 10,
-                    "category_name": "Produce",
-                    "name": "Apples"
-                },
-                {
-                    "id": 2,
-                    "category_id": 11,
-                    "category_name": "Bakery",
-                    "name": "Bread"
-                }
-            ]
+            {
+                "data": [
+                    {
+                        "id": 1,
+                        "category_id": 10,
+                        "category_name": "Produce",
+                        "name": "Apples"
+                    },
+                    {
+                        "id": 2,
+                        "category_id": 11,
+                        "category_name": "Bakery",
+                        "name": "Bread"
+                    }
+                ],
+                "meta": {"count": 2, "limit": 100, "sort": "name", "has_more": false}
+            }
             """
 
         MockURLProtocol.setRequestHandler { request in
@@ -359,14 +362,17 @@ final class GroceriesAPIClientTests: XCTestCase {
 
     func testListItems_withInListQueryEncodesQueryItem() async throws {
         let responseJSON = """
-            [
-                {
-                    "id": 1,
-                    "category_id": 10,
-                    "category_name": "Produce",
-                    "name": "Apples"
-                }
-            ]
+            {
+                "data": [
+                    {
+                        "id": 1,
+                        "category_id": 10,
+                        "category_name": "Produce",
+                        "name": "Apples"
+                    }
+                ],
+                "meta": {"count": 1, "limit": 100, "sort": "name", "has_more": false}
+            }
             """
 
         MockURLProtocol.setRequestHandler { request in
@@ -400,14 +406,17 @@ final class GroceriesAPIClientTests: XCTestCase {
 
     func testListItems_withCategoryIDQueryEncodesCategoryIDOnly() async throws {
         let responseJSON = """
-            [
-                {
-                    "id": 3,
-                    "category_id": 10,
-                    "category_name": "Produce",
-                    "name": "Carrots"
-                }
-            ]
+            {
+                "data": [
+                    {
+                        "id": 3,
+                        "category_id": 10,
+                        "category_name": "Produce",
+                        "name": "Carrots"
+                    }
+                ],
+                "meta": {"count": 1, "limit": 100, "sort": "name", "has_more": false}
+            }
             """
 
         MockURLProtocol.setRequestHandler { request in
@@ -441,14 +450,17 @@ final class GroceriesAPIClientTests: XCTestCase {
 
     func testListItems_withInListFalseQueryEncodesFalse() async throws {
         let responseJSON = """
-            [
-                {
-                    "id": 4,
-                    "category_id": 12,
-                    "category_name": "Pantry",
-                    "name": "Pasta"
-                }
-            ]
+            {
+                "data": [
+                    {
+                        "id": 4,
+                        "category_id": 12,
+                        "category_name": "Pantry",
+                        "name": "Pasta"
+                    }
+                ],
+                "meta": {"count": 1, "limit": 100, "sort": "name", "has_more": false}
+            }
             """
 
         MockURLProtocol.setRequestHandler { request in
@@ -482,14 +494,17 @@ final class GroceriesAPIClientTests: XCTestCase {
 
     func testListItems_withCategoryIDAndInListQueryEncodesBothItems() async throws {
         let responseJSON = """
-            [
-                {
-                    "id": 5,
-                    "category_id": 2,
-                    "category_name": "Dairy",
-                    "name": "Yogurt"
-                }
-            ]
+            {
+                "data": [
+                    {
+                        "id": 5,
+                        "category_id": 2,
+                        "category_name": "Dairy",
+                        "name": "Yogurt"
+                    }
+                ],
+                "meta": {"count": 1, "limit": 100, "sort": "name", "has_more": false}
+            }
             """
 
         MockURLProtocol.setRequestHandler { request in
@@ -523,6 +538,96 @@ final class GroceriesAPIClientTests: XCTestCase {
         XCTAssertEqual(items[0].name, "Yogurt")
     }
 
+    func testListItems_followsNextCursor() async throws {
+        let firstPageJSON = """
+            {
+                "data": [
+                    {
+                        "id": 1,
+                        "category_id": 10,
+                        "category_name": "Produce",
+                        "name": "Apples"
+                    }
+                ],
+                "meta": {"count": 1, "limit": 1, "sort": "name", "has_more": true, "next_cursor": "page2"}
+            }
+            """
+        let secondPageJSON = """
+            {
+                "data": [
+                    {
+                        "id": 2,
+                        "category_id": 11,
+                        "category_name": "Bakery",
+                        "name": "Bread"
+                    }
+                ],
+                "meta": {"count": 1, "limit": 1, "sort": "name", "has_more": false}
+            }
+            """
+
+        MockURLProtocol.setRequestHandler { request in
+            XCTAssertEqual(request.httpMethod, "GET")
+            XCTAssertEqual(request.url?.path, "/api/v1/items")
+
+            let queryItems = URLComponents(url: try XCTUnwrap(request.url), resolvingAgainstBaseURL: false)?.queryItems ?? []
+            let queryByName = Dictionary(uniqueKeysWithValues: queryItems.map { ($0.name, $0.value) })
+            XCTAssertEqual(queryByName["in_list"], "true")
+
+            let json = queryByName["cursor"] == "page2" ? secondPageJSON : firstPageJSON
+            let data = try XCTUnwrap(json.data(using: .utf8))
+            let response = try XCTUnwrap(
+                HTTPURLResponse(
+                    url: try XCTUnwrap(request.url),
+                    statusCode: 200,
+                    httpVersion: nil,
+                    headerFields: nil
+                )
+            )
+
+            return (response, data)
+        }
+
+        let client = makeClient()
+        let items = try await client.listItems(inList: true)
+
+        XCTAssertEqual(items.map(\.name), ["Apples", "Bread"])
+    }
+
+    func testListStores_returnsAllStores() async throws {
+        let responseJSON = """
+            {
+                "data": [
+                    {"id": 1, "name": "Corner Shop"},
+                    {"id": 2, "name": "Whole Foods"}
+                ],
+                "meta": {"count": 2, "limit": 100, "sort": "name", "has_more": false}
+            }
+            """
+
+        MockURLProtocol.setRequestHandler { request in
+            XCTAssertEqual(request.httpMethod, "GET")
+            XCTAssertEqual(request.url?.path, "/api/v1/stores")
+
+            let data = try XCTUnwrap(responseJSON.data(using: .utf8))
+            let response = try XCTUnwrap(
+                HTTPURLResponse(
+                    url: try XCTUnwrap(request.url),
+                    statusCode: 200,
+                    httpVersion: nil,
+                    headerFields: nil
+                )
+            )
+
+            return (response, data)
+        }
+
+        let client = makeClient()
+        let stores = try await client.listStores()
+
+        XCTAssertEqual(stores.map(\.name), ["Corner Shop", "Whole Foods"])
+    }
+
     func testCreateItem_postsExpectedBody() async throws {
         let responseJSON = """
             {
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/taiidani/groceries/sdk"
+)
+
+const (
+	// reconnectDelay is how long to wait before reopening a lost event stream.
+	reconnectDelay = 5 * time.Second
+
+	// loadingStatus is shown until the list has loaded for the first time.
+	loadingStatus = "Loading…"
+)
+
+type mode int
+
+const (
+	modeBrowse mode = iota
+	modeAdd
+)
+
+// Messages delivered to the event loop. All state changes happen on the loop,
+// so the app needs no locking.
+type (
+	keyMsg    key
+	resizeMsg struct{ width, height int }
+	eventMsg  sdk.Event
+	loadedMsg struct {
+		rows []row
+		err  error
+	}
+	connMsg struct {
+		connected bool
+		err       error
+	}
+	resultMsg struct {
+		status string
+		err    error
+	}
+)
+
+// app is the state of the terminal UI.
+type app struct {
+	client *sdk.Client
+	msgs   chan any
+
+	rows   []row
+	cursor int
+	offset int
+	width  int
+	height int
+
+	mode  mode
+	input []rune
+
+	status    string
+	statusErr bool
+	connected bool
+
+	// loading is set while the list is being fetched, and stale when a change
+	// arrived during the fetch so that the list must be fetched again.
+	loading bool
+	stale   bool
+}
+
+func newApp(c *sdk.Client, width, height int) *app {
+	return &app{
+		client: c,
+		msgs:   make(chan any),
+		width:  width,
+		height: height,
+		cursor: -1,
+		status: loadingStatus,
+	}
+}
+
+// run draws the UI and handles messages until the user quits or ctx is done.
+func (a *app) run(ctx context.Context, out io.Writer) error {
+	go a.subscribe(ctx)
+	a.reload(ctx)
+
+	for {
+		a.render(out)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg := <-a.msgs:
+			if quit := a.update(ctx, msg); quit {
+				return nil
+			}
+		}
+	}
+}
+
+// send delivers a message to the event loop from another goroutine.
+func (a *app) send(ctx context.Context, msg any) {
+	select {
+	case a.msgs <- msg:
+	case <-ctx.Done():
+	}
+}
+
+// update applies a message to the state, returning true when the user quits.
+func (a *app) update(ctx context.Context, msg any) bool {
+	switch msg := msg.(type) {
+	case keyMsg:
+		return a.handleKey(ctx, key(msg))
+
+	case resizeMsg:
+		a.width, a.height = msg.width, msg.height
+
+	case eventMsg:
+		switch msg.Name {
+		case "list", "cart", "category":
+			a.reload(ctx)
+		case "close":
+			a.setStatus("The server is shutting down", nil)
+		}
+
+	case connMsg:
+		a.connected = msg.connected
+		if msg.connected {
+			// Changes may have been missed while disconnected
+			a.reload(ctx)
+		} else if msg.err != nil {
+			a.setStatus("", fmt.Errorf("live updates unavailable: %w", msg.err))
+		}
+
+	case loadedMsg:
+		a.loading = false
+		if msg.err != nil {
+			a.setStatus("", fmt.Errorf("could not load the list: %w", msg.err))
+		} else {
+			a.setRows(msg.rows)
+			if a.status == loadingStatus {
+				a.setStatus("", nil)
+			}
+		}
+		if a.stale {
+			a.reload(ctx)
+		}
+
+	case resultMsg:
+		a.setStatus(msg.status, msg.err)
+		a.reload(ctx)
+	}
+
+	return false
+}
+
+func (a *app) handleKey(ctx context.Context, k key) bool {
+	if k.code == keyInterrupt {
+		return true
+	}
+
+	if a.mode == modeAdd {
+		switch k.code {
+		case keyEscape:
+			a.mode, a.input = modeBrowse, nil
+		case keyEnter:
+			name := string(a.input)
+			a.mode, a.input = modeBrowse, nil
+			if name != "" {
+				go a.add(ctx, name)
+			}
+		case keyBackspace:
+			if len(a.input) > 0 {
+				a.input = a.input[:len(a.input)-1]
+			}
+		case keyRune:
+			a.input = append(a.input, k.r)
+		}
+		return false
+	}
+
+	switch k.code {
+	case keyUp:
+		a.move(-1)
+	case keyDown:
+		a.move(1)
+	case keyPageUp:
+		a.move(-a.bodyHeight())
+	case keyPageDown:
+		a.move(a.bodyHeight())
+	case keyHome:
+		a.move(-len(a.rows))
+	case keyEnd:
+		a.move(len(a.rows))
+	case keyEnter:
+		a.toggle(ctx)
+	case keyEscape:
+		a.setStatus("", nil)
+	case keyRune:
+		switch k.r {
+		case 'k':
+			a.move(-1)
+		case 'j':
+			a.move(1)
+		case ' ', 'x':
+			a.toggle(ctx)
+		case 'a', '+':
+			a.mode = modeAdd
+		case 'r':
+			a.reload(ctx)
+		case 'q':
+			return true
+		}
+	}
+
+	return false
+}
+
+// move moves the cursor by delta items, skipping headings and stopping at
+// the first and last items.
+func (a *app) move(delta int) {
+	step := 1
+	if delta < 0 {
+		step, delta = -1, -delta
+	}
+
+	for i, moved := a.cursor+step, 0; i >= 0 && i < len(a.rows) && moved < delta; i += step {
+		if a.rows[i].kind == rowItem {
+			a.cursor = i
+			moved++
+		}
+	}
+}
+
+// setRows replaces the list, keeping the cursor on the same item if it is
+// still on the list.
+func (a *app) setRows(rows []row) {
+	selected := -1
+	if a.cursor >= 0 && a.cursor < len(a.rows) {
+		selected = a.rows[a.cursor].item.ID
+	}
+
+	a.rows = rows
+	a.cursor = -1
+	for i, r := range rows {
+		if r.kind != rowItem {
+			continue
+		}
+		if a.cursor < 0 || r.item.ID == selected {
+			a.cursor = i
+		}
+		if r.item.ID == selected {
+			break
+		}
+	}
+}
+
+// scroll adjusts the offset so that the cursor is within a screen of height
+// rows.
+func (a *app) scroll(height int) {
+	if a.cursor < a.offset {
+		a.offset = a.cursor
+	} else if a.cursor >= a.offset+height {
+		a.offset = a.cursor - height + 1
+	}
+	a.offset = max(0, min(a.offset, len(a.rows)-height))
+}
+
+// bodyHeight is the number of list rows that fit between the title, status
+// and footer lines.
+func (a *app) bodyHeight() int {
+	return max(1, a.height-3)
+}
+
+func (a *app) counts() (total, done int) {
+	for _, r := range a.rows {
+		if r.kind != rowItem {
+			continue
+		}
+		total++
+		if r.item.List.Done {
+			done++
+		}
+	}
+	return total, done
+}
+
+func (a *app) setStatus(status string, err error) {
+	a.status, a.statusErr = status, err != nil
+	if err != nil {
+		a.status = err.Error()
+	}
+}
+
+// reload fetches the list in the background, or marks it stale if a fetch is
+// already running.
+func (a *app) reload(ctx context.Context) {
+	if a.loading {
+		a.stale = true
+		return
+	}
+	a.loading, a.stale = true, false
+
+	go func() {
+		rows, err := a.load(ctx)
+		a.send(ctx, loadedMsg{rows: rows, err: err})
+	}()
+}
+
+func (a *app) load(ctx context.Context) ([]row, error) {
+	stores, err := a.client.ListStoresAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := a.client.ListCategoriesAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := a.client.ListItemsAll(ctx, &sdk.ListItemsParams{InList: sdk.Ptr(true)})
+	if err != nil {
+		return nil, err
+	}
+
+	return buildRows(stores, categories, items), nil
+}
+
+// toggle moves the selected item into or out of the cart. The change is
+// shown immediately and reverted by the reload if the server rejects it.
+func (a *app) toggle(ctx context.Context) {
+	if a.cursor < 0 || a.cursor >= len(a.rows) {
+		return
+	}
+
+	item := a.rows[a.cursor].item
+	entry := *item.List
+	entry.Done = !entry.Done
+	a.rows[a.cursor].item.List = &entry
+
+	go func() {
+		_, err := a.client.UpdateListItem(ctx, item.ID, nil, sdk.UpdateListItemRequest{Done: &entry.Done})
+		if err != nil {
+			a.send(ctx, resultMsg{err: fmt.Errorf("could not update %s: %w", item.Name, err)})
+		} else if entry.Done {
+			a.send(ctx, resultMsg{status: "Checked off " + item.Name})
+		} else {
+			a.send(ctx, resultMsg{status: "Put back " + item.Name})
+		}
+	}()
+}
+
+// add adds an item to the list by name, creating it if it does not exist.
+func (a *app) add(ctx context.Context, name string) {
+	resp, err := a.client.AddToListBatch(ctx, sdk.AddToListBatchRequest{
+		Items: []sdk.AddToListBatchRequestItem{{Name: &name}},
+	})
+	results := resp.Results
+	if err == nil && len(results) == 1 && results[0].Item != nil {
+		// The name may have matched an existing item spelled differently
+		name = results[0].Item.ItemName
+	}
+
+	switch {
+	case err != nil:
+		a.send(ctx, resultMsg{err: fmt.Errorf("could not add %s: %w", name, err)})
+	case len(results) != 1:
+		a.send(ctx, resultMsg{err: fmt.Errorf("could not add %s: unexpected response", name)})
+	case !results[0].OK:
+		a.send(ctx, resultMsg{err: fmt.Errorf("could not add %s: %s", name, sdk.Deref(results[0].Error))})
+	case results[0].Created:
+		a.send(ctx, resultMsg{status: "Added " + name + " as a new item"})
+	default:
+		a.send(ctx, resultMsg{status: "Added " + name})
+	}
+}
+
+// subscribe keeps the event stream open, reconnecting when it is lost.
+func (a *app) subscribe(ctx context.Context) {
+	for {
+		events, err := a.client.Subscribe(ctx)
+		if err == nil {
+			a.send(ctx, connMsg{connected: true})
+			for evt := range events {
+				a.send(ctx, eventMsg(evt))
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+		a.send(ctx, connMsg{connected: false, err: err})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
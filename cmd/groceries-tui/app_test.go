@@ -0,0 +1,118 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/taiidani/groceries/sdk"
+)
+
+func TestDecodeKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []key
+	}{
+		{name: "letters", input: "ab", want: []key{{code: keyRune, r: 'a'}, {code: keyRune, r: 'b'}}},
+		{name: "unicode", input: "é", want: []key{{code: keyRune, r: 'é'}}},
+		{name: "arrows", input: "\x1b[A\x1bOB", want: []key{{code: keyUp}, {code: keyDown}}},
+		{name: "paging", input: "\x1b[5~\x1b[6~", want: []key{{code: keyPageUp}, {code: keyPageDown}}},
+		{name: "escape", input: "\x1b", want: []key{{code: keyEscape}}},
+		{name: "escape then letter", input: "\x1bq", want: []key{{code: keyEscape}, {code: keyRune, r: 'q'}}},
+		{name: "unknown sequence", input: "\x1b[1;5Cx", want: []key{{code: keyRune, r: 'x'}}},
+		{name: "controls", input: "\r\x7f\x03", want: []key{{code: keyEnter}, {code: keyBackspace}, {code: keyInterrupt}}},
+		{name: "ignored control", input: "\x01", want: []key{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeKeys([]byte(tt.input))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeKeys(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func testRows() []row {
+	stores := []sdk.Store{{ID: 0, Name: "Uncategorized"}, {ID: 1, Name: "Grocer"}, {ID: 2, Name: "Hardware"}}
+	categories := []sdk.Category{
+		{ID: 0, StoreID: 0, Name: "Uncategorized"},
+		{ID: 1, StoreID: 1, Name: "Dairy"},
+		{ID: 2, StoreID: 1, Name: "Produce"},
+		{ID: 3, StoreID: 2, Name: "Tools"},
+	}
+	items := []sdk.Item{
+		{ID: 1, CategoryID: 1, Name: "Milk", List: &sdk.ListItemSummary{Quantity: "2"}},
+		{ID: 2, CategoryID: 2, Name: "Apples", List: &sdk.ListItemSummary{Done: true}},
+		{ID: 3, CategoryID: 1, Name: "Cheese", List: &sdk.ListItemSummary{}},
+		{ID: 4, CategoryID: 3, Name: "Hammer"},
+	}
+	return buildRows(stores, categories, items)
+}
+
+func TestBuildRows(t *testing.T) {
+	got := []string{}
+	for _, r := range testRows() {
+		got = append(got, r.name)
+	}
+
+	// Empty stores, empty categories and items not on the list are omitted
+	want := []string{"Grocer", "Dairy", "Milk", "Cheese", "Produce", "Apples"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildRows() = %q, want %q", got, want)
+	}
+}
+
+func TestAppMove(t *testing.T) {
+	a := newApp(nil, 80, 24)
+	a.setRows(testRows())
+
+	names := func() string { return a.rows[a.cursor].name }
+	if names() != "Milk" {
+		t.Fatalf("initial cursor on %q, want the first item", names())
+	}
+
+	steps := []struct {
+		delta int
+		want  string
+	}{
+		{delta: -1, want: "Milk"},
+		{delta: 1, want: "Cheese"},
+		{delta: 1, want: "Apples"},
+		{delta: 1, want: "Apples"},
+		{delta: -2, want: "Milk"},
+		{delta: 10, want: "Apples"},
+	}
+	for _, step := range steps {
+		a.move(step.delta)
+		if names() != step.want {
+			t.Errorf("move(%d) = %q, want %q", step.delta, names(), step.want)
+		}
+	}
+}
+
+func TestAppSetRowsKeepsSelection(t *testing.T) {
+	a := newApp(nil, 80, 24)
+	a.setRows(testRows())
+	a.move(1)
+
+	// Reloading keeps the cursor on the same item
+	a.setRows(testRows())
+	if got := a.rows[a.cursor].name; got != "Cheese" {
+		t.Errorf("cursor on %q after reload, want Cheese", got)
+	}
+
+	// Removing the selected item moves the cursor back to the first item
+	rows := testRows()
+	rows = append(rows[:3], rows[4:]...)
+	a.setRows(rows)
+	if got := a.rows[a.cursor].name; got != "Milk" {
+		t.Errorf("cursor on %q after removal, want Milk", got)
+	}
+
+	a.setRows(nil)
+	if a.cursor != -1 {
+		t.Errorf("cursor = %d on an empty list, want -1", a.cursor)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// keyCode identifies a key press. Printable characters are keyRune.
+type keyCode int
+
+const (
+	keyRune keyCode = iota
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyHome
+	keyEnd
+	keyEnter
+	keyEscape
+	keyBackspace
+	keyInterrupt
+)
+
+type key struct {
+	code keyCode
+	r    rune
+}
+
+// escapeSequences maps the sequences that terminals send for special keys.
+var escapeSequences = []struct {
+	seq  string
+	code keyCode
+}{
+	{"\x1b[A", keyUp},
+	{"\x1bOA", keyUp},
+	{"\x1b[B", keyDown},
+	{"\x1bOB", keyDown},
+	{"\x1b[5~", keyPageUp},
+	{"\x1b[6~", keyPageDown},
+	{"\x1b[H", keyHome},
+	{"\x1bOH", keyHome},
+	{"\x1b[1~", keyHome},
+	{"\x1b[F", keyEnd},
+	{"\x1bOF", keyEnd},
+	{"\x1b[4~", keyEnd},
+}
+
+// decodeKeys converts the bytes read from a raw terminal into key presses.
+// Unrecognized escape sequences are dropped.
+func decodeKeys(b []byte) []key {
+	ret := []key{}
+
+	for len(b) > 0 {
+		switch b[0] {
+		case 0x1b:
+			matched := false
+			for _, esc := range escapeSequences {
+				if bytes.HasPrefix(b, []byte(esc.seq)) {
+					ret = append(ret, key{code: esc.code})
+					b = b[len(esc.seq):]
+					matched = true
+					break
+				}
+			}
+			if matched {
+				continue
+			}
+
+			if len(b) == 1 || (b[1] != '[' && b[1] != 'O') {
+				ret = append(ret, key{code: keyEscape})
+				b = b[1:]
+				continue
+			}
+
+			// Skip an unknown CSI sequence up to its final byte
+			end := 2
+			for end < len(b) && (b[end] < 0x40 || b[end] > 0x7e) {
+				end++
+			}
+			b = b[min(end+1, len(b)):]
+		case '\r', '\n':
+			ret = append(ret, key{code: keyEnter})
+			b = b[1:]
+		case 0x7f, 0x08:
+			ret = append(ret, key{code: keyBackspace})
+			b = b[1:]
+		case 0x03, 0x04:
+			ret = append(ret, key{code: keyInterrupt})
+			b = b[1:]
+		default:
+			r, size := utf8.DecodeRune(b)
+			if r >= 0x20 && r != utf8.RuneError {
+				ret = append(ret, key{code: keyRune, r: r})
+			}
+			b = b[size:]
+		}
+	}
+
+	return ret
+}
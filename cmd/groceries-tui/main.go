@@ -0,0 +1,167 @@
+// Command groceries-tui is a terminal client for the groceries shopping list.
+// It logs in to a groceries server, shows the list grouped by store and
+// category, and keeps it up to date as other people shop.
+//
+// Usage:
+//
+//	groceries-tui [-url URL] [-user NAME]
+//
+// The password is read from GROCERIES_PASSWORD or prompted for. An existing
+// API token can be given in GROCERIES_TOKEN instead of logging in.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/taiidani/groceries/sdk"
+)
+
+// defaultURL is the server used when neither -url nor GROCERIES_URL is set.
+const defaultURL = "http://localhost:3000"
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+	defer cancel()
+
+	if err := run(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "groceries-tui: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context) error {
+	url := os.Getenv("GROCERIES_URL")
+	if url == "" {
+		url = defaultURL
+	}
+
+	flag.StringVar(&url, "url", url, "server URL ($GROCERIES_URL)")
+	user := flag.String("user", os.Getenv("GROCERIES_USER"), "user to log in as ($GROCERIES_USER)")
+	flag.Parse()
+
+	c, err := login(ctx, url, *user)
+	if err != nil {
+		return err
+	}
+
+	width, height, err := terminalSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return fmt.Errorf("could not read the terminal size: %w", err)
+	}
+
+	restore, err := makeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	fmt.Fprint(os.Stdout, ansiAltScreen)
+	defer fmt.Fprint(os.Stdout, ansiShowCursor+ansiMainScreen)
+
+	// Stop the background requests when the UI exits
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	a := newApp(c, width, height)
+	go readKeys(ctx, a, os.Stdin)
+	go watchSize(ctx, a, os.Stdout)
+
+	return a.run(ctx, os.Stdout)
+}
+
+// login returns a client for the server, using the token in GROCERIES_TOKEN
+// or logging in with a username and password.
+func login(ctx context.Context, url, user string) (*sdk.Client, error) {
+	if token := os.Getenv("GROCERIES_TOKEN"); token != "" {
+		return sdk.New(url, token), nil
+	}
+
+	var err error
+	if user == "" {
+		fmt.Fprint(os.Stderr, "Username: ")
+		if user, err = readLine(os.Stdin); err != nil {
+			return nil, err
+		}
+	}
+
+	password := os.Getenv("GROCERIES_PASSWORD")
+	if password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		password, err = readPassword(os.Stdin)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	session, err := sdk.Login(ctx, url, strings.TrimSpace(user), password)
+	if err != nil {
+		return nil, fmt.Errorf("could not log in: %w", err)
+	}
+
+	return sdk.New(url, session.Token), nil
+}
+
+// readLine reads a line one byte at a time, so that nothing after it is
+// consumed from the terminal.
+func readLine(r io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				break
+			}
+			line = append(line, b[0])
+		}
+		if errors.Is(err, io.EOF) && len(line) > 0 {
+			break
+		} else if err != nil {
+			return "", err
+		}
+	}
+
+	return strings.TrimRight(string(line), "\r"), nil
+}
+
+// readKeys delivers key presses to the app until ctx is done.
+func readKeys(ctx context.Context, a *app, in io.Reader) {
+	buf := make([]byte, 64)
+	for {
+		n, err := in.Read(buf)
+		for _, k := range decodeKeys(buf[:n]) {
+			a.send(ctx, keyMsg(k))
+		}
+		if err != nil {
+			a.send(ctx, keyMsg{code: keyInterrupt})
+			return
+		}
+	}
+}
+
+// watchSize delivers the new terminal size to the app when it is resized.
+func watchSize(ctx context.Context, a *app, out *os.File) {
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	defer signal.Stop(resized)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-resized:
+			if width, height, err := terminalSize(int(out.Fd())); err == nil {
+				a.send(ctx, resizeMsg{width: width, height: height})
+			}
+		}
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+var errUnsupported = errors.New("terminal control is not supported on this platform")
+
+func makeRaw(fd int) (func() error, error) {
+	return nil, errUnsupported
+}
+
+func terminalSize(fd int) (int, int, error) {
+	return 0, 0, errUnsupported
+}
+
+func notifyResize(ch chan<- os.Signal) {}
+
+func readPassword(f *os.File) (string, error) {
+	return "", errUnsupported
+}
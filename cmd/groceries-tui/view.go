@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/taiidani/groceries/sdk"
+)
+
+type rowKind int
+
+const (
+	rowStore rowKind = iota
+	rowCategory
+	rowItem
+)
+
+// row is a line of the list. Stores and categories are headings; only items
+// can be selected.
+type row struct {
+	kind rowKind
+	name string
+	item sdk.Item
+}
+
+// buildRows arranges the items on the shopping list under their stores and
+// categories, omitting stores and categories with nothing on the list.
+func buildRows(stores []sdk.Store, categories []sdk.Category, items []sdk.Item) []row {
+	ret := []row{}
+
+	for _, store := range stores {
+		storeRows := []row{}
+
+		for _, cat := range categories {
+			if cat.StoreID != store.ID {
+				continue
+			}
+
+			catRows := []row{}
+			for _, item := range items {
+				if item.CategoryID == cat.ID && item.List != nil {
+					catRows = append(catRows, row{kind: rowItem, name: item.Name, item: item})
+				}
+			}
+
+			if len(catRows) > 0 {
+				storeRows = append(storeRows, row{kind: rowCategory, name: cat.Name})
+				storeRows = append(storeRows, catRows...)
+			}
+		}
+
+		if len(storeRows) > 0 {
+			ret = append(ret, row{kind: rowStore, name: store.Name})
+			ret = append(ret, storeRows...)
+		}
+	}
+
+	return ret
+}
+
+// ANSI escape sequences used to draw the screen.
+const (
+	ansiHome       = "\x1b[H"
+	ansiClearLine  = "\x1b[K"
+	ansiClearBelow = "\x1b[J"
+	ansiHideCursor = "\x1b[?25l"
+	ansiShowCursor = "\x1b[?25h"
+	ansiAltScreen  = "\x1b[?1049h"
+	ansiMainScreen = "\x1b[?1049l"
+	ansiReset      = "\x1b[0m"
+	ansiBold       = "\x1b[1m"
+	ansiDim        = "\x1b[2m"
+	ansiReverse    = "\x1b[7m"
+	ansiCyan       = "\x1b[36m"
+	ansiRed        = "\x1b[31m"
+)
+
+// help is shown in the footer while browsing.
+const help = "↑/↓ move  space check off  a add  r reload  q quit"
+
+// render draws the whole screen. It overwrites the previous frame in place
+// rather than clearing it, which avoids flicker.
+func (a *app) render(w io.Writer) {
+	var b strings.Builder
+	b.WriteString(ansiHome)
+
+	// Title
+	total, done := a.counts()
+	title := fmt.Sprintf(" Groceries  %d items, %d in the cart", total, done)
+	if !a.connected {
+		title += "  (offline)"
+	}
+	a.line(&b, ansiReverse+ansiBold, title, true)
+
+	// List
+	height := a.bodyHeight()
+	a.scroll(height)
+	for i := a.offset; i < a.offset+height; i++ {
+		if i >= len(a.rows) {
+			if i == 0 && !a.loading {
+				a.line(&b, ansiDim, "  The list is empty. Press a to add an item.", false)
+			} else {
+				a.line(&b, "", "", false)
+			}
+			continue
+		}
+
+		r := a.rows[i]
+		switch r.kind {
+		case rowStore:
+			a.line(&b, ansiBold, r.name, false)
+		case rowCategory:
+			a.line(&b, ansiCyan, "  "+r.name, false)
+		case rowItem:
+			style := ""
+			if r.item.List.Done {
+				style = ansiDim
+			}
+			if i == a.cursor && a.mode == modeBrowse {
+				style += ansiReverse
+			}
+			a.line(&b, style, "    "+itemText(r.item), i == a.cursor)
+		}
+	}
+
+	// Status
+	if a.statusErr {
+		a.line(&b, ansiRed, a.status, false)
+	} else {
+		a.line(&b, ansiDim, a.status, false)
+	}
+
+	// Footer
+	if a.mode == modeAdd {
+		b.WriteString("Add: " + string(a.input) + ansiClearLine + ansiShowCursor)
+	} else {
+		b.WriteString(ansiDim + truncate(help, a.width) + ansiReset + ansiClearLine + ansiHideCursor)
+	}
+	b.WriteString(ansiClearBelow)
+
+	io.WriteString(w, b.String())
+}
+
+// line writes a line of the screen in the given style, optionally padding it
+// to the full width so that highlighting spans the screen.
+func (a *app) line(b *strings.Builder, style, text string, fill bool) {
+	text = truncate(text, a.width)
+	if fill {
+		text += strings.Repeat(" ", max(0, a.width-len([]rune(text))))
+	}
+
+	b.WriteString(style + text + ansiReset + ansiClearLine + "\r\n")
+}
+
+func itemText(item sdk.Item) string {
+	check := "[ ]"
+	if item.List.Done {
+		check = "[x]"
+	}
+
+	text := check + " " + item.Name
+	if item.List.Quantity != "" {
+		text += " (" + item.List.Quantity + ")"
+	}
+	return text
+}
+
+// truncate shortens s to at most width characters.
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if width <= 0 || len(r) <= width {
+		return s
+	}
+	return string(r[:width])
+}
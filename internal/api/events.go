@@ -0,0 +1,19 @@
+package api
+
+const (
+	// sseEventList mirrors the web server's event for items added to or
+	// removed from the list.
+	sseEventList = "list"
+
+	// sseEventCart mirrors the web server's event for items added to or
+	// removed from the cart.
+	sseEventCart = "cart"
+
+	// sseEventCategory mirrors the web server's event for categories added or
+	// removed.
+	sseEventCategory = "category"
+
+	// sseEventExpiring mirrors the web server's event for pantry items nearing
+	// their best-before date.
+	sseEventExpiring = "expiring"
+)
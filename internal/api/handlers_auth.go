@@ -27,14 +27,14 @@ func (s *Server) authLoginHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := authz.ValidateCredentials(req.Password); err != nil {
-		errorJSON(w, http.StatusUnauthorized, "invalid credentials")
+		errorJSON(w, http.StatusUnauthorized, codeInvalidCredentials, "invalid credentials")
 		return
 	}
 
-	user, err := s.db.GetUserByName(r.Context(), req.Username)
+	user, err := s.repo.GetUserByName(r.Context(), req.Username)
 	if err != nil {
 		// Don't leak whether the user exists vs password was wrong
-		errorJSON(w, http.StatusUnauthorized, "invalid credentials")
+		errorJSON(w, http.StatusUnauthorized, codeInvalidCredentials, "invalid credentials")
 		return
 	}
 
@@ -65,15 +65,15 @@ func (s *Server) authLogoutHandler(w http.ResponseWriter, r *http.Request) {
 func (s *Server) authMeHandler(w http.ResponseWriter, r *http.Request) {
 	user := userFromContext(r.Context())
 	if user == nil {
-		errorJSON(w, http.StatusUnauthorized, "not authenticated")
+		errorJSON(w, http.StatusUnauthorized, codeUnauthorized, "not authenticated")
 		return
 	}
 
 	// Re-fetch to ensure freshness
-	fresh, err := s.db.GetUser(r.Context(), user.ID)
+	fresh, err := s.repo.GetUser(r.Context(), user.ID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			errorJSON(w, http.StatusUnauthorized, "user no longer exists")
+			errorJSON(w, http.StatusUnauthorized, codeUnauthorized, "user no longer exists")
 			return
 		}
 		internalError(w, err)
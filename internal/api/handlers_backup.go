@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/taiidani/groceries/internal/backup"
+)
+
+// maxRestoreSize bounds the size of an uploaded archive.
+const maxRestoreSize = 32 << 20
+
+// backupHandler downloads the whole database as a backup archive.
+func (s *Server) backupHandler(w http.ResponseWriter, r *http.Request) {
+	archive, err := backup.Create(r.Context(), s.repo)
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("groceries-%s.json", archive.CreatedAt.Format("2006-01-02"))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	writeJSON(w, http.StatusOK, archive)
+}
+
+// restoreHandler loads a backup archive, merging it into the existing data.
+func (s *Server) restoreHandler(w http.ResponseWriter, r *http.Request) {
+	archive, err := backup.Decode(http.MaxBytesReader(w, r.Body, maxRestoreSize))
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	summary, err := backup.Restore(r.Context(), s.repo, archive)
+	if errors.Is(err, backup.ErrInvalidArchive) {
+		badRequest(w, err.Error())
+		return
+	} else if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	s.sseServer.Publish(r.Context(), sseEventList, nil)
+	writeJSON(w, http.StatusOK, summary)
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/taiidani/groceries/internal/db/models"
+	legacy "github.com/taiidani/groceries/internal/models"
+	"github.com/taiidani/groceries/internal/products"
+)
+
+func (s *Server) itemsByBarcodeHandler(w http.ResponseWriter, r *http.Request) {
+	code, err := models.NormalizeBarcode(r.PathValue("code"))
+	if err != nil {
+		validationFailed(w, err)
+		return
+	}
+
+	item, err := s.repo.ItemByBarcode(r.Context(), code)
+	if err == nil {
+		writeJSON(w, http.StatusOK, barcodeLookupJSON{Code: code, Item: &item})
+		return
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		internalError(w, err)
+		return
+	}
+
+	// Not one of ours yet, so see whether the lookup provider recognises it
+	product, err := s.products.Lookup(r.Context(), code)
+	if err != nil {
+		productLookupError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, barcodeLookupJSON{Code: code, Product: &product})
+}
+
+func (s *Server) itemBarcodeAddHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := parseId(r.PathValue("id"))
+	if err != nil {
+		badRequest(w, "id must be an integer")
+		return
+	}
+
+	if _, err := s.repo.GetItem(r.Context(), int(id)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			notFound(w, "item")
+		} else {
+			internalError(w, err)
+		}
+		return
+	}
+
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequest(w, "invalid request body")
+		return
+	}
+
+	code, err := models.NormalizeBarcode(req.Code)
+	if err != nil {
+		validationFailed(w, err)
+		return
+	}
+
+	barcode, created, err := s.repo.AssignBarcode(r.Context(), id, code)
+	if errors.Is(err, legacy.ErrBarcodeAssigned) {
+		conflict(w, codeBarcodeAssigned, err.Error())
+		return
+	} else if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, barcodeToJSON(barcode))
+}
+
+func (s *Server) itemBarcodeDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := parseId(r.PathValue("id"))
+	if err != nil {
+		badRequest(w, "id must be an integer")
+		return
+	}
+
+	code, err := models.NormalizeBarcode(r.PathValue("code"))
+	if err != nil {
+		validationFailed(w, err)
+		return
+	}
+
+	barcode, err := s.repo.GetBarcode(r.Context(), code)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && barcode.ItemID != id) {
+		notFound(w, "barcode")
+		return
+	} else if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	if err := s.repo.RemoveBarcode(r.Context(), id, code); err != nil {
+		internalError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// productLookupError reports a failed product lookup. Provider outages are
+// surfaced as a bad gateway so clients can fall back to manual entry.
+func productLookupError(w http.ResponseWriter, err error) {
+	if errors.Is(err, products.ErrNotFound) {
+		notFound(w, "product")
+		return
+	}
+
+	slog.Error("product lookup failed", "error", err)
+	errorJSON(w, http.StatusBadGateway, codeUpstreamUnavailable, "product lookup is unavailable")
+}
+
+// ---------------------------------------------------------------------------
+// JSON representation helpers
+// ---------------------------------------------------------------------------
+
+type barcodeJSON struct {
+	ItemID int32  `json:"item_id"`
+	Code   string `json:"code"`
+}
+
+func barcodeToJSON(b models.ItemBarcode) barcodeJSON {
+	return barcodeJSON{ItemID: b.ItemID, Code: b.Code}
+}
+
+// barcodeLookupJSON is the result of resolving a barcode. Item is set when the
+// barcode belongs to a known item; otherwise Product holds the details reported
+// by the lookup provider.
+type barcodeLookupJSON struct {
+	Code    string            `json:"code"`
+	Item    *legacy.Item      `json:"item"`
+	Product *products.Product `json:"product"`
+}
@@ -7,17 +7,26 @@ import (
 	"net/http"
 	"strconv"
 
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
 	"github.com/taiidani/groceries/internal/models"
 )
 
 func (s *Server) categoriesListHandler(w http.ResponseWriter, r *http.Request) {
-	categories, err := models.LoadCategories(r.Context())
+	page, err := parsePage(r)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	categories, err := s.repo.LoadCategoriesPage(r.Context(), page.query())
 	if err != nil {
 		internalError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, categories)
+	writePage(w, page, categories, func(c models.Category) models.PageKey {
+		return models.PageKey{Name: c.Name, ID: c.ID}
+	})
 }
 
 func (s *Server) categoriesGetHandler(w http.ResponseWriter, r *http.Request) {
@@ -27,7 +36,7 @@ func (s *Server) categoriesGetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	category, err := models.GetCategory(r.Context(), id)
+	category, err := s.repo.GetCategory(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			notFound(w, "category")
@@ -37,7 +46,7 @@ func (s *Server) categoriesGetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	items, err := category.Items(r.Context())
+	items, err := s.repo.CategoryItems(r.Context(), category.ID)
 	if err != nil {
 		internalError(w, err)
 		return
@@ -48,6 +57,7 @@ func (s *Server) categoriesGetHandler(w http.ResponseWriter, r *http.Request) {
 		Items []models.Item `json:"items"`
 	}
 
+	setETag(w, etag(category.Version))
 	writeJSON(w, http.StatusOK, response{
 		Category: category,
 		Items:    items,
@@ -67,11 +77,11 @@ func (s *Server) categoriesCreateHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	if body.Name == "" {
-		badRequest(w, "name is required")
+		invalidField(w, "name", dbmodels.ErrRequired, "name is required")
 		return
 	}
 	if body.StoreID == 0 {
-		badRequest(w, "store_id is required")
+		invalidField(w, "store_id", dbmodels.ErrRequired, "store_id is required")
 		return
 	}
 
@@ -81,27 +91,25 @@ func (s *Server) categoriesCreateHandler(w http.ResponseWriter, r *http.Request)
 		Description: body.Description,
 	}
 
-	if err := models.AddCategory(r.Context(), cat); err != nil {
+	id, err := s.repo.AddCategory(r.Context(), cat)
+	if isValidation(err) {
+		validationFailed(w, err)
+		return
+	} else if err != nil {
 		internalError(w, err)
 		return
 	}
 
-	// Reload to get the generated ID and item_count
-	categories, err := models.LoadCategories(r.Context())
+	s.sseServer.Publish(r.Context(), sseEventCategory, nil)
+
+	created, err := s.repo.GetCategory(r.Context(), id)
 	if err != nil {
 		internalError(w, err)
 		return
 	}
 
-	for _, c := range categories {
-		if c.Name == body.Name && c.StoreID == body.StoreID {
-			writeJSON(w, http.StatusCreated, c)
-			return
-		}
-	}
-
-	// Fallback: return the struct we built (ID will be zero)
-	writeJSON(w, http.StatusCreated, cat)
+	setETag(w, etag(created.Version))
+	writeJSON(w, http.StatusCreated, created)
 }
 
 func (s *Server) categoriesUpdateHandler(w http.ResponseWriter, r *http.Request) {
@@ -111,7 +119,7 @@ func (s *Server) categoriesUpdateHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	existing, err := models.GetCategory(r.Context(), id)
+	existing, err := s.repo.GetCategory(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			notFound(w, "category")
@@ -133,11 +141,11 @@ func (s *Server) categoriesUpdateHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	if body.Name == "" {
-		badRequest(w, "name is required")
+		invalidField(w, "name", dbmodels.ErrRequired, "name is required")
 		return
 	}
 	if body.StoreID == 0 {
-		badRequest(w, "store_id is required")
+		invalidField(w, "store_id", dbmodels.ErrRequired, "store_id is required")
 		return
 	}
 
@@ -145,20 +153,54 @@ func (s *Server) categoriesUpdateHandler(w http.ResponseWriter, r *http.Request)
 	existing.Name = body.Name
 	existing.Description = body.Description
 
-	if err := models.EditCategory(r.Context(), existing); err != nil {
-		internalError(w, err)
+	err = s.repo.InTx(r.Context(), func(tx *models.Repository) error {
+		version, err := tx.LockCategoryVersion(r.Context(), id)
+		if err != nil {
+			return err
+		}
+		if err := checkIfMatch(r, etag(version)); err != nil {
+			return err
+		}
+
+		return tx.EditCategory(r.Context(), existing)
+	})
+	if err != nil {
+		if errors.Is(err, errPreconditionFailed) {
+			s.categoryPreconditionFailed(w, r, id)
+		} else if isValidation(err) {
+			validationFailed(w, err)
+		} else if errors.Is(err, sql.ErrNoRows) {
+			notFound(w, "category")
+		} else {
+			internalError(w, err)
+		}
 		return
 	}
 
-	updated, err := models.GetCategory(r.Context(), id)
+	s.sseServer.Publish(r.Context(), sseEventCategory, nil)
+
+	updated, err := s.repo.GetCategory(r.Context(), id)
 	if err != nil {
 		internalError(w, err)
 		return
 	}
 
+	setETag(w, etag(updated.Version))
 	writeJSON(w, http.StatusOK, updated)
 }
 
+// categoryPreconditionFailed answers a stale category update with the
+// category's current state.
+func (s *Server) categoryPreconditionFailed(w http.ResponseWriter, r *http.Request, id int) {
+	current, err := s.repo.GetCategory(r.Context(), id)
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	preconditionFailed(w, etag(current.Version), current)
+}
+
 func (s *Server) categoriesDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
@@ -166,7 +208,7 @@ func (s *Server) categoriesDeleteHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if _, err := models.GetCategory(r.Context(), id); err != nil {
+	if _, err := s.repo.GetCategory(r.Context(), id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			notFound(w, "category")
 		} else {
@@ -175,14 +217,16 @@ func (s *Server) categoriesDeleteHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := models.DeleteCategory(r.Context(), id); err != nil {
-		if err.Error() == "category is still in use" {
-			conflict(w, err.Error())
+	if err := s.repo.DeleteCategory(r.Context(), id); err != nil {
+		if errors.Is(err, models.ErrCategoryInUse) {
+			conflict(w, codeInUse, err.Error())
 		} else {
 			internalError(w, err)
 		}
 		return
 	}
 
+	s.sseServer.Publish(r.Context(), sseEventCategory, nil)
+
 	w.WriteHeader(http.StatusNoContent)
 }
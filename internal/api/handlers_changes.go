@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/taiidani/groceries/internal/models"
+)
+
+const (
+	// defaultChangesLimit and maxChangesLimit bound how many entities a
+	// single read of the change feed returns.
+	defaultChangesLimit = 500
+	maxChangesLimit     = 1000
+)
+
+// changesHandler returns the entities created, updated or deleted since a
+// cursor, so that clients can keep a local copy of the catalog and list
+// without reloading them whenever something changes. A client starts from
+// cursor 0, which returns everything, and passes the returned cursor to the
+// next request.
+func (s *Server) changesHandler(w http.ResponseWriter, r *http.Request) {
+	var since int64
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		var err error
+		since, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || since < 0 {
+			badRequest(w, "since must be a non-negative integer")
+			return
+		}
+	}
+
+	limit := defaultChangesLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		var err error
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxChangesLimit {
+			badRequest(w, fmt.Sprintf("limit must be between 1 and %d", maxChangesLimit))
+			return
+		}
+	}
+
+	page, err := s.changesSince(r.Context(), since, limit)
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// changesSince returns the current state of up to limit entities changed
+// after the cursor, along with the cursor that follows them.
+func (s *Server) changesSince(ctx context.Context, cursor int64, limit int) (changesPageJSON, error) {
+	// One extra change tells whether there is another page
+	changes, err := s.repo.ChangesSince(ctx, cursor, limit+1)
+	if err != nil {
+		return changesPageJSON{}, err
+	}
+
+	ret := changesPageJSON{
+		Changes: make([]changeJSON, 0, min(len(changes), limit)),
+		Cursor:  cursor,
+		HasMore: len(changes) > limit,
+	}
+	if ret.HasMore {
+		changes = changes[:limit]
+	}
+
+	if len(changes) == 0 {
+		return ret, nil
+	}
+
+	changed, err := s.repo.LoadChanged(ctx, cursor, changes[len(changes)-1].Seq)
+	if err != nil {
+		return changesPageJSON{}, err
+	}
+
+	for _, change := range changes {
+		out := changeJSON{
+			Seq:    change.Seq,
+			Entity: change.Entity,
+			ID:     change.EntityID,
+			Op:     change.Op,
+		}
+		if change.Op != models.ChangeDelete {
+			var found bool
+			out.Data, found, err = changeData(changed, change)
+			if err != nil {
+				return changesPageJSON{}, err
+			} else if !found {
+				// Deleted after the change log was read. The tombstone is
+				// recorded beyond the cursor and will be sent again.
+				out.Op = models.ChangeDelete
+			}
+		}
+
+		ret.Changes = append(ret.Changes, out)
+		ret.Cursor = change.Seq
+	}
+
+	return ret, nil
+}
+
+// changeData picks the current state of a changed entity out of those loaded
+// for the page, in the same representation the entity's own endpoint returns.
+func changeData(changed models.ChangedEntities, change models.Change) (any, bool, error) {
+	switch change.Entity {
+	case models.EntityItem:
+		item, ok := changed.Items[change.EntityID]
+		return item, ok, nil
+	case models.EntityCategory:
+		category, ok := changed.Categories[change.EntityID]
+		return category, ok, nil
+	case models.EntityStore:
+		store, ok := changed.Stores[change.EntityID]
+		return store, ok, nil
+	case models.EntityListItem:
+		item, ok := changed.Items[change.EntityID]
+		if !ok || item.List == nil {
+			return nil, false, nil
+		}
+		return listItemToJSON(item), true, nil
+	}
+
+	return nil, false, fmt.Errorf("unknown entity %q in the change log", change.Entity)
+}
+
+// ---------------------------------------------------------------------------
+// JSON representation helpers
+// ---------------------------------------------------------------------------
+
+type changesPageJSON struct {
+	Changes []changeJSON `json:"changes"`
+	Cursor  int64        `json:"cursor"`
+	HasMore bool         `json:"has_more"`
+}
+
+type changeJSON struct {
+	Seq    int64           `json:"seq"`
+	Entity string          `json:"entity"`
+	ID     int             `json:"id"`
+	Op     models.ChangeOp `json:"op"`
+	Data   any             `json:"data,omitempty"`
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/taiidani/groceries/internal/events"
+)
+
+// eventsPingInterval is how often a ping is sent to keep idle streams open.
+const eventsPingInterval = 15 * time.Second
+
+// eventsHandler streams change notifications as Server-Sent Events, so that
+// clients can reload the data they display. Events carry no payload.
+func (s *Server) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := w.(http.Flusher); !ok {
+		internalError(w, errors.New("streaming is not supported"))
+		return
+	}
+
+	w.Header().Add("Content-Type", "text/event-stream")
+	w.Header().Add("Cache-Control", "no-cache")
+
+	sub := s.sseServer.Subscribe(r.Context(),
+		sseEventList,
+		sseEventCart,
+		sseEventCategory,
+		sseEventExpiring,
+	)
+
+	ping := time.NewTicker(eventsPingInterval)
+	defer ping.Stop()
+
+	// Send an initial ping so clients know the subscription is established
+	evt := events.Event{Event: "ping"}
+	evt.Write(w)
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			evt := events.Event{Event: "close"}
+			evt.Write(w)
+			return
+		case <-r.Context().Done():
+			slog.DebugContext(r.Context(), "API event stream closed")
+			return
+		case evt := <-sub:
+			evt.Write(w)
+		case <-ping.C:
+			evt := events.Event{Event: "ping"}
+			evt.Write(w)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+	"github.com/taiidani/groceries/internal/models"
+)
+
+const (
+	exportFormatMarkdown = "markdown"
+	exportFormatCSV      = "csv"
+	exportFormatJSON     = "json"
+	exportFormatHTML     = "html"
+)
+
+// listExportPage is the print-optimized layout of the list export, which the
+// web UI also serves as its print view.
+//
+//go:embed list_export.html
+var listExportPage string
+
+var listExportTemplate = template.Must(template.New("list_export").Parse(listExportPage))
+
+// listExportHandler exports the shopping list grouped by store and category,
+// as a Markdown checklist, CSV, JSON or a printable HTML page. The Markdown and
+// CSV exports can be imported again through listImportHandler.
+func (s *Server) listExportHandler(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = exportFormatMarkdown
+	}
+
+	// Like the web UI, the printable page only lists what is still to be
+	// bought
+	stores, err := s.loadListByStore(r.Context(), format != exportFormatHTML)
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	switch format {
+	case exportFormatMarkdown:
+		buf := bytes.Buffer{}
+		buf.WriteString("# Shopping list\n")
+		for _, store := range stores {
+			fmt.Fprintf(&buf, "\n## %s\n", store.Name)
+			for _, category := range store.Categories {
+				fmt.Fprintf(&buf, "\n### %s\n\n", category.Name)
+				for _, item := range category.Items {
+					check := " "
+					if item.Done {
+						check = "x"
+					}
+					if item.Quantity != "" {
+						fmt.Fprintf(&buf, "- [%s] %s %s\n", check, item.Quantity, item.Name)
+					} else {
+						fmt.Fprintf(&buf, "- [%s] %s\n", check, item.Name)
+					}
+				}
+			}
+		}
+		writeExport(w, "text/markdown; charset=utf-8", "groceries-list.md", buf.Bytes())
+
+	case exportFormatCSV:
+		rows := [][]string{{"store", "category", "item", "quantity", "done"}}
+		for _, store := range stores {
+			for _, category := range store.Categories {
+				for _, item := range category.Items {
+					rows = append(rows, []string{store.Name, category.Name, item.Name, item.Quantity, strconv.FormatBool(item.Done)})
+				}
+			}
+		}
+		writeCSVExport(w, "groceries-list.csv", rows)
+
+	case exportFormatJSON:
+		w.Header().Set("Content-Disposition", `attachment; filename="groceries-list.json"`)
+		writeJSON(w, http.StatusOK, struct {
+			ExportedAt time.Time         `json:"exported_at"`
+			Stores     []exportStoreJSON `json:"stores"`
+		}{ExportedAt: time.Now().UTC(), Stores: stores})
+
+	case exportFormatHTML:
+		buf := bytes.Buffer{}
+		err := listExportTemplate.Execute(&buf, struct {
+			Date   time.Time
+			Stores []exportStoreJSON
+		}{Date: time.Now(), Stores: stores})
+		if err != nil {
+			internalError(w, err)
+			return
+		}
+		writeExport(w, "text/html; charset=utf-8", "groceries-list.html", buf.Bytes())
+
+	default:
+		badRequest(w, "format must be one of markdown, csv, json or html")
+	}
+}
+
+// catalogExportHandler exports every store, category and item, whether or not
+// it is on the shopping list, as JSON or CSV.
+func (s *Server) catalogExportHandler(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = exportFormatJSON
+	}
+
+	stores, err := s.repo.LoadStores(r.Context())
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	categories, err := s.repo.LoadCategories(r.Context())
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	items, err := s.repo.LoadItems(r.Context())
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	switch format {
+	case exportFormatJSON:
+		catalogItems := make([]catalogItemJSON, 0, len(items))
+		for _, item := range items {
+			catalogItems = append(catalogItems, catalogItemJSON{
+				ID:         item.ID,
+				Name:       item.Name,
+				CategoryID: item.CategoryID,
+			})
+		}
+
+		w.Header().Set("Content-Disposition", `attachment; filename="groceries-catalog.json"`)
+		writeJSON(w, http.StatusOK, struct {
+			ExportedAt time.Time           `json:"exported_at"`
+			Stores     []dbmodels.Store    `json:"stores"`
+			Categories []dbmodels.Category `json:"categories"`
+			Items      []catalogItemJSON   `json:"items"`
+		}{ExportedAt: time.Now().UTC(), Stores: stores, Categories: categories, Items: catalogItems})
+
+	case exportFormatCSV:
+		storeNames := map[int32]string{}
+		for _, store := range stores {
+			storeNames[store.ID] = store.Name
+		}
+		categoryStores := map[int]string{}
+		for _, category := range categories {
+			categoryStores[int(category.ID)] = storeNames[category.StoreID]
+		}
+
+		rows := [][]string{{"store", "category", "item"}}
+		for _, item := range items {
+			rows = append(rows, []string{categoryStores[item.CategoryID], item.CategoryName(), item.Name})
+		}
+		writeCSVExport(w, "groceries-catalog.csv", rows)
+
+	default:
+		badRequest(w, "format must be one of json or csv")
+	}
+}
+
+// loadListByStore groups the shopping list by store and category, as
+// groupListByStore does.
+func (s *Server) loadListByStore(ctx context.Context, includeDone bool) ([]exportStoreJSON, error) {
+	stores, err := s.repo.LoadStores(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.repo.LoadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.LoadList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupListByStore(stores, categories, items, includeDone), nil
+}
+
+// groupListByStore groups the listed items by store and category, omitting
+// stores and categories with nothing on the list. Items already bought are
+// left out unless includeDone is set.
+func groupListByStore(stores []dbmodels.Store, categories []dbmodels.Category, items []models.Item, includeDone bool) []exportStoreJSON {
+	ret := []exportStoreJSON{}
+	for _, store := range stores {
+		addStore := exportStoreJSON{Name: store.Name}
+
+		for _, category := range categories {
+			if category.StoreID != store.ID {
+				continue
+			}
+
+			addCategory := exportCategoryJSON{Name: category.Name}
+			for _, item := range items {
+				if item.CategoryID != int(category.ID) || item.List == nil {
+					continue
+				}
+				if item.List.Done && !includeDone {
+					continue
+				}
+
+				addCategory.Items = append(addCategory.Items, exportItemJSON{
+					ItemID:   item.ID,
+					Name:     item.Name,
+					Quantity: item.List.Quantity,
+					Done:     item.List.Done,
+				})
+			}
+
+			if len(addCategory.Items) > 0 {
+				addStore.Categories = append(addStore.Categories, addCategory)
+			}
+		}
+
+		if len(addStore.Categories) > 0 {
+			ret = append(ret, addStore)
+		}
+	}
+
+	return ret
+}
+
+// writeExport writes an export as a file download.
+func writeExport(w http.ResponseWriter, contentType, filename string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+func writeCSVExport(w http.ResponseWriter, filename string, rows [][]string) {
+	buf := bytes.Buffer{}
+	if err := csv.NewWriter(&buf).WriteAll(rows); err != nil {
+		internalError(w, err)
+		return
+	}
+	writeExport(w, "text/csv; charset=utf-8", filename, buf.Bytes())
+}
+
+// ---------------------------------------------------------------------------
+// JSON representation helpers
+// ---------------------------------------------------------------------------
+
+type exportStoreJSON struct {
+	Name       string               `json:"name"`
+	Categories []exportCategoryJSON `json:"categories"`
+}
+
+type exportCategoryJSON struct {
+	Name  string           `json:"name"`
+	Items []exportItemJSON `json:"items"`
+}
+
+type exportItemJSON struct {
+	ItemID   int    `json:"item_id"`
+	Name     string `json:"name"`
+	Quantity string `json:"quantity"`
+	Done     bool   `json:"done"`
+}
+
+type catalogItemJSON struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	CategoryID int    `json:"category_id"`
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+	"github.com/taiidani/groceries/internal/models"
+)
+
+func TestGroupListByStore(t *testing.T) {
+	stores := []dbmodels.Store{{ID: 1, Name: "Costco"}, {ID: 2, Name: "Corner shop"}, {ID: 3, Name: "Bakery"}}
+	categories := []dbmodels.Category{
+		{ID: 10, StoreID: 1, Name: "Produce"},
+		{ID: 11, StoreID: 1, Name: "Bakery"},
+		{ID: 12, StoreID: 2, Name: "Dairy"},
+		{ID: 13, StoreID: 3, Name: "Bread"},
+	}
+	items := []models.Item{
+		{ID: 1, Name: "Apples", CategoryID: 10, List: &models.ListItem{Done: true}},
+		{ID: 2, Name: "Bananas", CategoryID: 10, List: &models.ListItem{Quantity: "6"}},
+		{ID: 3, Name: "Bread", CategoryID: 11, List: &models.ListItem{Done: true}},
+		{ID: 4, Name: "Milk", CategoryID: 12, List: &models.ListItem{Done: true}},
+		{ID: 5, Name: "Bagels", CategoryID: 13},
+	}
+
+	tests := []struct {
+		name        string
+		includeDone bool
+		want        []exportStoreJSON
+	}{
+		{
+			name: "without done items",
+			want: []exportStoreJSON{
+				{Name: "Costco", Categories: []exportCategoryJSON{
+					{Name: "Produce", Items: []exportItemJSON{{ItemID: 2, Name: "Bananas", Quantity: "6"}}},
+				}},
+			},
+		},
+		{
+			name:        "with done items",
+			includeDone: true,
+			want: []exportStoreJSON{
+				{Name: "Costco", Categories: []exportCategoryJSON{
+					{Name: "Produce", Items: []exportItemJSON{
+						{ItemID: 1, Name: "Apples", Done: true},
+						{ItemID: 2, Name: "Bananas", Quantity: "6"},
+					}},
+					{Name: "Bakery", Items: []exportItemJSON{{ItemID: 3, Name: "Bread", Done: true}}},
+				}},
+				{Name: "Corner shop", Categories: []exportCategoryJSON{
+					{Name: "Dairy", Items: []exportItemJSON{{ItemID: 4, Name: "Milk", Done: true}}},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := groupListByStore(stores, categories, items, tt.includeDone); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groupListByStore() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,11 +6,11 @@ import (
 	"errors"
 	"net/http"
 
-	"github.com/taiidani/groceries/internal/db/models"
+	legacy "github.com/taiidani/groceries/internal/models"
 )
 
 func (s *Server) groupsListHandler(w http.ResponseWriter, r *http.Request) {
-	groups, err := s.db.ListGroups(r.Context())
+	groups, err := s.repo.LoadGroups(r.Context())
 	if err != nil {
 		internalError(w, err)
 		return
@@ -26,7 +26,7 @@ func (s *Server) groupsGetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	group, err := s.db.GetGroup(r.Context(), id)
+	group, err := s.repo.GetGroup(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			notFound(w, "group")
@@ -49,14 +49,11 @@ func (s *Server) groupsCreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	group := models.Group{Name: req.Name}
-	if err := s.db.ValidateGroup(r.Context(), group); err != nil {
-		badRequest(w, err.Error())
+	group, err := s.repo.AddGroup(r.Context(), req.Name)
+	if isValidation(err) {
+		validationFailed(w, err)
 		return
-	}
-
-	group, err = s.db.CreateGroup(r.Context(), req.Name)
-	if err != nil {
+	} else if err != nil {
 		internalError(w, err)
 		return
 	}
@@ -71,16 +68,6 @@ func (s *Server) groupsUpdateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	existing, err := s.db.GetGroup(r.Context(), id)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			notFound(w, "group")
-		} else {
-			internalError(w, err)
-		}
-		return
-	}
-
 	var req struct {
 		Name string `json:"name"`
 	}
@@ -89,18 +76,15 @@ func (s *Server) groupsUpdateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	existing.Name = req.Name
-	if err := s.db.ValidateGroup(r.Context(), existing); err != nil {
-		badRequest(w, err.Error())
-		return
-	}
-
-	group, err := s.db.UpdateGroup(r.Context(), models.UpdateGroupParams{
-		ID:   id,
-		Name: req.Name,
-	})
+	group, err := s.repo.RenameGroup(r.Context(), id, req.Name)
 	if err != nil {
-		internalError(w, err)
+		if isValidation(err) {
+			validationFailed(w, err)
+		} else if errors.Is(err, sql.ErrNoRows) {
+			notFound(w, "group")
+		} else {
+			internalError(w, err)
+		}
 		return
 	}
 
@@ -114,7 +98,7 @@ func (s *Server) groupsDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, err := s.db.GetGroup(r.Context(), id); err != nil {
+	if _, err := s.repo.GetGroup(r.Context(), id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			notFound(w, "group")
 		} else {
@@ -123,9 +107,12 @@ func (s *Server) groupsDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.db.DeleteGroup(r.Context(), id); err != nil {
-		// DeleteGroup returns a descriptive error when the group is still in use
-		conflict(w, err.Error())
+	if err := s.repo.DeleteGroup(r.Context(), id); err != nil {
+		if errors.Is(err, legacy.ErrGroupInUse) {
+			conflict(w, codeInUse, err.Error())
+		} else {
+			internalError(w, err)
+		}
 		return
 	}
 
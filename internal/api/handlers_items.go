@@ -4,20 +4,27 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
 	"github.com/taiidani/groceries/internal/models"
 )
 
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+)
+
 func (s *Server) itemsListHandler(w http.ResponseWriter, r *http.Request) {
-	items, err := models.LoadItems(r.Context())
+	page, err := parsePage(r)
 	if err != nil {
-		internalError(w, err)
+		badRequest(w, err.Error())
 		return
 	}
 
-	// Apply query filters
+	query := models.ItemQuery{PageQuery: page.query()}
 	q := r.URL.Query()
 
 	if rawID := q.Get("category_id"); rawID != "" {
@@ -26,13 +33,7 @@ func (s *Server) itemsListHandler(w http.ResponseWriter, r *http.Request) {
 			badRequest(w, "category_id must be an integer")
 			return
 		}
-		filtered := items[:0]
-		for _, item := range items {
-			if item.CategoryID == categoryID {
-				filtered = append(filtered, item)
-			}
-		}
-		items = filtered
+		query.CategoryID = &categoryID
 	}
 
 	if rawInList := q.Get("in_list"); rawInList != "" {
@@ -41,18 +42,46 @@ func (s *Server) itemsListHandler(w http.ResponseWriter, r *http.Request) {
 			badRequest(w, "in_list must be a boolean")
 			return
 		}
-		filtered := items[:0]
-		for _, item := range items {
-			if inList && item.List != nil {
-				filtered = append(filtered, item)
-			} else if !inList && item.List == nil {
-				filtered = append(filtered, item)
-			}
+		query.InList = &inList
+	}
+
+	items, err := s.repo.LoadItemsPage(r.Context(), query)
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	writePage(w, page, items, func(item models.Item) models.PageKey {
+		return models.PageKey{Name: item.Name, ID: item.ID}
+	})
+}
+
+func (s *Server) itemsSearchHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	query := q.Get("q")
+	if query == "" {
+		badRequest(w, "q is required")
+		return
+	}
+
+	limit := defaultSearchLimit
+	if rawLimit := q.Get("limit"); rawLimit != "" {
+		var err error
+		limit, err = strconv.Atoi(rawLimit)
+		if err != nil || limit < 1 || limit > maxSearchLimit {
+			badRequest(w, fmt.Sprintf("limit must be an integer between 1 and %d", maxSearchLimit))
+			return
 		}
-		items = filtered
 	}
 
-	writeJSON(w, http.StatusOK, items)
+	matches, err := s.repo.SearchItems(r.Context(), query, limit)
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, itemMatchesToJSON(matches))
 }
 
 func (s *Server) itemsCreateHandler(w http.ResponseWriter, r *http.Request) {
@@ -65,11 +94,11 @@ func (s *Server) itemsCreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if req.Name == "" {
-		badRequest(w, "name is required")
+		invalidField(w, "name", dbmodels.ErrRequired, "name is required")
 		return
 	}
 	if req.CategoryID == 0 {
-		badRequest(w, "category_id is required")
+		invalidField(w, "category_id", dbmodels.ErrRequired, "category_id is required")
 		return
 	}
 
@@ -78,17 +107,22 @@ func (s *Server) itemsCreateHandler(w http.ResponseWriter, r *http.Request) {
 		Name:       req.Name,
 	}
 
-	if err := models.AddItem(r.Context(), newItem); err != nil {
+	id, err := s.repo.AddItem(r.Context(), newItem)
+	if isValidation(err) {
+		validationFailed(w, err)
+		return
+	} else if err != nil {
 		internalError(w, err)
 		return
 	}
 
-	created, err := models.GetItemByName(r.Context(), req.Name)
+	created, err := s.repo.GetItem(r.Context(), id)
 	if err != nil {
 		internalError(w, err)
 		return
 	}
 
+	setETag(w, etag(created.Version))
 	writeJSON(w, http.StatusCreated, created)
 }
 
@@ -99,7 +133,7 @@ func (s *Server) itemsGetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	item, err := models.GetItem(r.Context(), id)
+	item, err := s.repo.GetItem(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			notFound(w, "item")
@@ -109,6 +143,7 @@ func (s *Server) itemsGetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, etag(item.Version))
 	writeJSON(w, http.StatusOK, item)
 }
 
@@ -119,7 +154,7 @@ func (s *Server) itemsUpdateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	item, err := models.GetItem(r.Context(), id)
+	item, err := s.repo.GetItem(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			notFound(w, "item")
@@ -130,39 +165,135 @@ func (s *Server) itemsUpdateHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		CategoryID int    `json:"category_id"`
-		Name       string `json:"name"`
+		CategoryID int       `json:"category_id"`
+		Name       string    `json:"name"`
+		Aliases    *[]string `json:"aliases"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		badRequest(w, "invalid request body")
 		return
 	}
 	if req.Name == "" {
-		badRequest(w, "name is required")
+		invalidField(w, "name", dbmodels.ErrRequired, "name is required")
 		return
 	}
 	if req.CategoryID == 0 {
-		badRequest(w, "category_id is required")
+		invalidField(w, "category_id", dbmodels.ErrRequired, "category_id is required")
 		return
 	}
 
 	item.Name = req.Name
 	item.CategoryID = req.CategoryID
 
-	if err := models.EditItem(r.Context(), item); err != nil {
-		internalError(w, err)
+	err = s.repo.InTx(r.Context(), func(tx *models.Repository) error {
+		version, err := tx.LockItemVersion(r.Context(), id)
+		if err != nil {
+			return err
+		}
+		if err := checkIfMatch(r, etag(version)); err != nil {
+			return err
+		}
+
+		// Aliases are left untouched unless the request supplies them
+		if req.Aliases != nil {
+			aliases := models.NormalizeAliases(req.Name, *req.Aliases)
+			if err := tx.SetItemAliases(r.Context(), id, aliases); err != nil {
+				return err
+			}
+		}
+
+		return tx.EditItem(r.Context(), item)
+	})
+	if err != nil {
+		if errors.Is(err, errPreconditionFailed) {
+			s.itemPreconditionFailed(w, r, id)
+		} else if isValidation(err) {
+			validationFailed(w, err)
+		} else if errors.Is(err, sql.ErrNoRows) {
+			notFound(w, "item")
+		} else if errors.Is(err, models.ErrAliasConflict) {
+			conflict(w, codeAliasConflict, err.Error())
+		} else {
+			internalError(w, err)
+		}
 		return
 	}
 
-	updated, err := models.GetItem(r.Context(), id)
+	updated, err := s.repo.GetItem(r.Context(), id)
 	if err != nil {
 		internalError(w, err)
 		return
 	}
 
+	setETag(w, etag(updated.Version))
 	writeJSON(w, http.StatusOK, updated)
 }
 
+// itemPreconditionFailed answers a stale item update with the item's current
+// state.
+func (s *Server) itemPreconditionFailed(w http.ResponseWriter, r *http.Request, id int) {
+	current, err := s.repo.GetItem(r.Context(), id)
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	preconditionFailed(w, etag(current.Version), current)
+}
+
+func (s *Server) itemsMergeHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		badRequest(w, "id must be an integer")
+		return
+	}
+
+	var req struct {
+		TargetID int `json:"target_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequest(w, "invalid request body")
+		return
+	}
+	if req.TargetID == id {
+		errorJSON(w, http.StatusBadRequest, codeMergeIntoSelf, models.ErrMergeIntoSelf.Error())
+		return
+	}
+
+	if _, err := s.repo.GetItem(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			notFound(w, "item")
+		} else {
+			internalError(w, err)
+		}
+		return
+	}
+
+	if _, err := s.repo.GetItem(r.Context(), req.TargetID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			notFound(w, "target item")
+		} else {
+			internalError(w, err)
+		}
+		return
+	}
+
+	if err := s.repo.MergeItem(r.Context(), id, req.TargetID); err != nil {
+		internalError(w, err)
+		return
+	}
+
+	s.sseServer.Publish(r.Context(), sseEventList, nil)
+
+	merged, err := s.repo.GetItem(r.Context(), req.TargetID)
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, merged)
+}
+
 func (s *Server) itemsDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
@@ -170,7 +301,7 @@ func (s *Server) itemsDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = models.GetItem(r.Context(), id)
+	_, err = s.repo.GetItem(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			notFound(w, "item")
@@ -180,11 +311,40 @@ func (s *Server) itemsDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := models.DeleteItem(r.Context(), id); err != nil {
-		// DeleteItem returns a descriptive error when the item is in use by recipes
-		conflict(w, err.Error())
+	if err := s.repo.DeleteItem(r.Context(), id); err != nil {
+		if dbmodels.IsReferenced(err) {
+			conflict(w, codeInUse, "item is still in use")
+		} else {
+			internalError(w, err)
+		}
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// ---------------------------------------------------------------------------
+// JSON representation helpers
+// ---------------------------------------------------------------------------
+
+type itemMatchJSON struct {
+	ID           int     `json:"id"`
+	CategoryID   int     `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	Name         string  `json:"name"`
+	Score        float64 `json:"score"`
+}
+
+func itemMatchesToJSON(matches []models.ItemMatch) []itemMatchJSON {
+	ret := make([]itemMatchJSON, 0, len(matches))
+	for _, m := range matches {
+		ret = append(ret, itemMatchJSON{
+			ID:           m.Item.ID,
+			CategoryID:   m.Item.CategoryID,
+			CategoryName: m.Item.CategoryName(),
+			Name:         m.Item.Name,
+			Score:        m.Score,
+		})
+	}
+	return ret
+}
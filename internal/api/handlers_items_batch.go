@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+	"github.com/taiidani/groceries/internal/models"
+)
+
+const (
+	batchActionAssign = "assign"
+	batchActionMerge  = "merge"
+	batchActionDelete = "delete"
+
+	maxBatchItems = 100
+)
+
+// itemsBatchHandler applies one action to many items, typically to triage the
+// uncategorized inbox. Each item is processed independently and reported in
+// the results, so one failure does not prevent the rest from being applied.
+func (s *Server) itemsBatchHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Action     string `json:"action"`
+		ItemIDs    []int  `json:"item_ids"`
+		CategoryID int    `json:"category_id"`
+		TargetID   int    `json:"target_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequest(w, "invalid request body")
+		return
+	}
+	if len(req.ItemIDs) == 0 {
+		invalidField(w, "item_ids", dbmodels.ErrRequired, "item_ids is required")
+		return
+	}
+	if len(req.ItemIDs) > maxBatchItems {
+		badRequest(w, "item_ids cannot contain more than 100 items")
+		return
+	}
+
+	var apply func(ctx context.Context, id int) error
+
+	switch req.Action {
+	case batchActionAssign:
+		if _, err := s.repo.GetCategory(r.Context(), req.CategoryID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				invalidField(w, "category_id", dbmodels.ErrUnknownRef, "category_id must reference an existing category")
+			} else {
+				internalError(w, err)
+			}
+			return
+		}
+		apply = func(ctx context.Context, id int) error {
+			return s.repo.ItemChangeCategory(ctx, id, req.CategoryID)
+		}
+
+	case batchActionMerge:
+		if _, err := s.repo.GetItem(r.Context(), req.TargetID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				invalidField(w, "target_id", dbmodels.ErrUnknownRef, "target_id must reference an existing item")
+			} else {
+				internalError(w, err)
+			}
+			return
+		}
+		apply = func(ctx context.Context, id int) error {
+			return s.repo.MergeItem(ctx, id, req.TargetID)
+		}
+
+	case batchActionDelete:
+		apply = func(ctx context.Context, id int) error {
+			// The list check and the deletion must see the same item
+			return s.repo.InTx(ctx, func(tx *models.Repository) error {
+				item, err := tx.GetItem(ctx, id)
+				if err != nil {
+					return err
+				}
+				if item.List != nil {
+					return errItemOnList
+				}
+				return tx.DeleteItem(ctx, id)
+			})
+		}
+
+	default:
+		invalidField(w, "action", dbmodels.ErrInvalidChoice, "action must be one of assign, merge or delete")
+		return
+	}
+
+	results := make([]batchResultJSON, 0, len(req.ItemIDs))
+	changed := false
+	for _, id := range req.ItemIDs {
+		result := batchResultJSON{ItemID: id, OK: true}
+
+		// Check up front so every action reports missing items the same way
+		if _, err := s.repo.GetItem(r.Context(), id); err != nil {
+			result.OK, result.Error = false, batchErrorMessage(err)
+		} else if err := apply(r.Context(), id); err != nil {
+			result.OK, result.Error = false, batchErrorMessage(err)
+		} else {
+			changed = true
+		}
+
+		results = append(results, result)
+	}
+
+	if changed {
+		s.sseServer.Publish(r.Context(), sseEventList, nil)
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Results []batchResultJSON `json:"results"`
+	}{Results: results})
+}
+
+var errItemOnList = errors.New("item is on the shopping list")
+
+// batchErrorMessage converts a per-item error into a message that is safe to
+// return to the client.
+func batchErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return "item not found"
+	case errors.Is(err, errItemOnList), errors.Is(err, models.ErrMergeIntoSelf):
+		return err.Error()
+	}
+
+	slog.Error("batch item action failed", "error", err)
+	return "an unexpected error occurred"
+}
+
+// ---------------------------------------------------------------------------
+// JSON representation helpers
+// ---------------------------------------------------------------------------
+
+type batchResultJSON struct {
+	ItemID int    `json:"item_id"`
+	OK     bool   `json:"ok"`
+	Error  string `json:"error,omitempty"`
+}
@@ -1,17 +1,19 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
 
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
 	"github.com/taiidani/groceries/internal/models"
 )
 
 func (s *Server) listGetHandler(w http.ResponseWriter, r *http.Request) {
-	items, err := models.LoadList(r.Context())
+	items, err := s.repo.LoadList(r.Context())
 	if err != nil {
 		internalError(w, err)
 		return
@@ -47,6 +49,7 @@ func (s *Server) listGetHandler(w http.ResponseWriter, r *http.Request) {
 func (s *Server) listAddItemHandler(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		ItemID   *int   `json:"item_id"`
+		Barcode  string `json:"barcode"`
 		Name     string `json:"name"`
 		Quantity string `json:"quantity"`
 	}
@@ -56,11 +59,12 @@ func (s *Server) listAddItemHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var item models.Item
+	var created *createdItem
 
 	switch {
 	case req.ItemID != nil:
 		var err error
-		item, err = models.GetItem(r.Context(), *req.ItemID)
+		item, err = s.repo.GetItem(r.Context(), *req.ItemID)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				notFound(w, "item")
@@ -70,45 +74,103 @@ func (s *Server) listAddItemHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-	case req.Name != "":
-		var err error
-		item, err = models.GetItemByName(r.Context(), req.Name)
+	case req.Barcode != "":
+		code, err := dbmodels.NormalizeBarcode(req.Barcode)
+		if err != nil {
+			validationFailed(w, err)
+			return
+		}
+
+		item, err = s.repo.ItemByBarcode(r.Context(), code)
 		if errors.Is(err, sql.ErrNoRows) {
-			// Create a new uncategorized item on the fly
-			newItem := models.Item{
-				Name:       req.Name,
-				CategoryID: models.UncategorizedCategoryID,
+			// An unknown barcode is named by the caller or, failing that, by
+			// the product lookup provider before being remembered on the item
+			name := req.Name
+			if name == "" {
+				product, lookupErr := s.products.Lookup(r.Context(), code)
+				if lookupErr != nil {
+					productLookupError(w, lookupErr)
+					return
+				}
+				name = product.Name
 			}
-			if addErr := models.AddItem(r.Context(), newItem); addErr != nil {
-				internalError(w, addErr)
-				return
+
+			item, created, err = s.getOrCreateItem(r.Context(), name)
+			if err == nil {
+				_, _, err = s.repo.AssignBarcode(r.Context(), int32(item.ID), code)
 			}
-			item, err = models.GetItemByName(r.Context(), req.Name)
 		}
 		if err != nil {
 			internalError(w, err)
 			return
 		}
 
+	case req.Name != "":
+		var err error
+		item, created, err = s.getOrCreateItem(r.Context(), req.Name)
+		if err != nil {
+			internalError(w, err)
+			return
+		}
+
 	default:
-		badRequest(w, "one of item_id or name is required")
+		badRequest(w, "one of item_id, barcode or name is required")
 		return
 	}
 
-	if err := models.ListAddItem(r.Context(), item.ID, req.Quantity); err != nil {
+	if err := s.repo.ListAddItem(r.Context(), item.ID, req.Quantity); err != nil {
 		// Unique constraint violation means item is already on the list
-		conflict(w, "item is already on the list")
+		conflict(w, codeAlreadyListed, "item is already on the list")
 		return
 	}
 
+	s.sseServer.Publish(r.Context(), sseEventList, nil)
+
 	// Re-fetch the item so the response includes the populated list field
-	updated, err := models.GetItem(r.Context(), item.ID)
+	updated, err := s.repo.GetItem(r.Context(), item.ID)
 	if err != nil {
 		internalError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, listItemToJSON(updated))
+	type response struct {
+		listItemJSON
+		Suggestions        []itemMatchJSON         `json:"suggestions,omitempty"`
+		CategorySuggestion *categorySuggestionJSON `json:"category_suggestion,omitempty"`
+	}
+
+	resp := response{listItemJSON: listItemToJSON(updated)}
+	if created != nil {
+		resp.Suggestions = itemMatchesToJSON(created.suggestions)
+		resp.CategorySuggestion = categorySuggestionToJSON(created.category)
+	}
+
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// createdItem describes an item created by getOrCreateItem: existing items
+// with similar names the caller may have meant, and the category the item was
+// suggested or assigned.
+type createdItem struct {
+	suggestions []models.ItemMatch
+	category    *models.CategorySuggestion
+}
+
+// getOrCreateItem returns the item with the given name or alias, creating a
+// new item if neither exists. The returned createdItem is nil when an existing
+// item was found.
+func (s *Server) getOrCreateItem(ctx context.Context, name string) (models.Item, *createdItem, error) {
+	item, suggestions, err := s.repo.FindItemByName(ctx, name)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return item, nil, err
+	}
+
+	item, category, err := s.repo.AddItemByName(ctx, name)
+	if err != nil {
+		return item, nil, err
+	}
+
+	return item, &createdItem{suggestions: suggestions, category: category}, nil
 }
 
 func (s *Server) listUpdateItemHandler(w http.ResponseWriter, r *http.Request) {
@@ -127,7 +189,7 @@ func (s *Server) listUpdateItemHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	item, err := models.GetItem(r.Context(), id)
+	item, err := s.repo.GetItem(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			notFound(w, "list item")
@@ -142,59 +204,98 @@ func (s *Server) listUpdateItemHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Quantity != nil {
-		item.List.Quantity = *req.Quantity
-	}
+	err = s.repo.InTx(r.Context(), func(tx *models.Repository) error {
+		listID, version, err := tx.LockListItemVersion(r.Context(), id)
+		if err != nil {
+			return err
+		}
+		if err := checkIfMatch(r, listItemETag(listID, version)); err != nil {
+			return err
+		}
 
-	if req.Done != nil {
-		if err := models.MarkItemDone(r.Context(), strconv.Itoa(id), *req.Done); err != nil {
-			internalError(w, err)
-			return
+		if req.Done != nil {
+			if err := tx.MarkItemDone(r.Context(), id, *req.Done); err != nil {
+				return err
+			}
 		}
-	}
 
-	if req.Quantity != nil {
-		if err := models.EditItem(r.Context(), item); err != nil {
+		if req.Quantity != nil {
+			return tx.ListChangeQuantity(r.Context(), id, *req.Quantity)
+		}
+		return nil
+	})
+	if errors.Is(err, errPreconditionFailed) {
+		s.listItemPreconditionFailed(w, r, id)
+		return
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			notFound(w, "list item")
+		} else {
 			internalError(w, err)
-			return
 		}
+		return
 	}
 
-	updated, err := models.GetItem(r.Context(), id)
+	s.sseServer.Publish(r.Context(), sseEventList, nil)
+	if req.Done != nil {
+		s.sseServer.Publish(r.Context(), sseEventCart, nil)
+	}
+
+	updated, err := s.repo.GetItem(r.Context(), id)
 	if err != nil {
 		internalError(w, err)
 		return
 	}
 
+	if updated.List != nil {
+		setETag(w, listItemETag(updated.List.ID, updated.List.Version))
+	}
 	writeJSON(w, http.StatusOK, listItemToJSON(updated))
 }
 
-func (s *Server) listRemoveItemHandler(w http.ResponseWriter, r *http.Request) {
-	id := r.PathValue("id")
-	if id == "" {
-		badRequest(w, "id is required")
+// listItemPreconditionFailed answers a stale list update with the entry's
+// current state.
+func (s *Server) listItemPreconditionFailed(w http.ResponseWriter, r *http.Request, id int) {
+	current, err := s.repo.GetItem(r.Context(), id)
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+	if current.List == nil {
+		notFound(w, "list item")
 		return
 	}
 
-	if _, err := strconv.Atoi(id); err != nil {
+	preconditionFailed(w, listItemETag(current.List.ID, current.List.Version), listItemToJSON(current))
+}
+
+func (s *Server) listRemoveItemHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
 		badRequest(w, "id must be an integer")
 		return
 	}
 
-	if err := models.DeleteFromList(r.Context(), id); err != nil {
+	if err := s.repo.DeleteFromList(r.Context(), id); err != nil {
 		internalError(w, err)
 		return
 	}
 
+	s.sseServer.Publish(r.Context(), sseEventList, nil)
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
 func (s *Server) listFinishHandler(w http.ResponseWriter, r *http.Request) {
-	if err := models.FinishShopping(r.Context()); err != nil {
+	if err := s.repo.FinishShopping(r.Context()); err != nil {
 		internalError(w, err)
 		return
 	}
 
+	s.sseServer.Publish(r.Context(), sseEventList, nil)
+	s.sseServer.Publish(r.Context(), sseEventCart, nil)
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -209,6 +310,7 @@ type listItemJSON struct {
 	CategoryID int    `json:"category_id"`
 	Quantity   string `json:"quantity"`
 	Done       bool   `json:"done"`
+	Version    int    `json:"version"`
 }
 
 func listItemToJSON(item models.Item) listItemJSON {
@@ -221,6 +323,26 @@ func listItemToJSON(item models.Item) listItemJSON {
 		out.ID = item.List.ID
 		out.Quantity = item.List.Quantity
 		out.Done = item.List.Done
+		out.Version = item.List.Version
 	}
 	return out
 }
+
+type categorySuggestionJSON struct {
+	CategoryID   int     `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	Confidence   float64 `json:"confidence"`
+	Assigned     bool    `json:"assigned"`
+}
+
+func categorySuggestionToJSON(c *models.CategorySuggestion) *categorySuggestionJSON {
+	if c == nil {
+		return nil
+	}
+	return &categorySuggestionJSON{
+		CategoryID:   c.CategoryID,
+		CategoryName: c.CategoryName,
+		Confidence:   c.Confidence,
+		Assigned:     c.Assigned,
+	}
+}
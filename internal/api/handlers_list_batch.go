@@ -0,0 +1,126 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+	"github.com/taiidani/groceries/internal/models"
+)
+
+// listAddItemsBatchHandler adds many items to the shopping list in a single
+// transaction, such as every ingredient of a recipe. Entries that cannot be
+// added are reported in the results without failing the rest, and subscribers
+// receive one list event for the whole batch.
+func (s *Server) listAddItemsBatchHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Items []struct {
+			ItemID   int    `json:"item_id"`
+			Name     string `json:"name"`
+			Quantity string `json:"quantity"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequest(w, "invalid request body")
+		return
+	}
+	if len(req.Items) == 0 {
+		invalidField(w, "items", dbmodels.ErrRequired, "items is required")
+		return
+	}
+	if len(req.Items) > maxBatchItems {
+		badRequest(w, "items cannot contain more than 100 entries")
+		return
+	}
+
+	entries := make([]models.ListEntry, 0, len(req.Items))
+	for i, item := range req.Items {
+		if item.ItemID == 0 && strings.TrimSpace(item.Name) == "" {
+			badRequest(w, fmt.Sprintf("items[%d]: one of item_id or name is required", i))
+			return
+		}
+		entries = append(entries, models.ListEntry{
+			ItemID:   item.ItemID,
+			Name:     item.Name,
+			Quantity: item.Quantity,
+		})
+	}
+
+	added, err := s.repo.ListAddItems(r.Context(), entries)
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	entryResults, changed := listEntryResultsToJSON(added)
+	results := make([]listBatchResultJSON, 0, len(added))
+	for i, entry := range added {
+		result := listBatchResultJSON{listEntryResultJSON: entryResults[i]}
+		if entry.Created {
+			result.Suggestions = itemMatchesToJSON(entry.Suggestions)
+		}
+		results = append(results, result)
+	}
+
+	if changed {
+		s.sseServer.Publish(r.Context(), sseEventList, nil)
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Results []listBatchResultJSON `json:"results"`
+	}{Results: results})
+}
+
+// ---------------------------------------------------------------------------
+// JSON representation helpers
+// ---------------------------------------------------------------------------
+
+// listEntryResultJSON is the outcome of adding one entry to the list, shared
+// by the batch and import results.
+type listEntryResultJSON struct {
+	OK                 bool                    `json:"ok"`
+	Error              string                  `json:"error,omitempty"`
+	Item               *listItemJSON           `json:"item,omitempty"`
+	Created            bool                    `json:"created"`
+	CategorySuggestion *categorySuggestionJSON `json:"category_suggestion,omitempty"`
+}
+
+type listBatchResultJSON struct {
+	listEntryResultJSON
+	Suggestions []itemMatchJSON `json:"suggestions,omitempty"`
+}
+
+// listEntryResultsToJSON maps the outcomes of ListAddItems, reporting whether
+// any entry was added to the list.
+func listEntryResultsToJSON(added []models.ListEntryResult) ([]listEntryResultJSON, bool) {
+	ret := make([]listEntryResultJSON, 0, len(added))
+	changed := false
+	for _, entry := range added {
+		result := listEntryResultJSON{OK: entry.Err == nil}
+		switch {
+		case errors.Is(entry.Err, sql.ErrNoRows):
+			result.Error = "item not found"
+		case entry.Err != nil:
+			result.Error = entry.Err.Error()
+		default:
+			changed = true
+		}
+
+		if entry.Item.ID != 0 {
+			item := listItemToJSON(entry.Item)
+			result.Item = &item
+		}
+		if entry.Created {
+			result.Created = true
+			result.CategorySuggestion = categorySuggestionToJSON(entry.Category)
+		}
+
+		ret = append(ret, result)
+	}
+
+	return ret, changed
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/taiidani/groceries/internal/models"
+)
+
+// maxImportLines bounds the number of items a single import may add.
+const maxImportLines = 500
+
+// listImportHandler adds a list pasted or uploaded from another app, such as a
+// notes app checklist. Every parsed line is added in a single transaction and
+// reported in the results, including which lines created new items.
+func (s *Server) listImportHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Text   string `json:"text"`
+		Format string `json:"format"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequest(w, "invalid request body")
+		return
+	}
+
+	lines, err := models.ParseImport(strings.NewReader(req.Text), req.Format)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+	if len(lines) == 0 {
+		badRequest(w, "no items found in text")
+		return
+	}
+	if len(lines) > maxImportLines {
+		badRequest(w, "text cannot contain more than 500 items")
+		return
+	}
+
+	entries := make([]models.ListEntry, 0, len(lines))
+	for _, line := range lines {
+		entries = append(entries, models.ListEntry{Name: line.Name, Quantity: line.Quantity})
+	}
+
+	added, err := s.repo.ListAddItems(r.Context(), entries)
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	entryResults, changed := listEntryResultsToJSON(added)
+	results := make([]listImportResultJSON, 0, len(added))
+	for i, entry := range added {
+		results = append(results, listImportResultJSON{
+			Line:                lines[i].Line,
+			Name:                lines[i].Name,
+			Quantity:            lines[i].Quantity,
+			listEntryResultJSON: entryResults[i],
+			Uncategorized:       entry.Created && entry.Item.CategoryID == models.UncategorizedCategoryID,
+		})
+	}
+
+	if changed {
+		s.sseServer.Publish(r.Context(), sseEventList, nil)
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Results []listImportResultJSON `json:"results"`
+	}{Results: results})
+}
+
+// ---------------------------------------------------------------------------
+// JSON representation helpers
+// ---------------------------------------------------------------------------
+
+type listImportResultJSON struct {
+	Line     int    `json:"line"`
+	Name     string `json:"name"`
+	Quantity string `json:"quantity"`
+	listEntryResultJSON
+	Uncategorized bool `json:"uncategorized"`
+}
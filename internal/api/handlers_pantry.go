@@ -0,0 +1,241 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/taiidani/groceries/internal/db/models"
+)
+
+// bestBeforeLayout is the date format used for pantry best-before dates.
+const bestBeforeLayout = time.DateOnly
+
+func (s *Server) pantryListHandler(w http.ResponseWriter, r *http.Request) {
+	rows, err := s.repo.LoadPantry(r.Context())
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	ret := make([]pantryItemJSON, 0, len(rows))
+	for _, row := range rows {
+		ret = append(ret, pantryItemToJSON(row.PantryItem, row.ItemName))
+	}
+
+	writeJSON(w, http.StatusOK, ret)
+}
+
+func (s *Server) pantryExpiringHandler(w http.ResponseWriter, r *http.Request) {
+	days := models.ExpiringSoonDays
+	if rawDays := r.URL.Query().Get("days"); rawDays != "" {
+		var err error
+		days, err = strconv.Atoi(rawDays)
+		if err != nil || days < 0 || days > 365 {
+			badRequest(w, "days must be an integer between 0 and 365")
+			return
+		}
+	}
+
+	rows, err := s.repo.ExpiringPantryItems(r.Context(), time.Now().AddDate(0, 0, days))
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	ret := make([]pantryItemJSON, 0, len(rows))
+	for _, row := range rows {
+		ret = append(ret, pantryItemToJSON(row.PantryItem, row.ItemName))
+	}
+
+	writeJSON(w, http.StatusOK, ret)
+}
+
+func (s *Server) pantryGetHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := parseId(r.PathValue("id"))
+	if err != nil {
+		badRequest(w, "id must be an integer")
+		return
+	}
+
+	row, err := s.repo.GetPantryItem(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			notFound(w, "pantry item")
+		} else {
+			internalError(w, err)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pantryItemToJSON(row.PantryItem, row.ItemName))
+}
+
+func (s *Server) pantryUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := parseId(r.PathValue("id"))
+	if err != nil {
+		badRequest(w, "id must be an integer")
+		return
+	}
+
+	item, err := s.repo.GetItem(r.Context(), int(id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			notFound(w, "item")
+		} else {
+			internalError(w, err)
+		}
+		return
+	}
+
+	var req struct {
+		Quantity         int32   `json:"quantity"`
+		Location         string  `json:"location"`
+		BestBefore       *string `json:"best_before"`
+		RestockThreshold int32   `json:"restock_threshold"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequest(w, "invalid request body")
+		return
+	}
+
+	p := models.PantryItem{
+		ItemID:           id,
+		Quantity:         req.Quantity,
+		Location:         req.Location,
+		RestockThreshold: req.RestockThreshold,
+	}
+	if p.Location == "" {
+		p.Location = models.PantryLocationPantry
+	}
+	if req.BestBefore != nil && *req.BestBefore != "" {
+		bestBefore, err := time.Parse(bestBeforeLayout, *req.BestBefore)
+		if err != nil {
+			invalidField(w, "best_before", models.ErrInvalidFormat, "best_before must be a date in YYYY-MM-DD format")
+			return
+		}
+		p.BestBefore = sql.NullTime{Time: bestBefore, Valid: true}
+	}
+
+	pantryItem, err := s.repo.SetPantryItem(r.Context(), p)
+	if isValidation(err) {
+		validationFailed(w, err)
+		return
+	} else if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pantryItemToJSON(pantryItem, item.Name))
+}
+
+func (s *Server) pantryDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := parseId(r.PathValue("id"))
+	if err != nil {
+		badRequest(w, "id must be an integer")
+		return
+	}
+
+	if _, err := s.repo.GetPantryItem(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			notFound(w, "pantry item")
+		} else {
+			internalError(w, err)
+		}
+		return
+	}
+
+	if err := s.repo.DeletePantryItem(r.Context(), id); err != nil {
+		internalError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) pantryConsumeHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := parseId(r.PathValue("id"))
+	if err != nil {
+		badRequest(w, "id must be an integer")
+		return
+	}
+
+	existing, err := s.repo.GetPantryItem(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			notFound(w, "pantry item")
+		} else {
+			internalError(w, err)
+		}
+		return
+	}
+
+	// An empty body consumes a single unit
+	req := struct {
+		Amount int32 `json:"amount"`
+	}{Amount: 1}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			badRequest(w, "invalid request body")
+			return
+		}
+	}
+	if req.Amount < 1 {
+		invalidField(w, "amount", models.ErrOutOfRange, "amount must be at least 1")
+		return
+	}
+
+	pantryItem, addedToList, err := s.repo.ConsumePantryItem(r.Context(), id, req.Amount)
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	if addedToList {
+		if err := s.sseServer.Publish(r.Context(), sseEventList, nil); err != nil {
+			slog.WarnContext(r.Context(), "Could not publish list event", "error", err)
+		}
+	}
+
+	type response struct {
+		pantryItemJSON
+		AddedToList bool `json:"added_to_list"`
+	}
+
+	writeJSON(w, http.StatusOK, response{
+		pantryItemJSON: pantryItemToJSON(pantryItem, existing.ItemName),
+		AddedToList:    addedToList,
+	})
+}
+
+// ---------------------------------------------------------------------------
+// JSON representation helpers
+// ---------------------------------------------------------------------------
+
+type pantryItemJSON struct {
+	ItemID           int32   `json:"item_id"`
+	ItemName         string  `json:"item_name"`
+	Quantity         int32   `json:"quantity"`
+	Location         string  `json:"location"`
+	BestBefore       *string `json:"best_before"`
+	RestockThreshold int32   `json:"restock_threshold"`
+}
+
+func pantryItemToJSON(p models.PantryItem, itemName string) pantryItemJSON {
+	out := pantryItemJSON{
+		ItemID:           p.ItemID,
+		ItemName:         itemName,
+		Quantity:         p.Quantity,
+		Location:         p.Location,
+		RestockThreshold: p.RestockThreshold,
+	}
+	if p.BestBefore.Valid {
+		bestBefore := p.BestBefore.Time.Format(bestBeforeLayout)
+		out.BestBefore = &bestBefore
+	}
+	return out
+}
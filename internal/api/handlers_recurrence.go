@@ -0,0 +1,133 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/taiidani/groceries/internal/db/models"
+)
+
+func (s *Server) itemRecurrenceGetHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := parseId(r.PathValue("id"))
+	if err != nil {
+		badRequest(w, "id must be an integer")
+		return
+	}
+
+	rec, err := s.repo.GetItemRecurrence(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			notFound(w, "recurrence")
+		} else {
+			internalError(w, err)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, recurrenceToJSON(rec))
+}
+
+func (s *Server) itemRecurrenceUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := parseId(r.PathValue("id"))
+	if err != nil {
+		badRequest(w, "id must be an integer")
+		return
+	}
+
+	if _, err := s.repo.GetItem(r.Context(), int(id)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			notFound(w, "item")
+		} else {
+			internalError(w, err)
+		}
+		return
+	}
+
+	var req struct {
+		IntervalDays *int32 `json:"interval_days"`
+		Weekday      *int32 `json:"weekday"`
+		Quantity     string `json:"quantity"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequest(w, "invalid request body")
+		return
+	}
+
+	rec := models.ItemRecurrence{
+		ItemID:   id,
+		Quantity: req.Quantity,
+	}
+	if req.IntervalDays != nil {
+		rec.IntervalDays = sql.NullInt32{Int32: *req.IntervalDays, Valid: true}
+	}
+	if req.Weekday != nil {
+		rec.Weekday = sql.NullInt32{Int32: *req.Weekday, Valid: true}
+	}
+
+	rec, err = s.repo.SetItemRecurrence(r.Context(), rec)
+	if isValidation(err) {
+		validationFailed(w, err)
+		return
+	} else if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, recurrenceToJSON(rec))
+}
+
+func (s *Server) itemRecurrenceDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := parseId(r.PathValue("id"))
+	if err != nil {
+		badRequest(w, "id must be an integer")
+		return
+	}
+
+	if _, err := s.repo.GetItemRecurrence(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			notFound(w, "recurrence")
+		} else {
+			internalError(w, err)
+		}
+		return
+	}
+
+	if err := s.repo.DeleteItemRecurrence(r.Context(), id); err != nil {
+		internalError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ---------------------------------------------------------------------------
+// JSON representation helpers
+// ---------------------------------------------------------------------------
+
+type recurrenceJSON struct {
+	ItemID       int32      `json:"item_id"`
+	IntervalDays *int32     `json:"interval_days"`
+	Weekday      *int32     `json:"weekday"`
+	Quantity     string     `json:"quantity"`
+	LastAddedAt  *time.Time `json:"last_added_at"`
+}
+
+func recurrenceToJSON(rec models.ItemRecurrence) recurrenceJSON {
+	out := recurrenceJSON{
+		ItemID:   rec.ItemID,
+		Quantity: rec.Quantity,
+	}
+	if rec.IntervalDays.Valid {
+		out.IntervalDays = &rec.IntervalDays.Int32
+	}
+	if rec.Weekday.Valid {
+		out.Weekday = &rec.Weekday.Int32
+	}
+	if rec.LastAddedAt.Valid {
+		out.LastAddedAt = &rec.LastAddedAt.Time
+	}
+	return out
+}
@@ -7,16 +7,25 @@ import (
 	"net/http"
 
 	"github.com/taiidani/groceries/internal/db/models"
+	legacy "github.com/taiidani/groceries/internal/models"
 )
 
 func (s *Server) storesListHandler(w http.ResponseWriter, r *http.Request) {
-	stores, err := s.db.ListStores(r.Context())
+	page, err := parsePage(r)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	stores, err := s.repo.LoadStoresPage(r.Context(), page.query())
 	if err != nil {
 		internalError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, stores)
+	writePage(w, page, stores, func(s models.Store) legacy.PageKey {
+		return legacy.PageKey{Name: s.Name, ID: int(s.ID)}
+	})
 }
 
 func (s *Server) storesGetHandler(w http.ResponseWriter, r *http.Request) {
@@ -26,7 +35,7 @@ func (s *Server) storesGetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	store, err := s.db.GetStore(r.Context(), id)
+	store, err := s.repo.GetStore(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			notFound(w, "store")
@@ -36,7 +45,7 @@ func (s *Server) storesGetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	categories, err := s.db.ListCategoriesForStore(r.Context(), store.ID)
+	categories, err := s.repo.StoreCategories(r.Context(), store.ID)
 	if err != nil {
 		internalError(w, err)
 		return
@@ -62,13 +71,11 @@ func (s *Server) storesCreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.db.ValidateStore(r.Context(), models.Store{Name: req.Name}); err != nil {
-		badRequest(w, err.Error())
+	store, err := s.repo.AddStore(r.Context(), req.Name)
+	if isValidation(err) {
+		validationFailed(w, err)
 		return
-	}
-
-	store, err := s.db.CreateStore(r.Context(), req.Name)
-	if err != nil {
+	} else if err != nil {
 		internalError(w, err)
 		return
 	}
@@ -83,16 +90,6 @@ func (s *Server) storesUpdateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	existing, err := s.db.GetStore(r.Context(), id)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			notFound(w, "store")
-		} else {
-			internalError(w, err)
-		}
-		return
-	}
-
 	var req struct {
 		Name string `json:"name"`
 	}
@@ -101,18 +98,15 @@ func (s *Server) storesUpdateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.db.ValidateStore(r.Context(), models.Store{ID: id, Name: req.Name}); err != nil {
-		badRequest(w, err.Error())
-		return
-	}
-
-	existing.Name = req.Name
-	store, err := s.db.UpdateStore(r.Context(), models.UpdateStoreParams{
-		ID:   id,
-		Name: req.Name,
-	})
+	store, err := s.repo.RenameStore(r.Context(), id, req.Name)
 	if err != nil {
-		internalError(w, err)
+		if isValidation(err) {
+			validationFailed(w, err)
+		} else if errors.Is(err, sql.ErrNoRows) {
+			notFound(w, "store")
+		} else {
+			internalError(w, err)
+		}
 		return
 	}
 
@@ -126,7 +120,7 @@ func (s *Server) storesDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, err := s.db.GetStore(r.Context(), id); err != nil {
+	if _, err := s.repo.GetStore(r.Context(), id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			notFound(w, "store")
 		} else {
@@ -135,9 +129,12 @@ func (s *Server) storesDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.db.DeleteStore(r.Context(), id); err != nil {
-		// DeleteStore returns a descriptive error when the store is in use
-		conflict(w, err.Error())
+	if err := s.repo.DeleteStore(r.Context(), id); err != nil {
+		if errors.Is(err, legacy.ErrStoreInUse) {
+			conflict(w, codeInUse, err.Error())
+		} else {
+			internalError(w, err)
+		}
 		return
 	}
 
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/taiidani/groceries/internal/models"
+)
+
+// maxSyncOperations is the largest batch of offline changes accepted by a
+// single sync. It is larger than maxBatchItems because a client may queue a
+// whole shopping trip's worth of changes while out of reception.
+const maxSyncOperations = 500
+
+var syncOpTypes = []models.SyncOpType{models.SyncAdd, models.SyncCheck, models.SyncQuantity, models.SyncRemove}
+
+// syncHandler applies the changes a client made to the shopping list while
+// offline and returns the changes made on the server since the client's
+// cursor, so that it can bring its local copy up to date in one round trip.
+// When there are more changes than fit in the response the client continues
+// reading them from the change feed.
+// Conflicting changes are resolved by their timestamps as described on
+// models.Repository.ApplySync.
+func (s *Server) syncHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Cursor     int64 `json:"cursor"`
+		Operations []struct {
+			Type      models.SyncOpType `json:"type"`
+			ItemID    int               `json:"item_id"`
+			Name      string            `json:"name"`
+			Quantity  string            `json:"quantity"`
+			Done      bool              `json:"done"`
+			Timestamp time.Time         `json:"timestamp"`
+		} `json:"operations"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequest(w, "invalid request body")
+		return
+	}
+	if req.Cursor < 0 {
+		badRequest(w, "cursor cannot be negative")
+		return
+	}
+	if len(req.Operations) > maxSyncOperations {
+		badRequest(w, fmt.Sprintf("operations cannot contain more than %d entries", maxSyncOperations))
+		return
+	}
+
+	ops := make([]models.SyncOp, 0, len(req.Operations))
+	for i, op := range req.Operations {
+		switch {
+		case !slices.Contains(syncOpTypes, op.Type):
+			badRequest(w, fmt.Sprintf("operations[%d]: type must be one of add, check, quantity or remove", i))
+			return
+		case op.ItemID == 0 && strings.TrimSpace(op.Name) == "":
+			badRequest(w, fmt.Sprintf("operations[%d]: one of item_id or name is required", i))
+			return
+		case op.Timestamp.IsZero():
+			badRequest(w, fmt.Sprintf("operations[%d]: timestamp is required", i))
+			return
+		}
+
+		ops = append(ops, models.SyncOp{
+			Type:     op.Type,
+			ItemID:   op.ItemID,
+			Name:     op.Name,
+			Quantity: op.Quantity,
+			Done:     op.Done,
+			At:       op.Timestamp,
+		})
+	}
+
+	applied, err := s.repo.ApplySync(r.Context(), ops)
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	results := make([]syncResultJSON, 0, len(applied))
+	changed := false
+	for _, result := range applied {
+		results = append(results, syncResultJSON{Status: result.Status, ItemID: result.ItemID})
+		changed = changed || result.Status == models.SyncApplied
+	}
+
+	// The changes are read after the operations commit, so that the client
+	// receives the result of its own operations along with everyone else's
+	changes, err := s.changesSince(r.Context(), req.Cursor, defaultChangesLimit)
+	if err != nil {
+		internalError(w, err)
+		return
+	}
+
+	if changed {
+		s.sseServer.Publish(r.Context(), sseEventList, nil)
+		s.sseServer.Publish(r.Context(), sseEventCart, nil)
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Results []syncResultJSON `json:"results"`
+		changesPageJSON
+	}{
+		Results:         results,
+		changesPageJSON: changes,
+	})
+}
+
+// ---------------------------------------------------------------------------
+// JSON representation helpers
+// ---------------------------------------------------------------------------
+
+type syncResultJSON struct {
+	Status models.SyncStatus `json:"status"`
+	ItemID int               `json:"item_id,omitempty"`
+}
@@ -7,16 +7,25 @@ import (
 	"net/http"
 
 	"github.com/taiidani/groceries/internal/db/models"
+	legacy "github.com/taiidani/groceries/internal/models"
 )
 
 func (s *Server) usersListHandler(w http.ResponseWriter, r *http.Request) {
-	users, err := s.db.ListUsers(r.Context())
+	page, err := parsePage(r)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	users, err := s.repo.LoadUsersPage(r.Context(), page.query())
 	if err != nil {
 		internalError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, users)
+	writePage(w, page, users, func(u models.User) legacy.PageKey {
+		return legacy.PageKey{Name: u.Name, ID: int(u.ID)}
+	})
 }
 
 func (s *Server) usersGetHandler(w http.ResponseWriter, r *http.Request) {
@@ -26,7 +35,7 @@ func (s *Server) usersGetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := s.db.GetUser(r.Context(), id)
+	user, err := s.repo.GetUser(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			notFound(w, "user")
@@ -49,16 +58,14 @@ func (s *Server) usersCreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Name == "" {
-		badRequest(w, "name is required")
-		return
-	}
-
-	created, err := s.db.CreateUser(r.Context(), models.CreateUserParams{
+	created, err := s.repo.AddUser(r.Context(), models.User{
 		Name:  req.Name,
 		Admin: req.Admin,
 	})
-	if err != nil {
+	if isValidation(err) {
+		validationFailed(w, err)
+		return
+	} else if err != nil {
 		internalError(w, err)
 		return
 	}
@@ -73,7 +80,7 @@ func (s *Server) usersUpdateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := s.db.GetUser(r.Context(), id)
+	user, err := s.repo.GetUser(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			notFound(w, "user")
@@ -92,18 +99,19 @@ func (s *Server) usersUpdateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updateParams := models.UpdateUserParams{
-		ID: user.ID,
-	}
+	// Fields left out of the request keep their current values
 	if req.Name != nil {
-		updateParams.Name = *req.Name
+		user.Name = *req.Name
 	}
 	if req.Admin != nil {
-		updateParams.Admin = *req.Admin
+		user.Admin = *req.Admin
 	}
 
-	user, err = s.db.UpdateUser(r.Context(), updateParams)
-	if err != nil {
+	user, err = s.repo.EditUser(r.Context(), user)
+	if isValidation(err) {
+		validationFailed(w, err)
+		return
+	} else if err != nil {
 		internalError(w, err)
 		return
 	}
@@ -118,7 +126,7 @@ func (s *Server) usersDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, err := s.db.GetUser(r.Context(), id); err != nil {
+	if _, err := s.repo.GetUser(r.Context(), id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			notFound(w, "user")
 		} else {
@@ -127,7 +135,7 @@ func (s *Server) usersDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.db.DeleteUser(r.Context(), id); err != nil {
+	if err := s.repo.DeleteUser(r.Context(), id); err != nil {
 		internalError(w, err)
 		return
 	}
@@ -25,13 +25,13 @@ func (s *Server) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			errorJSON(w, http.StatusUnauthorized, "missing Authorization header")
+			errorJSON(w, http.StatusUnauthorized, codeUnauthorized, "missing Authorization header")
 			return
 		}
 
 		scheme, token, found := strings.Cut(authHeader, " ")
 		if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
-			errorJSON(w, http.StatusUnauthorized, "Authorization header must use the Bearer scheme")
+			errorJSON(w, http.StatusUnauthorized, codeUnauthorized, "Authorization header must use the Bearer scheme")
 			return
 		}
 
@@ -40,19 +40,19 @@ func (s *Server) authMiddleware(next http.Handler) http.Handler {
 		err := s.cache.Get(r.Context(), tokenCacheKey(token), &tokenData)
 		if err != nil {
 			if err == cache.ErrKeyNotFound {
-				errorJSON(w, http.StatusUnauthorized, "invalid or expired token")
+				errorJSON(w, http.StatusUnauthorized, codeUnauthorized, "invalid or expired token")
 			} else {
 				slog.ErrorContext(r.Context(), "failed to look up API token", "error", err)
-				errorJSON(w, http.StatusInternalServerError, "could not validate token")
+				errorJSON(w, http.StatusInternalServerError, codeInternal, "could not validate token")
 			}
 			return
 		}
 
 		// Load the associated user
-		user, err := s.db.GetUser(r.Context(), tokenData.UserID)
+		user, err := s.repo.GetUser(r.Context(), tokenData.UserID)
 		if err != nil {
 			slog.ErrorContext(r.Context(), "failed to load user for API token", "userID", tokenData.UserID, "error", err)
-			errorJSON(w, http.StatusUnauthorized, "invalid or expired token")
+			errorJSON(w, http.StatusUnauthorized, codeUnauthorized, "invalid or expired token")
 			return
 		}
 
@@ -68,12 +68,12 @@ func (s *Server) adminMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		user, ok := r.Context().Value(userKey).(*models.User)
 		if !ok || user == nil {
-			errorJSON(w, http.StatusUnauthorized, "not authenticated")
+			errorJSON(w, http.StatusUnauthorized, codeUnauthorized, "not authenticated")
 			return
 		}
 
 		if !user.Admin {
-			errorJSON(w, http.StatusForbidden, "admin access required")
+			errorJSON(w, http.StatusForbidden, codeForbidden, "admin access required")
 			return
 		}
 
@@ -0,0 +1,99 @@
+package api
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// openAPISpec is the OpenAPI description of the routes registered in
+// addRoutes. It is maintained by hand alongside them.
+//
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// docsPage renders openAPISpec in the browser.
+//
+//go:embed docs.html
+var docsPage []byte
+
+// specHandler serves the OpenAPI description of this API.
+func (s *Server) specHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(openAPISpec)
+}
+
+// docsHandler serves a page documenting this API.
+func (s *Server) docsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(docsPage)
+}
+
+// spec is a parsed OpenAPI document. It is kept as the generic maps and
+// slices the YAML decodes to, so that validation can walk any part of it.
+type spec struct {
+	doc map[string]any
+}
+
+// parseSpec parses an OpenAPI document.
+func parseSpec(raw []byte) (*spec, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse OpenAPI document: %w", err)
+	}
+	if _, ok := doc["paths"].(map[string]any); !ok {
+		return nil, fmt.Errorf("parse OpenAPI document: no paths")
+	}
+	return &spec{doc: doc}, nil
+}
+
+// operation returns the operation documented for a route pattern such as
+// "GET /api/v1/items/{id}", along with the parameters shared by every
+// operation on its path.
+func (s *spec) operation(pattern string) (op map[string]any, pathParams []any, ok bool) {
+	method, path, found := strings.Cut(pattern, " ")
+	if !found {
+		return nil, nil, false
+	}
+
+	item, ok := s.doc["paths"].(map[string]any)[path].(map[string]any)
+	if !ok {
+		return nil, nil, false
+	}
+	op, ok = item[strings.ToLower(method)].(map[string]any)
+	if !ok {
+		return nil, nil, false
+	}
+
+	pathParams, _ = item["parameters"].([]any)
+	return op, pathParams, true
+}
+
+// resolve follows a $ref to the part of the document it points at. Anything
+// other than a local reference is returned as it is.
+func (s *spec) resolve(node map[string]any) map[string]any {
+	for {
+		ref, ok := node["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return node
+		}
+
+		var target any = s.doc
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			part = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+			m, ok := target.(map[string]any)
+			if !ok {
+				return nil
+			}
+			target = m[part]
+		}
+
+		node, ok = target.(map[string]any)
+		if !ok {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// routeRecorder collects the patterns of the routes registered on it.
+type routeRecorder []string
+
+func (r *routeRecorder) Handle(pattern string, _ http.Handler) {
+	*r = append(*r, pattern)
+}
+
+func TestRoutesAreDocumented(t *testing.T) {
+	s, err := parseSpec(openAPISpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var routes routeRecorder
+	(&Server{}).addRoutes(&routes)
+
+	for _, pattern := range routes {
+		if !strings.Contains(pattern, " ") {
+			// Catch-all routes without a method are not operations
+			continue
+		}
+		if _, _, ok := s.operation(pattern); !ok {
+			t.Errorf("route %q is missing from openapi.yaml", pattern)
+		}
+	}
+
+	for path, item := range s.doc["paths"].(map[string]any) {
+		for method := range item.(map[string]any) {
+			if method == "parameters" {
+				continue
+			}
+			pattern := strings.ToUpper(method) + " " + path
+			if !slices.Contains(routes, pattern) {
+				t.Errorf("openapi.yaml documents %q, which is not routed", pattern)
+			}
+		}
+	}
+}
+
+func TestRoutesDoNotConflict(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("addRoutes() registered conflicting routes: %v", r)
+		}
+	}()
+
+	(&Server{}).addRoutes(http.NewServeMux())
+}
+
+func TestRoutesResolve(t *testing.T) {
+	mux := http.NewServeMux()
+	(&Server{}).addRoutes(mux)
+
+	tests := []struct {
+		method string
+		target string
+		want   string
+	}{
+		{method: http.MethodGet, target: "/api/v1/items/by-barcode/0123456789012", want: "GET /api/v1/items/by-barcode/{code}"},
+		{method: http.MethodGet, target: "/api/v1/items/7", want: "GET /api/v1/items/{id}"},
+		{method: http.MethodGet, target: "/api/v1/recurrences/7", want: "GET /api/v1/recurrences/{id}"},
+		{method: http.MethodPut, target: "/api/v1/recurrences/7", want: "PUT /api/v1/recurrences/{id}"},
+		{method: http.MethodDelete, target: "/api/v1/recurrences/7", want: "DELETE /api/v1/recurrences/{id}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			_, pattern := mux.Handler(httptest.NewRequest(tt.method, tt.target, nil))
+			if pattern != tt.want {
+				t.Errorf("%s %s is routed to %q, want %q", tt.method, tt.target, pattern, tt.want)
+			}
+		})
+	}
+}
+
+// TestRoutesAuthenticateBeforeValidating checks that an unauthenticated
+// request is refused with a 401 rather than a 400 describing the request the
+// route expects.
+func TestRoutesAuthenticateBeforeValidating(t *testing.T) {
+	v, err := newValidator(openAPISpec)
+	if err != nil {
+		t.Fatalf("newValidator() error = %v", err)
+	}
+	mux := http.NewServeMux()
+	(&Server{validator: v}).addRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/items", strings.NewReader(`{"name": 5}`)))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("POST /api/v1/items without a token = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestValidator(t *testing.T) {
+	const item = `{"id": 1, "category_id": 3, "category_name": "Produce", "name": "Apples", "version": 1, "list": null}`
+
+	tests := []struct {
+		name     string
+		pattern  string
+		method   string
+		target   string
+		body     string
+		status   int
+		response string
+		// contentType of the response, application/json when empty
+		contentType string
+		wantStatus  int
+		wantReport  bool
+	}{
+		{
+			name:       "matching request and response",
+			pattern:    "GET /api/v1/items/{id}",
+			method:     http.MethodGet,
+			target:     "/api/v1/items/1",
+			status:     http.StatusOK,
+			response:   item,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "response missing a required field",
+			pattern:    "GET /api/v1/items/{id}",
+			method:     http.MethodGet,
+			target:     "/api/v1/items/1",
+			status:     http.StatusOK,
+			response:   `{"id": 1, "category_id": 3, "category_name": "Produce", "version": 1}`,
+			wantStatus: http.StatusOK,
+			wantReport: true,
+		},
+		{
+			name:       "undocumented status",
+			pattern:    "GET /api/v1/items/{id}",
+			method:     http.MethodGet,
+			target:     "/api/v1/items/1",
+			status:     http.StatusTeapot,
+			response:   `{"error": "short and stout"}`,
+			wantStatus: http.StatusTeapot,
+			wantReport: true,
+		},
+		{
+			name:        "documented problem",
+			pattern:     "GET /api/v1/items/{id}",
+			method:      http.MethodGet,
+			target:      "/api/v1/items/1",
+			status:      http.StatusNotFound,
+			contentType: problemContentType,
+			response:    `{"type": "about:blank", "title": "Not Found", "status": 404, "detail": "item not found", "code": "not_found", "error": "item not found"}`,
+			wantStatus:  http.StatusNotFound,
+		},
+		{
+			name:        "problem with an undocumented code",
+			pattern:     "GET /api/v1/items/{id}",
+			method:      http.MethodGet,
+			target:      "/api/v1/items/1",
+			status:      http.StatusNotFound,
+			contentType: problemContentType,
+			response:    `{"type": "about:blank", "title": "Not Found", "status": 404, "detail": "item not found", "code": "gone", "error": "item not found"}`,
+			wantStatus:  http.StatusNotFound,
+			wantReport:  true,
+		},
+		{
+			name:       "query parameter of the wrong type",
+			pattern:    "GET /api/v1/items",
+			method:     http.MethodGet,
+			target:     "/api/v1/items?limit=lots",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "query parameter out of range",
+			pattern:    "GET /api/v1/items",
+			method:     http.MethodGet,
+			target:     "/api/v1/items?limit=501",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "request body of the wrong type",
+			pattern:    "POST /api/v1/stores",
+			method:     http.MethodPost,
+			target:     "/api/v1/stores",
+			body:       `{"name": 5}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "matching request body",
+			pattern:    "POST /api/v1/stores",
+			method:     http.MethodPost,
+			target:     "/api/v1/stores",
+			body:       `{"name": "Costco"}`,
+			status:     http.StatusCreated,
+			response:   `{"id": 2, "name": "Costco"}`,
+			wantStatus: http.StatusCreated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := newValidator(openAPISpec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var reports []error
+			v.report = func(_ *http.Request, err error) { reports = append(reports, err) }
+
+			mux := http.NewServeMux()
+			mux.Handle(tt.pattern, v.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentType := tt.contentType
+				if contentType == "" {
+					contentType = "application/json"
+				}
+				w.Header().Set("Content-Type", contentType)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.response))
+			})))
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if got := len(reports) > 0; got != tt.wantReport {
+				t.Errorf("reports = %v, want a report: %v", reports, tt.wantReport)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/taiidani/groceries/internal/models"
+)
+
+const (
+	// defaultPageLimit and maxPageLimit bound how many rows a single page of
+	// a listing returns.
+	defaultPageLimit = 100
+	maxPageLimit     = 500
+)
+
+// pageRequest is a page of a listing requested through the limit, sort, q
+// and cursor query parameters.
+type pageRequest struct {
+	models.PageQuery
+}
+
+// parsePage reads the page a listing request asks for. Listings are sorted by
+// name unless the request asks otherwise.
+func parsePage(r *http.Request) (pageRequest, error) {
+	q := r.URL.Query()
+	page := pageRequest{models.PageQuery{
+		Search: q.Get("q"),
+		Sort:   models.SortName,
+		Limit:  defaultPageLimit,
+	}}
+
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return pageRequest{}, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		page.Limit = limit
+	}
+
+	if raw := q.Get("sort"); raw != "" {
+		if !slices.Contains(models.SortOrders, raw) {
+			return pageRequest{}, errors.New("sort must be one of name, -name, id or -id")
+		}
+		page.Sort = raw
+	}
+
+	if raw := q.Get("cursor"); raw != "" {
+		cursor, err := decodeCursor(raw)
+		if err != nil {
+			return pageRequest{}, errors.New("cursor is invalid")
+		}
+		if cursor.Sort != page.Sort {
+			return pageRequest{}, errors.New("cursor belongs to a listing with a different sort")
+		}
+		page.After = &models.PageKey{Name: cursor.Name, ID: cursor.ID}
+	}
+
+	return page, nil
+}
+
+// query returns the query for the page and the row after it, which tells
+// whether there is another page.
+func (p pageRequest) query() models.PageQuery {
+	query := p.PageQuery
+	query.Limit++
+	return query
+}
+
+// writePage writes the rows loaded by the page's query along with the page's
+// metadata. key returns the position of a row, from which the cursor for the
+// next page is built.
+func writePage[T any](w http.ResponseWriter, p pageRequest, rows []T, key func(T) models.PageKey) {
+	meta := pageMetaJSON{
+		Limit:   p.Limit,
+		Sort:    p.Sort,
+		HasMore: len(rows) > p.Limit,
+	}
+	if meta.HasMore {
+		rows = rows[:p.Limit]
+		last := key(rows[len(rows)-1])
+		meta.NextCursor = encodeCursor(pageCursor{Sort: p.Sort, Name: last.Name, ID: last.ID})
+	}
+	meta.Count = len(rows)
+	if rows == nil {
+		rows = []T{}
+	}
+
+	writeJSON(w, http.StatusOK, pageJSON[T]{Data: rows, Meta: meta})
+}
+
+// pageCursor is the position a listing continues from. Clients treat the
+// encoded cursor as opaque.
+type pageCursor struct {
+	Sort string `json:"s"`
+	Name string `json:"n,omitempty"`
+	ID   int    `json:"i"`
+}
+
+func encodeCursor(c pageCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return pageCursor{}, err
+	}
+
+	var c pageCursor
+	err = json.Unmarshal(raw, &c)
+	return c, err
+}
+
+// ---------------------------------------------------------------------------
+// JSON representation helpers
+// ---------------------------------------------------------------------------
+
+type pageJSON[T any] struct {
+	Data []T          `json:"data"`
+	Meta pageMetaJSON `json:"meta"`
+}
+
+type pageMetaJSON struct {
+	Count      int    `json:"count"`
+	Limit      int    `json:"limit"`
+	Sort       string `json:"sort"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
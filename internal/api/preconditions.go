@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// errPreconditionFailed is returned from a unit of work when the request's
+// If-Match header does not name the current version of the resource.
+var errPreconditionFailed = errors.New("the resource has changed since it was read")
+
+// etag formats a row version as a strong entity tag.
+func etag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// listItemETag formats the version of a list entry as a strong entity tag.
+// A list entry is a new row each time its item is added to the list, and its
+// version starts over, so the tag includes the entry's ID to keep a tag from
+// an earlier entry from matching.
+func listItemETag(id, version int) string {
+	return `"` + strconv.Itoa(id) + "-" + strconv.Itoa(version) + `"`
+}
+
+// setETag sends the entity tag of the resource in the response.
+func setETag(w http.ResponseWriter, tag string) {
+	w.Header().Set("ETag", tag)
+}
+
+// checkIfMatch returns errPreconditionFailed unless the request's If-Match
+// header lists the current entity tag or "*". Requests without the header are
+// unconditional, so clients that do not track versions keep working.
+//
+// The tag must be read with a lock held for the rest of the write, so
+// that no other write can slip in between the check and the update.
+func checkIfMatch(r *http.Request, current string) error {
+	header := r.Header.Values("If-Match")
+	if len(header) == 0 {
+		return nil
+	}
+
+	for _, line := range header {
+		for tag := range strings.SplitSeq(line, ",") {
+			// Weak tags never match: If-Match requires a strong comparison
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || tag == current {
+				return nil
+			}
+		}
+	}
+
+	return errPreconditionFailed
+}
+
+// preconditionFailed writes a 412 response carrying the current state of the
+// resource, so that the client can reapply its change and retry.
+func preconditionFailed(w http.ResponseWriter, tag string, current any) {
+	setETag(w, tag)
+	writeJSON(w, http.StatusPreconditionFailed, current)
+}
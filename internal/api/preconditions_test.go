@@ -0,0 +1,45 @@
+package api
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch []string
+		current string
+		wantErr error
+	}{
+		{name: "unconditional", current: etag(3)},
+		{name: "current version", ifMatch: []string{`"3"`}, current: etag(3)},
+		{name: "any version", ifMatch: []string{"*"}, current: etag(3)},
+		{name: "one of several", ifMatch: []string{`"2", "3"`}, current: etag(3)},
+		{name: "stale version", ifMatch: []string{`"2"`}, current: etag(3), wantErr: errPreconditionFailed},
+		{name: "weak tag", ifMatch: []string{`W/"3"`}, current: etag(3), wantErr: errPreconditionFailed},
+		{name: "current list entry", ifMatch: []string{`"8-1"`}, current: listItemETag(8, 1)},
+		{
+			// The version of a list entry starts over when its item is added
+			// to the list again, which must not revive tags of the old entry
+			name:    "earlier list entry",
+			ifMatch: []string{listItemETag(7, 1)},
+			current: listItemETag(8, 1),
+			wantErr: errPreconditionFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("PUT", "/api/v1/list/items/7", nil)
+			for _, v := range tt.ifMatch {
+				r.Header.Add("If-Match", v)
+			}
+
+			if err := checkIfMatch(r, tt.current); !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkIfMatch() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
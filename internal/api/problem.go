@@ -0,0 +1,128 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+// Problem codes identify the problems an error response describes. Clients
+// branch on them rather than on messages, so a published code must not
+// change.
+const (
+	codeBadRequest          = "bad_request"
+	codeValidationFailed    = "validation_failed"
+	codeUnauthorized        = "unauthorized"
+	codeInvalidCredentials  = "invalid_credentials"
+	codeForbidden           = "forbidden"
+	codeNotFound            = "not_found"
+	codeInUse               = "in_use"
+	codeAlreadyListed       = "already_listed"
+	codeAliasConflict       = "alias_conflict"
+	codeBarcodeAssigned     = "barcode_assigned"
+	codeMergeIntoSelf       = "merge_into_self"
+	codeUpstreamUnavailable = "upstream_unavailable"
+	codeInternal            = "internal_error"
+)
+
+// fieldCodes are the codes of the kinds of validation failure reported in
+// the errors of a validation_failed problem.
+var fieldCodes = map[error]string{
+	dbmodels.ErrRequired:      "required",
+	dbmodels.ErrTooShort:      "too_short",
+	dbmodels.ErrTooLong:       "too_long",
+	dbmodels.ErrOutOfRange:    "out_of_range",
+	dbmodels.ErrInvalidChoice: "invalid_choice",
+	dbmodels.ErrInvalidFormat: "invalid_format",
+	dbmodels.ErrDuplicate:     "duplicate",
+	dbmodels.ErrUnknownRef:    "unknown_reference",
+	dbmodels.ErrExclusive:     "exclusive",
+}
+
+// problemContentType is the media type of error responses.
+const problemContentType = "application/problem+json"
+
+// Problem is the body of every API error response, an RFC 9457 problem
+// details object.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+	Code   string `json:"code"`
+	// Errors lists the fields at fault in a validation_failed problem.
+	Errors []FieldProblem `json:"errors,omitempty"`
+	// Error repeats Detail for clients written before problem details.
+	//
+	// Deprecated: Use Detail, and Code to tell problems apart.
+	Error string `json:"error"`
+}
+
+// FieldProblem is a problem with one field of a request.
+type FieldProblem struct {
+	// Field is empty when the problem concerns the request as a whole.
+	Field   string `json:"field,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func newProblem(status int, code, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+		Error:  detail,
+	}
+}
+
+// writeProblem writes p as an error response.
+func writeProblem(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		slog.Error("failed to encode problem response", "error", err)
+	}
+}
+
+// validationFailed writes a 400 problem listing the field errors in err, as
+// returned by the models' Validate methods. Anything else in err means that
+// validation itself failed, which is an internal error.
+func validationFailed(w http.ResponseWriter, err error) {
+	fields, ok := dbmodels.FieldErrors(err)
+	if !ok {
+		internalError(w, err)
+		return
+	}
+
+	messages := make([]string, 0, len(fields))
+	p := newProblem(http.StatusBadRequest, codeValidationFailed, "")
+	for _, f := range fields {
+		code, ok := fieldCodes[f.Err]
+		if !ok {
+			code = "invalid"
+		}
+		p.Errors = append(p.Errors, FieldProblem{Field: f.Field, Code: code, Message: f.Message})
+		messages = append(messages, f.Message)
+	}
+	p.Detail = strings.Join(messages, "\n")
+	p.Error = p.Detail
+
+	writeProblem(w, p)
+}
+
+// invalidField writes a 400 validation problem for a single field of the
+// request, such as a missing or malformed parameter.
+func invalidField(w http.ResponseWriter, field string, kind error, msg string) {
+	validationFailed(w, &dbmodels.FieldError{Field: field, Err: kind, Message: msg})
+}
+
+// isValidation reports whether err consists only of field errors.
+func isValidation(err error) bool {
+	_, ok := dbmodels.FieldErrors(err)
+	return ok
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+func TestValidationFailed(t *testing.T) {
+	s, err := parseSpec(openAPISpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := s.resolve(map[string]any{"$ref": "#/components/schemas/Problem"})
+
+	t.Run("field errors", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		validationFailed(rec, errors.Join(
+			&dbmodels.FieldError{Field: "name", Err: dbmodels.ErrTooShort, Message: "name is too short"},
+			&dbmodels.FieldError{Err: dbmodels.ErrExclusive, Message: "pick one"},
+		))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+		if got := rec.Header().Get("Content-Type"); got != problemContentType {
+			t.Errorf("Content-Type = %q, want %q", got, problemContentType)
+		}
+
+		var got Problem
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		want := Problem{
+			Type:   "about:blank",
+			Title:  "Bad Request",
+			Status: http.StatusBadRequest,
+			Detail: "name is too short\npick one",
+			Code:   codeValidationFailed,
+			Errors: []FieldProblem{
+				{Field: "name", Code: "too_short", Message: "name is too short"},
+				{Code: "exclusive", Message: "pick one"},
+			},
+			Error: "name is too short\npick one",
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("problem = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		validationFailed(rec, errors.Join(
+			&dbmodels.FieldError{Field: "name", Err: dbmodels.ErrTooShort, Message: "name is too short"},
+			fmt.Errorf("could not load store: %w", errors.New("connection refused")),
+		))
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+		}
+	})
+
+	// Every kind of field error must have a documented code
+	for kind, code := range fieldCodes {
+		t.Run(code, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			invalidField(rec, "name", kind, kind.Error())
+
+			body, err := decodeJSON(rec.Body.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.checkSchema(schema, body, "body"); err != nil {
+				t.Errorf("problem does not match openapi.yaml: %v", err)
+			}
+		})
+	}
+}
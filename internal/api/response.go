@@ -6,11 +6,6 @@ import (
 	"net/http"
 )
 
-// ErrorResponse is the standard error body returned by all API endpoints.
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
-
 // writeJSON serialises v as JSON and writes it to w with the given status code.
 func writeJSON(w http.ResponseWriter, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
@@ -20,35 +15,29 @@ func writeJSON(w http.ResponseWriter, status int, v any) {
 	}
 }
 
-// errorJSON writes a standard JSON error response.
-func errorJSON(w http.ResponseWriter, status int, msg string) {
-	writeJSON(w, status, ErrorResponse{Error: msg})
-}
-
-// renderError is an alias for errorJSON used by catch-all route handlers that
-// do not have access to the request object.
-func renderError(w http.ResponseWriter, status int, err error) {
-	errorJSON(w, status, err.Error())
+// errorJSON writes a problem details error response.
+func errorJSON(w http.ResponseWriter, status int, code, msg string) {
+	writeProblem(w, newProblem(status, code, msg))
 }
 
-// badRequest writes a 400 JSON error response.
+// badRequest writes a 400 error response for a malformed request.
 func badRequest(w http.ResponseWriter, msg string) {
-	errorJSON(w, http.StatusBadRequest, msg)
+	errorJSON(w, http.StatusBadRequest, codeBadRequest, msg)
 }
 
-// notFound writes a 404 JSON error response.
+// notFound writes a 404 error response.
 func notFound(w http.ResponseWriter, resource string) {
-	errorJSON(w, http.StatusNotFound, resource+" not found")
+	errorJSON(w, http.StatusNotFound, codeNotFound, resource+" not found")
 }
 
-// conflict writes a 409 JSON error response.
-func conflict(w http.ResponseWriter, msg string) {
-	errorJSON(w, http.StatusConflict, msg)
+// conflict writes a 409 error response.
+func conflict(w http.ResponseWriter, code, msg string) {
+	errorJSON(w, http.StatusConflict, code, msg)
 }
 
-// internalError logs err and writes a 500 JSON error response. The raw error
-// is intentionally not forwarded to the client.
+// internalError logs err and writes a 500 error response. The raw error is
+// intentionally not forwarded to the client.
 func internalError(w http.ResponseWriter, err error) {
 	slog.Error("internal API error", "error", err)
-	errorJSON(w, http.StatusInternalServerError, "an unexpected error occurred")
+	errorJSON(w, http.StatusInternalServerError, codeInternal, "an unexpected error occurred")
 }
@@ -5,64 +5,102 @@ package api
 
 import (
 	"context"
-	"database/sql"
-	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 
 	sentryhttp "github.com/getsentry/sentry-go/http"
 	"github.com/go-redis/redis/v8"
 	"github.com/taiidani/groceries/internal/cache"
-	"github.com/taiidani/groceries/internal/db/models"
 	"github.com/taiidani/groceries/internal/events"
+	legacy "github.com/taiidani/groceries/internal/models"
+	"github.com/taiidani/groceries/internal/products"
 )
 
 // Server is the API server instance.
 type Server struct {
 	ctx       context.Context
-	db        *models.Queries
+	repo      *legacy.Repository
 	cache     cache.Cache
 	sseServer events.PubSub
+	products  products.Provider
+	validator *validator
 }
 
 // NewServer creates a new API server and registers all routes onto the provided mux.
-// Routes are mounted under /api/v1/.
-func NewServer(ctx context.Context, conn *sql.DB, rds *redis.Client, mux *http.ServeMux) *Server {
+// Routes are mounted under /api/v1/. All data is read and written through repo,
+// and unknown barcodes are resolved through the given product lookup provider.
+func NewServer(ctx context.Context, repo *legacy.Repository, rds *redis.Client, lookup products.Provider, mux *http.ServeMux) *Server {
 	srv := &Server{
 		ctx:       ctx,
-		db:        models.New(conn),
+		repo:      repo,
 		cache:     cache.NewRedisCache(rds),
 		sseServer: events.NewRedisPubSub(rds),
+		products:  lookup,
+	}
+	if validateEnabled {
+		v, err := newValidator(openAPISpec)
+		if err != nil {
+			slog.ErrorContext(ctx, "could not enable API validation", "error", err)
+		}
+		srv.validator = v
 	}
 	srv.addRoutes(mux)
 	return srv
 }
 
-func (s *Server) addRoutes(mux *http.ServeMux) {
+// router is the part of http.ServeMux that routes are registered with.
+type router interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+func (s *Server) addRoutes(mux router) {
 	sentryHandler := sentryhttp.New(sentryhttp.Options{})
 
+	validate := func(h http.Handler) http.Handler {
+		if s.validator != nil {
+			h = s.validator.middleware(h)
+		}
+		return h
+	}
+
+	public := func(h http.Handler) http.Handler {
+		return sentryHandler.Handle(validate(h))
+	}
+
+	// Callers are authenticated, and admins authorized, before their requests
+	// are validated, so that a caller without access gets a 401 or 403 rather
+	// than a 400 describing the expected request
 	wrap := func(h http.Handler) http.Handler {
-		return sentryHandler.Handle(s.authMiddleware(h))
+		return sentryHandler.Handle(s.authMiddleware(validate(h)))
+	}
+
+	admin := func(h http.Handler) http.Handler {
+		return sentryHandler.Handle(s.authMiddleware(s.adminMiddleware(validate(h))))
 	}
 
+	// Documentation - no token required
+	mux.Handle("GET /api/v1/openapi.yaml", public(http.HandlerFunc(s.specHandler)))
+	mux.Handle("GET /api/v1/docs", public(http.HandlerFunc(s.docsHandler)))
+
 	// Auth - no token required
-	mux.Handle("POST /api/v1/auth/login", sentryHandler.Handle(http.HandlerFunc(s.authLoginHandler)))
+	mux.Handle("POST /api/v1/auth/login", public(http.HandlerFunc(s.authLoginHandler)))
 	mux.Handle("POST /api/v1/auth/logout", wrap(http.HandlerFunc(s.authLogoutHandler)))
 	mux.Handle("GET /api/v1/auth/me", wrap(http.HandlerFunc(s.authMeHandler)))
 
 	// Users (admin only)
-	mux.Handle("GET /api/v1/users", wrap(s.adminMiddleware(http.HandlerFunc(s.usersListHandler))))
-	mux.Handle("POST /api/v1/users", wrap(s.adminMiddleware(http.HandlerFunc(s.usersCreateHandler))))
-	mux.Handle("GET /api/v1/users/{id}", wrap(s.adminMiddleware(http.HandlerFunc(s.usersGetHandler))))
-	mux.Handle("PUT /api/v1/users/{id}", wrap(s.adminMiddleware(http.HandlerFunc(s.usersUpdateHandler))))
-	mux.Handle("DELETE /api/v1/users/{id}", wrap(s.adminMiddleware(http.HandlerFunc(s.usersDeleteHandler))))
+	mux.Handle("GET /api/v1/users", admin(http.HandlerFunc(s.usersListHandler)))
+	mux.Handle("POST /api/v1/users", admin(http.HandlerFunc(s.usersCreateHandler)))
+	mux.Handle("GET /api/v1/users/{id}", admin(http.HandlerFunc(s.usersGetHandler)))
+	mux.Handle("PUT /api/v1/users/{id}", admin(http.HandlerFunc(s.usersUpdateHandler)))
+	mux.Handle("DELETE /api/v1/users/{id}", admin(http.HandlerFunc(s.usersDeleteHandler)))
 
 	// Groups (admin only)
-	mux.Handle("GET /api/v1/groups", wrap(s.adminMiddleware(http.HandlerFunc(s.groupsListHandler))))
-	mux.Handle("POST /api/v1/groups", wrap(s.adminMiddleware(http.HandlerFunc(s.groupsCreateHandler))))
-	mux.Handle("GET /api/v1/groups/{id}", wrap(s.adminMiddleware(http.HandlerFunc(s.groupsGetHandler))))
-	mux.Handle("PUT /api/v1/groups/{id}", wrap(s.adminMiddleware(http.HandlerFunc(s.groupsUpdateHandler))))
-	mux.Handle("DELETE /api/v1/groups/{id}", wrap(s.adminMiddleware(http.HandlerFunc(s.groupsDeleteHandler))))
+	mux.Handle("GET /api/v1/groups", admin(http.HandlerFunc(s.groupsListHandler)))
+	mux.Handle("POST /api/v1/groups", admin(http.HandlerFunc(s.groupsCreateHandler)))
+	mux.Handle("GET /api/v1/groups/{id}", admin(http.HandlerFunc(s.groupsGetHandler)))
+	mux.Handle("PUT /api/v1/groups/{id}", admin(http.HandlerFunc(s.groupsUpdateHandler)))
+	mux.Handle("DELETE /api/v1/groups/{id}", admin(http.HandlerFunc(s.groupsDeleteHandler)))
 
 	// Stores
 	mux.Handle("GET /api/v1/stores", wrap(http.HandlerFunc(s.storesListHandler)))
@@ -81,20 +119,57 @@ func (s *Server) addRoutes(mux *http.ServeMux) {
 	// Items
 	mux.Handle("GET /api/v1/items", wrap(http.HandlerFunc(s.itemsListHandler)))
 	mux.Handle("POST /api/v1/items", wrap(http.HandlerFunc(s.itemsCreateHandler)))
+	mux.Handle("POST /api/v1/items/batch", wrap(http.HandlerFunc(s.itemsBatchHandler)))
+	mux.Handle("GET /api/v1/items/search", wrap(http.HandlerFunc(s.itemsSearchHandler)))
+	mux.Handle("GET /api/v1/items/by-barcode/{code}", wrap(http.HandlerFunc(s.itemsByBarcodeHandler)))
 	mux.Handle("GET /api/v1/items/{id}", wrap(http.HandlerFunc(s.itemsGetHandler)))
 	mux.Handle("PUT /api/v1/items/{id}", wrap(http.HandlerFunc(s.itemsUpdateHandler)))
 	mux.Handle("DELETE /api/v1/items/{id}", wrap(http.HandlerFunc(s.itemsDeleteHandler)))
+	mux.Handle("POST /api/v1/items/{id}/merge", wrap(http.HandlerFunc(s.itemsMergeHandler)))
+	mux.Handle("POST /api/v1/items/{id}/barcodes", wrap(http.HandlerFunc(s.itemBarcodeAddHandler)))
+	mux.Handle("DELETE /api/v1/items/{id}/barcodes/{code}", wrap(http.HandlerFunc(s.itemBarcodeDeleteHandler)))
+
+	// Recurrences, keyed by item ID. They live outside /api/v1/items/{id} so
+	// that they cannot clash with /api/v1/items/by-barcode/{code}.
+	mux.Handle("GET /api/v1/recurrences/{id}", wrap(http.HandlerFunc(s.itemRecurrenceGetHandler)))
+	mux.Handle("PUT /api/v1/recurrences/{id}", wrap(http.HandlerFunc(s.itemRecurrenceUpdateHandler)))
+	mux.Handle("DELETE /api/v1/recurrences/{id}", wrap(http.HandlerFunc(s.itemRecurrenceDeleteHandler)))
+
+	// Pantry
+	mux.Handle("GET /api/v1/pantry", wrap(http.HandlerFunc(s.pantryListHandler)))
+	mux.Handle("GET /api/v1/pantry/expiring", wrap(http.HandlerFunc(s.pantryExpiringHandler)))
+	mux.Handle("GET /api/v1/pantry/{id}", wrap(http.HandlerFunc(s.pantryGetHandler)))
+	mux.Handle("PUT /api/v1/pantry/{id}", wrap(http.HandlerFunc(s.pantryUpdateHandler)))
+	mux.Handle("DELETE /api/v1/pantry/{id}", wrap(http.HandlerFunc(s.pantryDeleteHandler)))
+	mux.Handle("POST /api/v1/pantry/{id}/consume", wrap(http.HandlerFunc(s.pantryConsumeHandler)))
+
+	// Backup (admin only)
+	mux.Handle("GET /api/v1/admin/backup", admin(http.HandlerFunc(s.backupHandler)))
+	mux.Handle("POST /api/v1/admin/restore", admin(http.HandlerFunc(s.restoreHandler)))
 
 	// Shopping list
 	mux.Handle("GET /api/v1/list", wrap(http.HandlerFunc(s.listGetHandler)))
 	mux.Handle("POST /api/v1/list/items", wrap(http.HandlerFunc(s.listAddItemHandler)))
+	mux.Handle("POST /api/v1/list/items:batch", wrap(http.HandlerFunc(s.listAddItemsBatchHandler)))
+	mux.Handle("POST /api/v1/list/import", wrap(http.HandlerFunc(s.listImportHandler)))
 	mux.Handle("PUT /api/v1/list/items/{id}", wrap(http.HandlerFunc(s.listUpdateItemHandler)))
 	mux.Handle("DELETE /api/v1/list/items/{id}", wrap(http.HandlerFunc(s.listRemoveItemHandler)))
 	mux.Handle("POST /api/v1/list/finish", wrap(http.HandlerFunc(s.listFinishHandler)))
+	mux.Handle("GET /api/v1/list/export", wrap(http.HandlerFunc(s.listExportHandler)))
+
+	// Offline sync and the change feed
+	mux.Handle("POST /api/v1/sync", wrap(http.HandlerFunc(s.syncHandler)))
+	mux.Handle("GET /api/v1/changes", wrap(http.HandlerFunc(s.changesHandler)))
+
+	// Catalog
+	mux.Handle("GET /api/v1/catalog/export", wrap(http.HandlerFunc(s.catalogExportHandler)))
+
+	// Events
+	mux.Handle("GET /api/v1/events", wrap(http.HandlerFunc(s.eventsHandler)))
 
 	// Not found handler for /api/v1/ prefix
 	mux.Handle("/api/", sentryHandler.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		renderError(w, http.StatusNotFound, fmt.Errorf("endpoint not found"))
+		notFound(w, "endpoint")
 	})))
 }
 
@@ -0,0 +1,437 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// validateEnabled checks every route against the OpenAPI document with
+// validator.middleware. It is on in development and can be turned on
+// elsewhere, such as in an end-to-end test environment, with
+// API_VALIDATE=true. Validation buffers every response and is not meant for
+// production.
+var validateEnabled = os.Getenv("DEV") == "true" || os.Getenv("API_VALIDATE") == "true"
+
+// validator checks requests and responses against the OpenAPI document.
+type validator struct {
+	spec *spec
+
+	// report is called with every response that does not match the
+	// document, and with every route the document does not describe.
+	report func(r *http.Request, err error)
+}
+
+// newValidator returns a validator for the given OpenAPI document that logs
+// the responses that do not match it.
+func newValidator(raw []byte) (*validator, error) {
+	s, err := parseSpec(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &validator{
+		spec: s,
+		report: func(r *http.Request, err error) {
+			slog.ErrorContext(r.Context(), "API does not match the OpenAPI document", "route", r.Pattern, "error", err)
+		},
+	}, nil
+}
+
+// middleware rejects requests that do not match the operation documented for
+// their route with a 400, and reports responses that do not match it. It
+// must wrap handlers registered on an http.ServeMux, which sets the route
+// the operation is looked up by.
+func (v *validator) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op, pathParams, ok := v.spec.operation(r.Pattern)
+		if !ok {
+			v.report(r, fmt.Errorf("%s is not documented", r.Pattern))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := v.checkRequest(r, op, pathParams); err != nil {
+			badRequest(w, err.Error())
+			return
+		}
+
+		// Streams are passed through as they are written
+		if v.streams(op) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		resp := &bufferedResponse{ResponseWriter: w}
+		next.ServeHTTP(resp, r)
+		if resp.status == 0 {
+			resp.status = http.StatusOK
+		}
+
+		if err := v.checkResponse(op, resp.status, w.Header(), resp.body.Bytes()); err != nil {
+			v.report(r, err)
+		}
+
+		w.WriteHeader(resp.status)
+		w.Write(resp.body.Bytes())
+	})
+}
+
+// checkRequest reports how a request fails to match its operation.
+func (v *validator) checkRequest(r *http.Request, op map[string]any, pathParams []any) error {
+	params, _ := op["parameters"].([]any)
+	for _, p := range append(slices.Clone(pathParams), params...) {
+		param, _ := p.(map[string]any)
+		param = v.spec.resolve(param)
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+
+		var raw string
+		var present bool
+		switch in {
+		case "path":
+			raw = r.PathValue(name)
+			present = raw != ""
+		case "query":
+			raw = r.URL.Query().Get(name)
+			present = r.URL.Query().Has(name)
+		case "header":
+			raw = r.Header.Get(name)
+			present = raw != ""
+		default:
+			continue
+		}
+
+		if !present {
+			if required, _ := param["required"].(bool); required {
+				return fmt.Errorf("%s parameter %s is required", in, name)
+			}
+			continue
+		}
+
+		schema, _ := param["schema"].(map[string]any)
+		if err := v.spec.checkSchema(schema, paramValue(v.spec.resolve(schema), raw), name); err != nil {
+			return err
+		}
+	}
+
+	body, _ := op["requestBody"].(map[string]any)
+	body = v.spec.resolve(body)
+	if body == nil {
+		return nil
+	}
+
+	schema, ok := jsonSchema(body, r.Header.Get("Content-Type"))
+	if !ok {
+		return nil
+	}
+
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("could not read the request body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(raw))
+
+	if len(raw) == 0 {
+		if required, _ := body["required"].(bool); required {
+			return fmt.Errorf("request body is required")
+		}
+		return nil
+	}
+
+	value, err := decodeJSON(raw)
+	if err != nil {
+		return fmt.Errorf("request body is not valid JSON")
+	}
+
+	return v.spec.checkSchema(schema, value, "request body")
+}
+
+// checkResponse reports how a response fails to match its operation.
+func (v *validator) checkResponse(op map[string]any, status int, header http.Header, body []byte) error {
+	responses, _ := op["responses"].(map[string]any)
+	resp, ok := responses[strconv.Itoa(status)].(map[string]any)
+	if !ok {
+		resp, ok = responses["default"].(map[string]any)
+	}
+	if !ok {
+		return fmt.Errorf("status %d is not documented", status)
+	}
+
+	resp = v.spec.resolve(resp)
+	if len(body) == 0 {
+		return nil
+	}
+
+	content, _ := resp["content"].(map[string]any)
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if _, ok := content[mediaType]; !ok {
+		return fmt.Errorf("status %d does not document a %q body", status, mediaType)
+	}
+
+	schema, ok := jsonSchema(resp, mediaType)
+	if !ok {
+		return nil
+	}
+
+	value, err := decodeJSON(body)
+	if err != nil {
+		return fmt.Errorf("status %d body is not valid JSON", status)
+	}
+
+	return v.spec.checkSchema(schema, value, fmt.Sprintf("status %d body", status))
+}
+
+// streams reports whether an operation responds with a stream of events.
+func (v *validator) streams(op map[string]any) bool {
+	responses, _ := op["responses"].(map[string]any)
+	for _, r := range responses {
+		resp, _ := r.(map[string]any)
+		content, _ := v.spec.resolve(resp)["content"].(map[string]any)
+		if _, ok := content["text/event-stream"]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonSchema returns the schema of a request body or response sent with the
+// given content type, when the content is JSON or a JSON-based type such as
+// application/problem+json. A body sent without a content type is taken to
+// be JSON.
+func jsonSchema(body map[string]any, contentType string) (map[string]any, bool) {
+	mediaType := "application/json"
+	if contentType != "" {
+		mediaType, _, _ = mime.ParseMediaType(contentType)
+	}
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return nil, false
+	}
+
+	content, _ := body["content"].(map[string]any)
+	media, ok := content[mediaType].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+
+	schema, ok := media["schema"].(map[string]any)
+	return schema, ok
+}
+
+// paramValue converts a parameter to the JSON value its schema describes, so
+// that it can be checked like a body. Values that do not convert are
+// returned as strings, which fail the schema's type.
+func paramValue(schema map[string]any, raw string) any {
+	switch schema["type"] {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(raw, 64); err == nil {
+			return json.Number(raw)
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+	return raw
+}
+
+func decodeJSON(raw []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var value any
+	err := dec.Decode(&value)
+	return value, err
+}
+
+// checkSchema reports how a decoded JSON value fails to match a schema. It
+// supports the parts of JSON Schema used by the API's document. at names the
+// value in the error.
+func (s *spec) checkSchema(schema map[string]any, value any, at string) error {
+	schema = s.resolve(schema)
+	if schema == nil {
+		return nil
+	}
+
+	for _, sub := range asList(schema["allOf"]) {
+		if err := s.checkSchema(asMap(sub), value, at); err != nil {
+			return err
+		}
+	}
+
+	if alts := asList(schema["oneOf"]); len(alts) > 0 {
+		matched := 0
+		for _, alt := range alts {
+			if s.checkSchema(asMap(alt), value, at) == nil {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return fmt.Errorf("%s matches %d of the schemas in oneOf, want 1", at, matched)
+		}
+	}
+
+	if alts := asList(schema["anyOf"]); len(alts) > 0 {
+		if !slices.ContainsFunc(alts, func(alt any) bool { return s.checkSchema(asMap(alt), value, at) == nil }) {
+			return fmt.Errorf("%s matches none of the schemas in anyOf", at)
+		}
+	}
+
+	if t, ok := schema["type"]; ok {
+		types := asList(t)
+		if name, ok := t.(string); ok {
+			types = []any{name}
+		}
+		if !slices.ContainsFunc(types, func(t any) bool { return hasType(value, t) }) {
+			return fmt.Errorf("%s must be of type %v", at, t)
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		if !slices.ContainsFunc(enum, func(e any) bool { return sameValue(e, value) }) {
+			return fmt.Errorf("%s must be one of %v", at, enum)
+		}
+	}
+	if c, ok := schema["const"]; ok && !sameValue(c, value) {
+		return fmt.Errorf("%s must be %v", at, c)
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		for _, name := range asList(schema["required"]) {
+			if _, ok := value[fmt.Sprint(name)]; !ok {
+				return fmt.Errorf("%s.%v is required", at, name)
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		for name, prop := range properties {
+			if v, ok := value[name]; ok {
+				if err := s.checkSchema(asMap(prop), v, at+"."+name); err != nil {
+					return err
+				}
+			}
+		}
+
+	case []any:
+		if n, ok := asNumber(schema["minItems"]); ok && float64(len(value)) < n {
+			return fmt.Errorf("%s must hold at least %v entries", at, n)
+		}
+		if n, ok := asNumber(schema["maxItems"]); ok && float64(len(value)) > n {
+			return fmt.Errorf("%s must hold at most %v entries", at, n)
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, v := range value {
+				if err := s.checkSchema(items, v, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case string:
+		length := float64(utf8.RuneCountInString(value))
+		if n, ok := asNumber(schema["minLength"]); ok && length < n {
+			return fmt.Errorf("%s must be at least %v characters", at, n)
+		}
+		if n, ok := asNumber(schema["maxLength"]); ok && length > n {
+			return fmt.Errorf("%s must be at most %v characters", at, n)
+		}
+		layout := map[string]string{"date-time": time.RFC3339, "date": time.DateOnly}[fmt.Sprint(schema["format"])]
+		if _, err := time.Parse(layout, value); layout != "" && err != nil {
+			return fmt.Errorf("%s must be a %v", at, schema["format"])
+		}
+
+	case json.Number:
+		n, _ := value.Float64()
+		if minimum, ok := asNumber(schema["minimum"]); ok && n < minimum {
+			return fmt.Errorf("%s must be at least %v", at, minimum)
+		}
+		if maximum, ok := asNumber(schema["maximum"]); ok && n > maximum {
+			return fmt.Errorf("%s must be at most %v", at, maximum)
+		}
+	}
+
+	return nil
+}
+
+// hasType reports whether a decoded JSON value is of a JSON Schema type.
+func hasType(value any, t any) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "integer":
+		n, ok := value.(json.Number)
+		return ok && !strings.ContainsAny(n.String(), ".eE")
+	}
+	return false
+}
+
+// sameValue reports whether a value from the document equals a decoded JSON
+// value. The two decode numbers differently, so they are compared as text.
+func sameValue(doc, value any) bool {
+	return fmt.Sprint(doc) == fmt.Sprint(value)
+}
+
+func asList(v any) []any {
+	list, _ := v.([]any)
+	return list
+}
+
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func asNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// bufferedResponse holds back a response until it has been checked.
+type bufferedResponse struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
@@ -0,0 +1,265 @@
+// Package backup reads and writes versioned JSON archives of the groceries
+// database, so that data can be moved between servers without pg_dump.
+//
+// Archives reference records by the IDs they had on the source server. On
+// restore every record is matched to an existing record by name or created
+// anew, and references are remapped to the IDs on the destination server.
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/taiidani/groceries/internal/db/models"
+	legacy "github.com/taiidani/groceries/internal/models"
+)
+
+const (
+	// Format identifies a groceries archive.
+	Format = "groceries-backup"
+
+	// Version is the archive version written by Create. Restore accepts this
+	// version and every earlier one.
+	Version = 1
+)
+
+// ErrInvalidArchive is returned when an archive cannot be restored because it
+// is malformed or fails validation. No data is changed when it is returned.
+var ErrInvalidArchive = errors.New("invalid archive")
+
+// Archive is a complete copy of the database.
+type Archive struct {
+	Format     string       `json:"format"`
+	Version    int          `json:"version"`
+	CreatedAt  time.Time    `json:"created_at"`
+	Groups     []Group      `json:"groups"`
+	Users      []User       `json:"users"`
+	Stores     []Store      `json:"stores"`
+	Categories []Category   `json:"categories"`
+	Items      []Item       `json:"items"`
+	List       []ListEntry  `json:"list"`
+	Staples    []Staple     `json:"staples"`
+	Pantry     []PantryItem `json:"pantry"`
+}
+
+// Group is a user group.
+type Group struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+}
+
+// User is a user account and the groups it belongs to.
+type User struct {
+	ID       int32   `json:"id"`
+	Name     string  `json:"name"`
+	Admin    bool    `json:"admin"`
+	GroupIDs []int32 `json:"group_ids"`
+}
+
+// Store is a store that categories are grouped under.
+type Store struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Category is a category of items within a store.
+type Category struct {
+	ID          int32  `json:"id"`
+	StoreID     int32  `json:"store_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Item is a grocery item with its alternative names and barcodes.
+type Item struct {
+	ID         int32    `json:"id"`
+	CategoryID int32    `json:"category_id"`
+	Name       string   `json:"name"`
+	Aliases    []string `json:"aliases"`
+	Barcodes   []string `json:"barcodes"`
+}
+
+// ListEntry is an item on the shopping list.
+type ListEntry struct {
+	ItemID   int32  `json:"item_id"`
+	Quantity string `json:"quantity"`
+	Done     bool   `json:"done"`
+}
+
+// Staple is the schedule on which an item is re-added to the list.
+type Staple struct {
+	ItemID       int32      `json:"item_id"`
+	IntervalDays *int32     `json:"interval_days"`
+	Weekday      *int32     `json:"weekday"`
+	Quantity     string     `json:"quantity"`
+	LastAddedAt  *time.Time `json:"last_added_at"`
+}
+
+// PantryItem is the stock of an item kept at home.
+type PantryItem struct {
+	ItemID           int32      `json:"item_id"`
+	Quantity         int32      `json:"quantity"`
+	Location         string     `json:"location"`
+	BestBefore       *time.Time `json:"best_before"`
+	RestockThreshold int32      `json:"restock_threshold"`
+}
+
+// Decode reads an archive, rejecting files that are not groceries archives or
+// that were written by a newer version of the application.
+func Decode(r io.Reader) (Archive, error) {
+	var ret Archive
+	if err := json.NewDecoder(r).Decode(&ret); err != nil {
+		return ret, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+
+	switch {
+	case ret.Format != Format:
+		return ret, fmt.Errorf("%w: not a %s file", ErrInvalidArchive, Format)
+	case ret.Version < 1:
+		return ret, fmt.Errorf("%w: missing version", ErrInvalidArchive)
+	case ret.Version > Version:
+		return ret, fmt.Errorf("%w: version %d is newer than the supported version %d", ErrInvalidArchive, ret.Version, Version)
+	}
+
+	return ret, nil
+}
+
+// Create reads the whole database into an archive. Everything is read from a
+// single snapshot so that the archive is consistent.
+func Create(ctx context.Context, repo *legacy.Repository) (ret Archive, err error) {
+	err = repo.ReadSnapshot(ctx, func(tx *legacy.Repository) error {
+		ret, err = create(ctx, tx.Queries())
+		return err
+	})
+	return ret, err
+}
+
+func create(ctx context.Context, q *models.Queries) (Archive, error) {
+	ret := Archive{
+		Format:    Format,
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	groups, err := q.ListGroups(ctx)
+	if err != nil {
+		return ret, fmt.Errorf("could not load groups: %w", err)
+	}
+	for _, g := range groups {
+		ret.Groups = append(ret.Groups, Group{ID: g.ID, Name: g.Name})
+	}
+
+	memberships, err := q.ListUserGroups(ctx)
+	if err != nil {
+		return ret, fmt.Errorf("could not load group memberships: %w", err)
+	}
+	userGroups := map[int32][]int32{}
+	for _, m := range memberships {
+		userGroups[m.UserID] = append(userGroups[m.UserID], m.GroupID)
+	}
+
+	users, err := q.ListUsers(ctx)
+	if err != nil {
+		return ret, fmt.Errorf("could not load users: %w", err)
+	}
+	for _, u := range users {
+		ret.Users = append(ret.Users, User{ID: u.ID, Name: u.Name, Admin: u.Admin, GroupIDs: userGroups[u.ID]})
+	}
+
+	stores, err := q.ListStores(ctx)
+	if err != nil {
+		return ret, fmt.Errorf("could not load stores: %w", err)
+	}
+	for _, s := range stores {
+		ret.Stores = append(ret.Stores, Store{ID: s.ID, Name: s.Name})
+	}
+
+	categories, err := q.ListCategories(ctx)
+	if err != nil {
+		return ret, fmt.Errorf("could not load categories: %w", err)
+	}
+	for _, c := range categories {
+		ret.Categories = append(ret.Categories, Category{ID: c.ID, StoreID: c.StoreID, Name: c.Name, Description: c.Description})
+	}
+
+	aliases, err := q.ListItemAliases(ctx)
+	if err != nil {
+		return ret, fmt.Errorf("could not load item aliases: %w", err)
+	}
+	itemAliases := map[int32][]string{}
+	for _, a := range aliases {
+		itemAliases[a.ItemID] = append(itemAliases[a.ItemID], a.Name)
+	}
+
+	barcodes, err := q.ListAllItemBarcodes(ctx)
+	if err != nil {
+		return ret, fmt.Errorf("could not load item barcodes: %w", err)
+	}
+	itemBarcodes := map[int32][]string{}
+	for _, b := range barcodes {
+		itemBarcodes[b.ItemID] = append(itemBarcodes[b.ItemID], b.Code)
+	}
+
+	items, err := q.ListItems(ctx)
+	if err != nil {
+		return ret, fmt.Errorf("could not load items: %w", err)
+	}
+	for _, i := range items {
+		ret.Items = append(ret.Items, Item{
+			ID:         i.ID,
+			CategoryID: i.CategoryID,
+			Name:       i.Name,
+			Aliases:    itemAliases[i.ID],
+			Barcodes:   itemBarcodes[i.ID],
+		})
+	}
+
+	list, err := q.ListListItems(ctx)
+	if err != nil {
+		return ret, fmt.Errorf("could not load the list: %w", err)
+	}
+	for _, l := range list {
+		ret.List = append(ret.List, ListEntry{ItemID: l.ItemID, Quantity: l.Quantity, Done: l.Done})
+	}
+
+	staples, err := q.ListItemRecurrences(ctx)
+	if err != nil {
+		return ret, fmt.Errorf("could not load staples: %w", err)
+	}
+	for _, s := range staples {
+		staple := Staple{ItemID: s.ItemID, Quantity: s.Quantity}
+		if s.IntervalDays.Valid {
+			staple.IntervalDays = &s.IntervalDays.Int32
+		}
+		if s.Weekday.Valid {
+			staple.Weekday = &s.Weekday.Int32
+		}
+		if s.LastAddedAt.Valid {
+			staple.LastAddedAt = &s.LastAddedAt.Time
+		}
+		ret.Staples = append(ret.Staples, staple)
+	}
+
+	pantry, err := q.ListPantryItems(ctx)
+	if err != nil {
+		return ret, fmt.Errorf("could not load the pantry: %w", err)
+	}
+	for _, p := range pantry {
+		item := PantryItem{
+			ItemID:           p.PantryItem.ItemID,
+			Quantity:         p.PantryItem.Quantity,
+			Location:         p.PantryItem.Location,
+			RestockThreshold: p.PantryItem.RestockThreshold,
+		}
+		if p.PantryItem.BestBefore.Valid {
+			item.BestBefore = &p.PantryItem.BestBefore.Time
+		}
+		ret.Pantry = append(ret.Pantry, item)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,39 @@
+package backup
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "current version", content: `{"format": "groceries-backup", "version": 1, "items": [{"id": 3, "name": "Milk"}]}`},
+		{name: "newer version", content: `{"format": "groceries-backup", "version": 2}`, wantErr: true},
+		{name: "missing version", content: `{"format": "groceries-backup"}`, wantErr: true},
+		{name: "other format", content: `{"format": "something-else", "version": 1}`, wantErr: true},
+		{name: "not json", content: `store,category,item`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(strings.NewReader(tt.content))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidArchive) {
+					t.Errorf("Decode() error = %v, want ErrInvalidArchive", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if len(got.Items) != 1 || got.Items[0].Name != "Milk" {
+				t.Errorf("Decode() items = %+v", got.Items)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/taiidani/groceries/internal/db/models"
+	legacy "github.com/taiidani/groceries/internal/models"
+)
+
+// Summary reports how many records of each kind a restore created, and how
+// many matched records that already existed and were left unchanged.
+type Summary struct {
+	Groups     Counts `json:"groups"`
+	Users      Counts `json:"users"`
+	Stores     Counts `json:"stores"`
+	Categories Counts `json:"categories"`
+	Items      Counts `json:"items"`
+}
+
+// Counts is the number of records created and matched by a restore.
+type Counts struct {
+	Created  int `json:"created"`
+	Existing int `json:"existing"`
+}
+
+// idMap maps the IDs in an archive to the IDs on this server.
+type idMap map[int32]int32
+
+// Restore loads an archive in a single transaction. Groups, users, stores and
+// items are matched by name, and categories by name within their store, so
+// that restoring into a server with data merges the two. Records that already
+// exist are left unchanged. Everything is validated with the same rules as the
+// API, and any failure rolls back the whole restore.
+func Restore(ctx context.Context, repo *legacy.Repository, a Archive) (summary Summary, err error) {
+	err = repo.InTx(ctx, func(tx *legacy.Repository) error {
+		summary, err = restore(ctx, tx.Queries(), a)
+		return err
+	})
+	return summary, err
+}
+
+func restore(ctx context.Context, q *models.Queries, a Archive) (Summary, error) {
+	summary := Summary{}
+
+	groups := idMap{}
+	for _, g := range a.Groups {
+		existing, err := q.GetGroupByName(ctx, g.Name)
+		if err == nil {
+			groups[g.ID] = existing.ID
+			summary.Groups.Existing++
+			continue
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return summary, err
+		}
+
+		if err := q.ValidateGroup(ctx, models.Group{Name: g.Name}); err != nil {
+			return summary, invalid("group %q: %w", g.Name, err)
+		}
+		created, err := q.CreateGroup(ctx, g.Name)
+		if err != nil {
+			return summary, fmt.Errorf("could not create group %q: %w", g.Name, err)
+		}
+		groups[g.ID] = created.ID
+		summary.Groups.Created++
+	}
+
+	for _, u := range a.Users {
+		user, err := q.GetUserByName(ctx, u.Name)
+		if err == nil {
+			summary.Users.Existing++
+		} else if errors.Is(err, sql.ErrNoRows) {
+			if err := q.ValidateUser(ctx, models.User{Name: u.Name, Admin: u.Admin}); err != nil {
+				return summary, invalid("user %q: %w", u.Name, err)
+			}
+			user, err = q.CreateUser(ctx, models.CreateUserParams{Name: u.Name, Admin: u.Admin})
+			if err != nil {
+				return summary, fmt.Errorf("could not create user %q: %w", u.Name, err)
+			}
+			summary.Users.Created++
+		} else {
+			return summary, err
+		}
+
+		for _, groupID := range u.GroupIDs {
+			id, ok := groups[groupID]
+			if !ok {
+				return summary, invalid("user %q: unknown group %d", u.Name, groupID)
+			}
+			if err := q.RestoreUserGroup(ctx, models.RestoreUserGroupParams{UserID: user.ID, GroupID: id}); err != nil {
+				return summary, fmt.Errorf("could not add user %q to group: %w", u.Name, err)
+			}
+		}
+	}
+
+	// The Uncategorized store and category always exist with the ID 0
+	stores := idMap{0: 0}
+	for _, s := range a.Stores {
+		if s.ID == 0 {
+			continue
+		}
+
+		existing, err := q.GetStoreByName(ctx, s.Name)
+		if err == nil {
+			stores[s.ID] = existing.ID
+			summary.Stores.Existing++
+			continue
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return summary, err
+		}
+
+		if err := q.ValidateStore(ctx, models.Store{Name: s.Name}); err != nil {
+			return summary, invalid("store %q: %w", s.Name, err)
+		}
+		created, err := q.CreateStore(ctx, s.Name)
+		if err != nil {
+			return summary, fmt.Errorf("could not create store %q: %w", s.Name, err)
+		}
+		if err := recordCreate(ctx, q, legacy.EntityStore, created.ID); err != nil {
+			return summary, err
+		}
+		stores[s.ID] = created.ID
+		summary.Stores.Created++
+	}
+
+	categories := idMap{0: 0}
+	for _, c := range a.Categories {
+		if c.ID == 0 {
+			continue
+		}
+
+		storeID, ok := stores[c.StoreID]
+		if !ok {
+			return summary, invalid("category %q: unknown store %d", c.Name, c.StoreID)
+		}
+
+		existing, err := q.GetCategoryForStoreByName(ctx, models.GetCategoryForStoreByNameParams{StoreID: storeID, Name: c.Name})
+		if err == nil {
+			categories[c.ID] = existing.ID
+			summary.Categories.Existing++
+			continue
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return summary, err
+		}
+
+		validate := legacy.Category{StoreID: int(storeID), Name: c.Name, Description: c.Description}
+		if err := validate.Validate(); err != nil {
+			return summary, invalid("category %q: %w", c.Name, err)
+		}
+		created, err := q.CreateCategory(ctx, models.CreateCategoryParams{Name: c.Name, StoreID: storeID, Description: c.Description})
+		if err != nil {
+			return summary, fmt.Errorf("could not create category %q: %w", c.Name, err)
+		}
+		if err := recordCreate(ctx, q, legacy.EntityCategory, created.ID); err != nil {
+			return summary, err
+		}
+		categories[c.ID] = created.ID
+		summary.Categories.Created++
+	}
+
+	items := idMap{}
+	for _, i := range a.Items {
+		categoryID, ok := categories[i.CategoryID]
+		if !ok {
+			return summary, invalid("item %q: unknown category %d", i.Name, i.CategoryID)
+		}
+		if i.Name == "" {
+			return summary, invalid("item %d: name is required", i.ID)
+		}
+
+		item, err := q.GetItemByName(ctx, i.Name)
+		if err == nil {
+			summary.Items.Existing++
+		} else if errors.Is(err, sql.ErrNoRows) {
+			item, err = q.CreateItem(ctx, models.CreateItemParams{CategoryID: categoryID, Name: i.Name})
+			if err != nil {
+				return summary, fmt.Errorf("could not create item %q: %w", i.Name, err)
+			}
+			if err := recordCreate(ctx, q, legacy.EntityItem, item.ID); err != nil {
+				return summary, err
+			}
+			summary.Items.Created++
+		} else {
+			return summary, err
+		}
+		items[i.ID] = item.ID
+
+		for _, alias := range i.Aliases {
+			if err := q.RestoreItemAlias(ctx, models.RestoreItemAliasParams{ItemID: item.ID, Name: alias}); err != nil {
+				return summary, fmt.Errorf("could not add alias %q: %w", alias, err)
+			}
+		}
+
+		for _, code := range i.Barcodes {
+			normalized, err := models.NormalizeBarcode(code)
+			if err != nil {
+				return summary, invalid("item %q: barcode %q: %w", i.Name, code, err)
+			}
+			if err := q.RestoreItemBarcode(ctx, models.RestoreItemBarcodeParams{ItemID: item.ID, Code: normalized}); err != nil {
+				return summary, fmt.Errorf("could not add barcode %q: %w", code, err)
+			}
+		}
+	}
+
+	for _, l := range a.List {
+		itemID, ok := items[l.ItemID]
+		if !ok {
+			return summary, invalid("list entry: unknown item %d", l.ItemID)
+		}
+		added, err := q.RestoreListItem(ctx, models.RestoreListItemParams{ItemID: itemID, Quantity: l.Quantity, Done: l.Done})
+		if err != nil {
+			return summary, fmt.Errorf("could not add list entry: %w", err)
+		}
+		if added == 0 {
+			continue
+		}
+		if err := recordCreate(ctx, q, legacy.EntityListItem, itemID); err != nil {
+			return summary, err
+		}
+	}
+
+	for _, s := range a.Staples {
+		itemID, ok := items[s.ItemID]
+		if !ok {
+			return summary, invalid("staple: unknown item %d", s.ItemID)
+		}
+
+		staple := models.ItemRecurrence{ItemID: itemID, Quantity: s.Quantity}
+		if s.IntervalDays != nil {
+			staple.IntervalDays = sql.NullInt32{Int32: *s.IntervalDays, Valid: true}
+		}
+		if s.Weekday != nil {
+			staple.Weekday = sql.NullInt32{Int32: *s.Weekday, Valid: true}
+		}
+		if s.LastAddedAt != nil {
+			staple.LastAddedAt = sql.NullTime{Time: *s.LastAddedAt, Valid: true}
+		}
+		if err := q.ValidateItemRecurrence(ctx, staple); err != nil {
+			return summary, invalid("staple for item %d: %w", s.ItemID, err)
+		}
+
+		err := q.RestoreItemRecurrence(ctx, models.RestoreItemRecurrenceParams{
+			ItemID:       staple.ItemID,
+			IntervalDays: staple.IntervalDays,
+			Weekday:      staple.Weekday,
+			Quantity:     staple.Quantity,
+			LastAddedAt:  staple.LastAddedAt,
+		})
+		if err != nil {
+			return summary, fmt.Errorf("could not add staple: %w", err)
+		}
+	}
+
+	for _, p := range a.Pantry {
+		itemID, ok := items[p.ItemID]
+		if !ok {
+			return summary, invalid("pantry item: unknown item %d", p.ItemID)
+		}
+
+		pantryItem := models.PantryItem{
+			ItemID:           itemID,
+			Quantity:         p.Quantity,
+			Location:         p.Location,
+			RestockThreshold: p.RestockThreshold,
+		}
+		if p.BestBefore != nil {
+			pantryItem.BestBefore = sql.NullTime{Time: *p.BestBefore, Valid: true}
+		}
+		if err := q.ValidatePantryItem(ctx, pantryItem); err != nil {
+			return summary, invalid("pantry item %d: %w", p.ItemID, err)
+		}
+
+		err := q.RestorePantryItem(ctx, models.RestorePantryItemParams{
+			ItemID:           pantryItem.ItemID,
+			Quantity:         pantryItem.Quantity,
+			Location:         pantryItem.Location,
+			BestBefore:       pantryItem.BestBefore,
+			RestockThreshold: pantryItem.RestockThreshold,
+		})
+		if err != nil {
+			return summary, fmt.Errorf("could not add pantry item: %w", err)
+		}
+	}
+
+	return summary, nil
+}
+
+// invalid wraps a validation failure in ErrInvalidArchive.
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %w", ErrInvalidArchive, fmt.Errorf(format, args...))
+}
+
+// recordCreate adds a restored record to the change log, so that clients
+// syncing from it pick the record up.
+func recordCreate(ctx context.Context, q *models.Queries, entity string, id int32) error {
+	return legacy.RecordChange(ctx, q, legacy.Change{Entity: entity, EntityID: int(id), Op: legacy.ChangeCreate})
+}
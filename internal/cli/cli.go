@@ -0,0 +1,132 @@
+// Package cli implements the subcommands of the groceries binary.
+//
+// Maintenance commands such as migrate, seed and user create connect to the
+// database in DATABASE_URL directly. Everyday commands such as backup and
+// list add go through the REST API, so they can be run against a remote
+// server with an API token.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrUsage is returned when a command is invoked with invalid arguments. The
+// usage has already been printed when it is returned.
+var ErrUsage = errors.New("invalid usage")
+
+// command is a subcommand of the groceries binary.
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(ctx context.Context, e *env, args []string) error
+}
+
+var commands = []command{
+	{name: "serve", summary: "Start the web server (the default)"},
+	{name: "migrate", args: "up|down|status", summary: "Apply, roll back or inspect database migrations", run: migrateCommand},
+	{name: "seed", args: "-yes", summary: "Replace the database contents with the development seed data", run: seedCommand},
+	{name: "user", args: "create [-admin] NAME", summary: "Create a user", run: userCommand},
+	{name: "backup", args: "[-o FILE]", summary: "Download a backup archive from the server", run: backupCommand},
+	{name: "list", args: "add [-quantity QUANTITY] NAME...", summary: "Add items to the shopping list on the server", run: listCommand},
+}
+
+// env is the environment that a command runs in.
+type env struct {
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
+// Run runs the subcommand named by args[0] with the remaining arguments.
+// The "serve" command is handled by the caller and is not accepted here.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	e := &env{stdout: stdout, stderr: stderr, getenv: os.Getenv}
+	return e.run(ctx, args)
+}
+
+func (e *env) run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		e.usage()
+		return ErrUsage
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] && cmd.run != nil {
+			err := cmd.run(ctx, e, args[1:])
+			if errors.Is(err, flag.ErrHelp) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		e.usage()
+		return nil
+	}
+
+	fmt.Fprintf(e.stderr, "unknown command %q\n\n", args[0])
+	e.usage()
+	return ErrUsage
+}
+
+func (e *env) usage() {
+	fmt.Fprintln(e.stderr, "Usage: groceries <command> [arguments]")
+	fmt.Fprintln(e.stderr)
+	fmt.Fprintln(e.stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(e.stderr, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+}
+
+// flagSet creates the flags for a command, printing its usage on errors.
+func (e *env) flagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: groceries %s %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses a command's flags, turning parse failures into ErrUsage.
+// Asking for help returns flag.ErrHelp, which Run treats as success.
+func parse(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return ErrUsage
+	}
+	return err
+}
+
+// usageError prints a problem with a command's arguments and its usage.
+func (e *env) usageError(fs *flag.FlagSet, format string, args ...any) error {
+	fmt.Fprintf(e.stderr, format+"\n", args...)
+	fs.Usage()
+	return ErrUsage
+}
+
+// subcommand splits the first positional argument from the rest, requiring it
+// to be one of the given names.
+func (e *env) subcommand(fs *flag.FlagSet, args []string, names ...string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, e.usageError(fs, "missing subcommand")
+	}
+
+	for _, name := range names {
+		if args[0] == name {
+			return name, args[1:], nil
+		}
+	}
+
+	return "", nil, e.usageError(fs, "unknown subcommand %q, expected %s", args[0], strings.Join(names, " or "))
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"testing"
+)
+
+func testEnv(vars map[string]string) *env {
+	return &env{
+		stdout: io.Discard,
+		stderr: io.Discard,
+		getenv: func(key string) string { return vars[key] },
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want error
+	}{
+		{name: "no command", args: nil, want: ErrUsage},
+		{name: "help", args: []string{"help"}, want: nil},
+		{name: "unknown command", args: []string{"frobnicate"}, want: ErrUsage},
+		{name: "serve is handled by main", args: []string{"serve"}, want: ErrUsage},
+		{name: "command help", args: []string{"migrate", "-h"}, want: nil},
+		{name: "migrate without direction", args: []string{"migrate"}, want: ErrUsage},
+		{name: "migrate unknown direction", args: []string{"migrate", "sideways"}, want: ErrUsage},
+		{name: "migrate extra arguments", args: []string{"migrate", "up", "now"}, want: ErrUsage},
+		{name: "seed without confirmation", args: []string{"seed"}, want: ErrUsage},
+		{name: "user without subcommand", args: []string{"user"}, want: ErrUsage},
+		{name: "user create without name", args: []string{"user", "create", "-admin"}, want: ErrUsage},
+		{name: "user create unknown flag", args: []string{"user", "create", "-root", "alice"}, want: ErrUsage},
+		{name: "backup extra arguments", args: []string{"backup", "now"}, want: ErrUsage},
+		{name: "list add without names", args: []string{"list", "add", "-quantity", "2"}, want: ErrUsage},
+		{name: "list unknown subcommand", args: []string{"list", "remove", "milk"}, want: ErrUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testEnv(nil).run(context.Background(), tt.args)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("run(%q) = %v, want %v", tt.args, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunMissingConfiguration(t *testing.T) {
+	e := testEnv(nil)
+
+	if err := e.run(context.Background(), []string{"migrate", "status"}); err == nil || errors.Is(err, ErrUsage) {
+		t.Errorf("migrate without DATABASE_URL = %v, want a configuration error", err)
+	}
+
+	if err := e.run(context.Background(), []string{"list", "add", "milk"}); err == nil || errors.Is(err, ErrUsage) {
+		t.Errorf("list add without credentials = %v, want a configuration error", err)
+	}
+}
+
+func TestRemoteFlags(t *testing.T) {
+	tests := []struct {
+		name      string
+		vars      map[string]string
+		args      []string
+		wantURL   string
+		wantToken string
+	}{
+		{
+			name:    "defaults",
+			wantURL: defaultURL,
+		},
+		{
+			name:      "environment",
+			vars:      map[string]string{"GROCERIES_URL": "https://example.com", "GROCERIES_TOKEN": "abc"},
+			wantURL:   "https://example.com",
+			wantToken: "abc",
+		},
+		{
+			name:      "flags override the environment",
+			vars:      map[string]string{"GROCERIES_URL": "https://example.com", "GROCERIES_TOKEN": "abc"},
+			args:      []string{"-url", "http://other", "-token", "def"},
+			wantURL:   "http://other",
+			wantToken: "def",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			r := testEnv(tt.vars).remoteFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if r.url != tt.wantURL {
+				t.Errorf("url = %q, want %q", r.url, tt.wantURL)
+			}
+			if r.token != tt.wantToken {
+				t.Errorf("token = %q, want %q", r.token, tt.wantToken)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/taiidani/groceries/internal/db"
+	"github.com/taiidani/groceries/internal/db/models"
+	legacy "github.com/taiidani/groceries/internal/models"
+)
+
+// openDB connects to the database in DATABASE_URL without migrating it.
+func (e *env) openDB(ctx context.Context) (*sql.DB, error) {
+	dsn := e.getenv("DATABASE_URL")
+	if dsn == "" {
+		return nil, errors.New("required DATABASE_URL environment variable not present")
+	}
+
+	conn, err := db.Open(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to database: %w", err)
+	}
+	return conn, nil
+}
+
+func migrateCommand(ctx context.Context, e *env, args []string) error {
+	fs := e.flagSet("migrate", "up|down|status")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	direction, rest, err := e.subcommand(fs, fs.Args(), "up", "down", "status")
+	if err != nil {
+		return err
+	} else if len(rest) > 0 {
+		return e.usageError(fs, "unexpected arguments %q", rest)
+	}
+
+	conn, err := e.openDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	switch direction {
+	case "up":
+		return db.MigrateUp(ctx, conn)
+	case "down":
+		return db.MigrateDown(ctx, conn)
+	default:
+		return db.MigrateStatus(ctx, conn)
+	}
+}
+
+func seedCommand(ctx context.Context, e *env, args []string) error {
+	fs := e.flagSet("seed", "-yes")
+	yes := fs.Bool("yes", false, "confirm that every existing record should be deleted")
+	if err := parse(fs, args); err != nil {
+		return err
+	} else if fs.NArg() > 0 {
+		return e.usageError(fs, "unexpected arguments %q", fs.Args())
+	} else if !*yes {
+		return e.usageError(fs, "seeding deletes all existing data; pass -yes to continue")
+	}
+
+	conn, err := e.openDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// The seeds expect the latest schema
+	if err := db.MigrateUp(ctx, conn); err != nil {
+		return err
+	}
+
+	if err := db.Seed(ctx, conn); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(e.stdout, "Seeded the database")
+	return nil
+}
+
+func userCommand(ctx context.Context, e *env, args []string) error {
+	fs := e.flagSet("user", "create [-admin] NAME")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	_, rest, err := e.subcommand(fs, fs.Args(), "create")
+	if err != nil {
+		return err
+	}
+
+	create := e.flagSet("user create", "[-admin] NAME")
+	admin := create.Bool("admin", false, "grant the user administrative access")
+	if err := parse(create, rest); err != nil {
+		return err
+	} else if create.NArg() != 1 {
+		return e.usageError(create, "expected exactly one user name")
+	}
+
+	conn, err := e.openDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	repo := legacy.NewRepository(conn)
+	user, err := repo.AddUser(ctx, models.User{Name: create.Arg(0), Admin: *admin})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.stdout, "Created user %q with ID %d\n", user.Name, user.ID)
+	return nil
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/taiidani/groceries/sdk"
+)
+
+// defaultURL is the server used when neither -url nor GROCERIES_URL is set.
+const defaultURL = "http://localhost:3000"
+
+// remote holds the flags that select and authenticate with a server.
+type remote struct {
+	url   string
+	token string
+	user  string
+}
+
+// remoteFlags registers the server flags on fs, defaulting them from the
+// environment.
+func (e *env) remoteFlags(fs *flag.FlagSet) *remote {
+	r := &remote{}
+
+	url := e.getenv("GROCERIES_URL")
+	if url == "" {
+		url = defaultURL
+	}
+
+	fs.StringVar(&r.url, "url", url, "server URL ($GROCERIES_URL)")
+	fs.StringVar(&r.token, "token", e.getenv("GROCERIES_TOKEN"), "API token ($GROCERIES_TOKEN)")
+	fs.StringVar(&r.user, "user", e.getenv("GROCERIES_USER"), "log in as this user with the password in $GROCERIES_PASSWORD, instead of using a token ($GROCERIES_USER)")
+	return r
+}
+
+// client returns an API client for the server, logging in first if a user
+// was given instead of a token.
+func (e *env) client(ctx context.Context, r *remote) (*sdk.Client, error) {
+	if r.token != "" {
+		return sdk.New(r.url, r.token), nil
+	} else if r.user == "" {
+		return nil, errors.New("an API token or user is required; set -token or -user")
+	}
+
+	password := e.getenv("GROCERIES_PASSWORD")
+	if password == "" {
+		return nil, errors.New("required GROCERIES_PASSWORD environment variable not present")
+	}
+
+	session, err := sdk.Login(ctx, r.url, r.user, password)
+	if err != nil {
+		return nil, fmt.Errorf("could not log in: %w", err)
+	}
+
+	return sdk.New(r.url, session.Token), nil
+}
+
+func backupCommand(ctx context.Context, e *env, args []string) error {
+	fs := e.flagSet("backup", "[-o FILE]")
+	r := e.remoteFlags(fs)
+	output := fs.String("o", "", "write the archive to FILE instead of stdout")
+	if err := parse(fs, args); err != nil {
+		return err
+	} else if fs.NArg() > 0 {
+		return e.usageError(fs, "unexpected arguments %q", fs.Args())
+	}
+
+	c, err := e.client(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	export, err := c.Backup(ctx)
+	if err != nil {
+		return err
+	}
+	defer export.Body.Close()
+
+	if *output == "" {
+		_, err = io.Copy(e.stdout, export.Body)
+		return err
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, export.Body); err != nil {
+		return errors.Join(f.Close(), err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.stderr, "Wrote backup to %s\n", *output)
+	return nil
+}
+
+func listCommand(ctx context.Context, e *env, args []string) error {
+	fs := e.flagSet("list", "add [-quantity QUANTITY] NAME...")
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	_, rest, err := e.subcommand(fs, fs.Args(), "add")
+	if err != nil {
+		return err
+	}
+
+	add := e.flagSet("list add", "[-quantity QUANTITY] NAME...")
+	r := e.remoteFlags(add)
+	quantity := add.String("quantity", "", "quantity to add each item with")
+	if err := parse(add, rest); err != nil {
+		return err
+	} else if add.NArg() == 0 {
+		return e.usageError(add, "expected at least one item name")
+	}
+
+	c, err := e.client(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	entries := []sdk.AddToListBatchRequestItem{}
+	for _, name := range add.Args() {
+		entries = append(entries, sdk.AddToListBatchRequestItem{Name: &name, Quantity: quantity})
+	}
+
+	resp, err := c.AddToListBatch(ctx, sdk.AddToListBatchRequest{Items: entries})
+	if err != nil {
+		return err
+	}
+	results := resp.Results
+
+	failed := 0
+	for i, result := range results {
+		// Names may have been matched to an existing item with a different name
+		name := *entries[i].Name
+		if result.Item != nil {
+			name = result.Item.ItemName
+		}
+
+		switch {
+		case !result.OK:
+			failed++
+			fmt.Fprintf(e.stderr, "Could not add %q: %s\n", name, sdk.Deref(result.Error))
+		case result.Created:
+			fmt.Fprintf(e.stdout, "Added %q as a new item\n", name)
+		default:
+			fmt.Fprintf(e.stdout, "Added %q\n", name)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d items could not be added", failed, len(entries))
+	}
+	return nil
+}
@@ -6,34 +6,87 @@ import (
 	"context"
 	"database/sql"
 	"embed"
+	"io/fs"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/pressly/goose/v3"
 )
 
+const dialect = "postgres"
+
 //go:embed migrations/*.sql
 var schema embed.FS
 
+//go:embed seeds/*.sql
+var seeds embed.FS
+
 func New(ctx context.Context, dsn string) (*sql.DB, error) {
-	db, err := sql.Open("pgx", dsn)
+	db, err := Open(ctx, dsn)
 	if err != nil {
 		return nil, err
 	}
 
-	err = db.PingContext(ctx)
+	return db, ensureSchema(ctx, db, dialect)
+}
+
+// Open connects to the database without applying migrations. The pool is
+// closed again when the database cannot be reached.
+func Open(ctx context.Context, dsn string) (*sql.DB, error) {
+	db, err := sql.Open("pgx", dsn)
 	if err != nil {
-		return db, err
+		return nil, err
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// MigrateUp applies every pending migration.
+func MigrateUp(ctx context.Context, db *sql.DB) error {
+	return ensureSchema(ctx, db, dialect)
+}
+
+// MigrateDown rolls back the most recently applied migration.
+func MigrateDown(ctx context.Context, db *sql.DB) error {
+	if err := useFS(schema, dialect); err != nil {
+		return err
+	}
+
+	return goose.DownContext(ctx, db, "migrations")
+}
+
+// MigrateStatus logs whether each migration has been applied.
+func MigrateStatus(ctx context.Context, db *sql.DB) error {
+	if err := useFS(schema, dialect); err != nil {
+		return err
 	}
 
-	return db, ensureSchema(ctx, db, "postgres")
+	return goose.StatusContext(ctx, db, "migrations")
 }
 
-func ensureSchema(_ context.Context, db *sql.DB, dialect string) error {
-	goose.SetBaseFS(schema)
+// Seed replaces the contents of the database with the development seed data.
+// Seeds are not versioned, so they can be applied any number of times.
+func Seed(ctx context.Context, db *sql.DB) error {
+	if err := useFS(seeds, dialect); err != nil {
+		return err
+	}
 
-	if err := goose.SetDialect(dialect); err != nil {
+	return goose.UpContext(ctx, db, "seeds", goose.WithNoVersioning())
+}
+
+func ensureSchema(ctx context.Context, db *sql.DB, dialect string) error {
+	if err := useFS(schema, dialect); err != nil {
 		return err
 	}
 
-	return goose.Up(db, "migrations")
+	return goose.UpContext(ctx, db, "migrations")
+}
+
+func useFS(fsys fs.FS, dialect string) error {
+	goose.SetBaseFS(fsys)
+	return goose.SetDialect(dialect)
 }
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE item_recurrence (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL UNIQUE REFERENCES item ON DELETE CASCADE,
+    interval_days INTEGER CHECK (interval_days > 0),
+    weekday INTEGER CHECK (weekday BETWEEN 0 AND 6),
+    quantity VARCHAR(255) NOT NULL DEFAULT '',
+    last_added_at TIMESTAMPTZ,
+    CHECK ((interval_days IS NULL) <> (weekday IS NULL))
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS item_recurrence;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE pantry_item (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL UNIQUE REFERENCES item ON DELETE CASCADE,
+    quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    location VARCHAR(32) NOT NULL DEFAULT 'pantry' CHECK (location IN ('fridge', 'freezer', 'pantry')),
+    best_before DATE,
+    restock_threshold INTEGER NOT NULL DEFAULT 0 CHECK (restock_threshold >= 0)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS pantry_item;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE item_barcode (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES item ON DELETE CASCADE,
+    code VARCHAR(14) NOT NULL UNIQUE
+);
+CREATE INDEX item_barcode_item_id ON item_barcode (item_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS item_barcode;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+DO $$
+BEGIN
+    CREATE EXTENSION IF NOT EXISTS pg_trgm;
+    CREATE INDEX IF NOT EXISTS item_name_trgm ON item USING gin (lower(name) gin_trgm_ops);
+EXCEPTION WHEN insufficient_privilege OR undefined_file THEN
+    -- Item search falls back to ranking in the application
+    RAISE NOTICE 'pg_trgm is unavailable: %', SQLERRM;
+END
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS item_name_trgm;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE item_alias (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES item ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL
+);
+CREATE UNIQUE INDEX item_alias_name ON item_alias (lower(name));
+CREATE INDEX item_alias_item_id ON item_alias (item_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS item_alias;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE item ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE item_list ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE category ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE category DROP COLUMN version;
+ALTER TABLE item_list DROP COLUMN version;
+ALTER TABLE item DROP COLUMN version;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE change_log (
+    seq BIGSERIAL PRIMARY KEY,
+    entity VARCHAR(32) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    op VARCHAR(16) NOT NULL,
+    field VARCHAR(32) NOT NULL DEFAULT '',
+    changed_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX change_log_entity ON change_log (entity, entity_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS change_log;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Records that predate the change log are recorded as created, so that a
+-- client reading the log from the start receives everything
+INSERT INTO change_log (entity, entity_id, op, changed_at)
+SELECT 'category', category.id, 'create', now() FROM category
+WHERE NOT EXISTS (SELECT 1 FROM change_log WHERE entity = 'category' AND entity_id = category.id);
+
+INSERT INTO change_log (entity, entity_id, op, changed_at)
+SELECT 'item', item.id, 'create', now() FROM item
+WHERE NOT EXISTS (SELECT 1 FROM change_log WHERE entity = 'item' AND entity_id = item.id);
+
+INSERT INTO change_log (entity, entity_id, op, changed_at)
+SELECT 'list_item', item_list.item_id, 'create', now() FROM item_list
+WHERE NOT EXISTS (SELECT 1 FROM change_log WHERE entity = 'list_item' AND entity_id = item_list.item_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- The backfilled entries are indistinguishable from later ones and are left
+-- in place; the change log itself is dropped by the previous migration
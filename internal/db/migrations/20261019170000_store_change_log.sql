@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Stores joined the change log after it was backfilled, so existing stores
+-- are recorded as created for clients reading the log from the start
+INSERT INTO change_log (entity, entity_id, op, changed_at)
+SELECT 'store', store.id, 'create', now() FROM store
+WHERE NOT EXISTS (SELECT 1 FROM change_log WHERE entity = 'store' AND entity_id = store.id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM change_log WHERE entity = 'store';
+-- +goose StatementEnd
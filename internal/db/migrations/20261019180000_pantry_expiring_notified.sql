@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- expiring_notified_for is the best-before date clients were last told was
+-- approaching, so that every replica of the scheduler announces it only once
+ALTER TABLE pantry_item ADD COLUMN expiring_notified_for DATE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pantry_item DROP COLUMN IF EXISTS expiring_notified_for;
+-- +goose StatementEnd
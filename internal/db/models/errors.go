@@ -0,0 +1,83 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Kinds of validation failure. Validate methods report each problem as a
+// *FieldError wrapping one of these, joining several with errors.Join, so
+// that callers can match them with errors.Is instead of by their text.
+var (
+	ErrRequired      = errors.New("value is required")
+	ErrTooShort      = errors.New("value is too short")
+	ErrTooLong       = errors.New("value is too long")
+	ErrOutOfRange    = errors.New("value is out of range")
+	ErrInvalidChoice = errors.New("value is not one of the allowed choices")
+	ErrInvalidFormat = errors.New("value is not in the expected format")
+	ErrDuplicate     = errors.New("value is already taken")
+	ErrUnknownRef    = errors.New("value refers to a record that does not exist")
+	ErrExclusive     = errors.New("values cannot be combined")
+)
+
+// FieldError is a problem with one field of a record.
+type FieldError struct {
+	// Field is the JSON name of the field, or empty when the problem concerns
+	// the record as a whole.
+	Field string
+	// Err is the kind of problem, one of the validation errors above.
+	Err error
+	// Message describes the problem to a person.
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors returns the field errors in err, in the order they were
+// joined, and reports whether err holds nothing else. It is false when
+// validation itself failed, such as a query that could not be run.
+func FieldErrors(err error) ([]*FieldError, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	switch err := err.(type) {
+	case *FieldError:
+		return []*FieldError{err}, true
+	case interface{ Unwrap() []error }:
+		var ret []*FieldError
+		for _, e := range err.Unwrap() {
+			fields, ok := FieldErrors(e)
+			if !ok {
+				return nil, false
+			}
+			ret = append(ret, fields...)
+		}
+		return ret, true
+	case interface{ Unwrap() error }:
+		return FieldErrors(err.Unwrap())
+	}
+
+	return nil, false
+}
+
+// IsReferenced reports whether err is the database refusing to delete a
+// record that other records still refer to.
+func IsReferenced(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
+
+// IsDuplicate reports whether err is the database refusing to store a value
+// that a unique constraint says is already taken.
+func IsDuplicate(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
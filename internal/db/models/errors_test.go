@@ -0,0 +1,63 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFieldErrors(t *testing.T) {
+	name := &FieldError{Field: "name", Err: ErrTooShort, Message: "name is too short"}
+	location := &FieldError{Field: "location", Err: ErrInvalidChoice, Message: "location is not allowed"}
+
+	tests := []struct {
+		name   string
+		err    error
+		want   []*FieldError
+		wantOK bool
+	}{
+		{name: "no error", err: nil, wantOK: false},
+		{name: "single field error", err: name, want: []*FieldError{name}, wantOK: true},
+		{name: "joined field errors", err: errors.Join(name, location), want: []*FieldError{name, location}, wantOK: true},
+		{name: "wrapped joined field errors", err: fmt.Errorf("invalid record: %w", errors.Join(name, location)), want: []*FieldError{name, location}, wantOK: true},
+		{name: "other error", err: errors.New("connection refused"), wantOK: false},
+		{name: "field error joined with other error", err: errors.Join(name, errors.New("connection refused")), wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FieldErrors(tt.err)
+			if ok != tt.wantOK {
+				t.Fatalf("FieldErrors() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("FieldErrors() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("FieldErrors()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePantryItem_FieldErrors(t *testing.T) {
+	err := (&Queries{}).ValidatePantryItem(t.Context(), PantryItem{Quantity: -1, Location: "garage"})
+
+	if !errors.Is(err, ErrOutOfRange) || !errors.Is(err, ErrInvalidChoice) {
+		t.Fatalf("ValidatePantryItem() error = %v, want out of range and invalid choice", err)
+	}
+
+	fields, ok := FieldErrors(err)
+	if !ok {
+		t.Fatalf("FieldErrors(%v) reported errors other than field errors", err)
+	}
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.Field)
+	}
+	if fmt.Sprint(names) != "[quantity location]" {
+		t.Errorf("fields = %v, want [quantity location]", names)
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import "strings"
+
+// NormalizeBarcode validates a UPC or EAN barcode and returns it in the form
+// it is stored. Spaces and hyphens are ignored, and UPC-A and GTIN-14 codes
+// that are equivalent to an EAN-13 are stored as that EAN-13 so that the same
+// product scans to the same item regardless of the scanner's output format.
+func NormalizeBarcode(code string) (string, error) {
+	code = strings.NewReplacer(" ", "", "-", "").Replace(code)
+
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return "", &FieldError{Field: "code", Err: ErrInvalidFormat, Message: "barcode must contain only digits"}
+		}
+	}
+
+	switch len(code) {
+	case 8, 13:
+	case 12:
+		code = "0" + code
+	case 14:
+		if code[0] == '0' {
+			code = code[1:]
+		}
+	default:
+		return "", &FieldError{Field: "code", Err: ErrInvalidFormat, Message: "barcode must be 8, 12, 13 or 14 digits long"}
+	}
+
+	if !validCheckDigit(code) {
+		return "", &FieldError{Field: "code", Err: ErrInvalidFormat, Message: "barcode check digit is invalid"}
+	}
+
+	return code, nil
+}
+
+// validCheckDigit verifies the trailing GS1 check digit of a numeric code.
+func validCheckDigit(code string) bool {
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		digit := int(code[i] - '0')
+		if (len(code)-2-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	return (10-sum%10)%10 == int(code[len(code)-1]-'0')
+}
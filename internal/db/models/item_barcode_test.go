@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestNormalizeBarcode(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{name: "EAN-13", code: "4006381333931", want: "4006381333931"},
+		{name: "EAN-8", code: "96385074", want: "96385074"},
+		{name: "UPC-A", code: "036000291452", want: "0036000291452"},
+		{name: "GTIN-14 with leading zero", code: "00036000291452", want: "0036000291452"},
+		{name: "GTIN-14", code: "10036000291459", want: "10036000291459"},
+		{name: "spaces and hyphens", code: "4 006381-333931", want: "4006381333931"},
+		{name: "bad check digit", code: "4006381333932", wantErr: true},
+		{name: "letters", code: "40063813339A1", wantErr: true},
+		{name: "wrong length", code: "12345", wantErr: true},
+		{name: "empty", code: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeBarcode(tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeBarcode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeBarcode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
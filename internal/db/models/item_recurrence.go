@@ -0,0 +1,56 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// maxRecurrenceIntervalDays caps "every N days" rules to one year.
+const maxRecurrenceIntervalDays = 365
+
+func (q *Queries) ValidateItemRecurrence(ctx context.Context, r ItemRecurrence) error {
+	var vErr error
+
+	if r.IntervalDays.Valid == r.Weekday.Valid {
+		vErr = errors.Join(vErr, &FieldError{Err: ErrExclusive, Message: "exactly one of interval_days or weekday must be provided"})
+	}
+
+	if r.IntervalDays.Valid && (r.IntervalDays.Int32 < 1 || r.IntervalDays.Int32 > maxRecurrenceIntervalDays) {
+		vErr = errors.Join(vErr, &FieldError{Field: "interval_days", Err: ErrOutOfRange, Message: "interval_days must be between 1 and 365"})
+	}
+
+	if r.Weekday.Valid && (r.Weekday.Int32 < int32(time.Sunday) || r.Weekday.Int32 > int32(time.Saturday)) {
+		vErr = errors.Join(vErr, &FieldError{Field: "weekday", Err: ErrOutOfRange, Message: "weekday must be between 0 (Sunday) and 6 (Saturday)"})
+	}
+
+	return vErr
+}
+
+// Due reports whether the recurring item should be placed back on the list at
+// the given time. Interval rules are due once N days have passed since the
+// item was last added. Weekday rules are due on that weekday, at most once per
+// day.
+func (r ItemRecurrence) Due(now time.Time) bool {
+	switch {
+	case r.IntervalDays.Valid:
+		if !r.LastAddedAt.Valid {
+			return true
+		}
+		next := r.LastAddedAt.Time.AddDate(0, 0, int(r.IntervalDays.Int32))
+		return !now.Before(next)
+
+	case r.Weekday.Valid:
+		if now.Weekday() != time.Weekday(r.Weekday.Int32) {
+			return false
+		}
+		if !r.LastAddedAt.Valid {
+			return true
+		}
+		ly, lm, ld := r.LastAddedAt.Time.In(now.Location()).Date()
+		ny, nm, nd := now.Date()
+		return ly != ny || lm != nm || ld != nd
+	}
+
+	return false
+}
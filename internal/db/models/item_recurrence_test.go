@@ -0,0 +1,142 @@
+package models
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestItemRecurrence_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		recurrence ItemRecurrence
+		wantErr    bool
+	}{
+		{
+			name:       "every 7 days",
+			recurrence: ItemRecurrence{IntervalDays: sql.NullInt32{Int32: 7, Valid: true}},
+			wantErr:    false,
+		},
+		{
+			name:       "every saturday",
+			recurrence: ItemRecurrence{Weekday: sql.NullInt32{Int32: 6, Valid: true}},
+			wantErr:    false,
+		},
+		{
+			name:       "every sunday",
+			recurrence: ItemRecurrence{Weekday: sql.NullInt32{Int32: 0, Valid: true}},
+			wantErr:    false,
+		},
+		{
+			name:       "no rule",
+			recurrence: ItemRecurrence{},
+			wantErr:    true,
+		},
+		{
+			name: "both rules",
+			recurrence: ItemRecurrence{
+				IntervalDays: sql.NullInt32{Int32: 7, Valid: true},
+				Weekday:      sql.NullInt32{Int32: 1, Valid: true},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "zero interval",
+			recurrence: ItemRecurrence{IntervalDays: sql.NullInt32{Int32: 0, Valid: true}},
+			wantErr:    true,
+		},
+		{
+			name:       "interval over a year",
+			recurrence: ItemRecurrence{IntervalDays: sql.NullInt32{Int32: 366, Valid: true}},
+			wantErr:    true,
+		},
+		{
+			name:       "weekday out of range",
+			recurrence: ItemRecurrence{Weekday: sql.NullInt32{Int32: 7, Valid: true}},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := Queries{}
+			err := q.ValidateItemRecurrence(t.Context(), tt.recurrence)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ItemRecurrence.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestItemRecurrence_Due(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, time.October, 14, 9, 0, 0, 0, time.UTC)
+	at := func(t time.Time) sql.NullTime { return sql.NullTime{Time: t, Valid: true} }
+
+	tests := []struct {
+		name       string
+		recurrence ItemRecurrence
+		want       bool
+	}{
+		{
+			name:       "interval never added",
+			recurrence: ItemRecurrence{IntervalDays: sql.NullInt32{Int32: 7, Valid: true}},
+			want:       true,
+		},
+		{
+			name: "interval not yet elapsed",
+			recurrence: ItemRecurrence{
+				IntervalDays: sql.NullInt32{Int32: 7, Valid: true},
+				LastAddedAt:  at(now.AddDate(0, 0, -6)),
+			},
+			want: false,
+		},
+		{
+			name: "interval exactly elapsed",
+			recurrence: ItemRecurrence{
+				IntervalDays: sql.NullInt32{Int32: 7, Valid: true},
+				LastAddedAt:  at(now.AddDate(0, 0, -7)),
+			},
+			want: true,
+		},
+		{
+			name:       "weekday matches, never added",
+			recurrence: ItemRecurrence{Weekday: sql.NullInt32{Int32: int32(time.Wednesday), Valid: true}},
+			want:       true,
+		},
+		{
+			name:       "weekday does not match",
+			recurrence: ItemRecurrence{Weekday: sql.NullInt32{Int32: int32(time.Thursday), Valid: true}},
+			want:       false,
+		},
+		{
+			name: "weekday already added today",
+			recurrence: ItemRecurrence{
+				Weekday:     sql.NullInt32{Int32: int32(time.Wednesday), Valid: true},
+				LastAddedAt: at(now.Add(-2 * time.Hour)),
+			},
+			want: false,
+		},
+		{
+			name: "weekday added last week",
+			recurrence: ItemRecurrence{
+				Weekday:     sql.NullInt32{Int32: int32(time.Wednesday), Valid: true},
+				LastAddedAt: at(now.AddDate(0, 0, -7)),
+			},
+			want: true,
+		},
+		{
+			name:       "no rule",
+			recurrence: ItemRecurrence{},
+			want:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.recurrence.Due(now); got != tt.want {
+				t.Errorf("ItemRecurrence.Due() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+const (
+	PantryLocationFridge  = "fridge"
+	PantryLocationFreezer = "freezer"
+	PantryLocationPantry  = "pantry"
+)
+
+// ExpiringSoonDays is how far ahead of its best-before date a pantry item is
+// considered to be expiring soon.
+const ExpiringSoonDays = 3
+
+// PantryLocations lists the places a pantry item may be stored.
+var PantryLocations = []string{PantryLocationFridge, PantryLocationFreezer, PantryLocationPantry}
+
+func (q *Queries) ValidatePantryItem(ctx context.Context, p PantryItem) error {
+	var vErr error
+
+	if p.Quantity < 0 {
+		vErr = errors.Join(vErr, &FieldError{Field: "quantity", Err: ErrOutOfRange, Message: "quantity cannot be negative"})
+	}
+
+	if p.RestockThreshold < 0 {
+		vErr = errors.Join(vErr, &FieldError{Field: "restock_threshold", Err: ErrOutOfRange, Message: "restock_threshold cannot be negative"})
+	}
+
+	if !slices.Contains(PantryLocations, p.Location) {
+		vErr = errors.Join(vErr, &FieldError{Field: "location", Err: ErrInvalidChoice, Message: "location must be one of fridge, freezer or pantry"})
+	}
+
+	return vErr
+}
+
+// NeedsRestock reports whether the stock on hand has dropped below the item's
+// restock threshold.
+func (p PantryItem) NeedsRestock() bool {
+	return p.Quantity < p.RestockThreshold
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestPantryItem_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    PantryItem
+		wantErr bool
+	}{
+		{
+			name:    "valid pantry item",
+			item:    PantryItem{Quantity: 2, Location: PantryLocationPantry},
+			wantErr: false,
+		},
+		{
+			name:    "valid freezer item with threshold",
+			item:    PantryItem{Quantity: 0, Location: PantryLocationFreezer, RestockThreshold: 1},
+			wantErr: false,
+		},
+		{
+			name:    "negative quantity",
+			item:    PantryItem{Quantity: -1, Location: PantryLocationFridge},
+			wantErr: true,
+		},
+		{
+			name:    "negative threshold",
+			item:    PantryItem{Location: PantryLocationFridge, RestockThreshold: -1},
+			wantErr: true,
+		},
+		{
+			name:    "unknown location",
+			item:    PantryItem{Location: "garage"},
+			wantErr: true,
+		},
+		{
+			name:    "empty location",
+			item:    PantryItem{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := Queries{}
+			err := q.ValidatePantryItem(t.Context(), tt.item)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PantryItem.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPantryItem_NeedsRestock(t *testing.T) {
+	tests := []struct {
+		name string
+		item PantryItem
+		want bool
+	}{
+		{name: "no threshold", item: PantryItem{Quantity: 0}, want: false},
+		{name: "below threshold", item: PantryItem{Quantity: 1, RestockThreshold: 2}, want: true},
+		{name: "at threshold", item: PantryItem{Quantity: 2, RestockThreshold: 2}, want: false},
+		{name: "above threshold", item: PantryItem{Quantity: 5, RestockThreshold: 2}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.item.NeedsRestock(); got != tt.want {
+				t.Errorf("PantryItem.NeedsRestock() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,7 +8,7 @@ import (
 func (q *Queries) ValidateUser(ctx context.Context, u User) error {
 	var vErr error
 	if u.Name == "" {
-		vErr = errors.Join(vErr, errors.New("username must be valid"))
+		vErr = errors.Join(vErr, &FieldError{Field: "name", Err: ErrRequired, Message: "username must be valid"})
 	}
 
 	return vErr
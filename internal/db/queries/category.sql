@@ -19,10 +19,25 @@ FROM category
 WHERE store_id = $1
 ORDER BY name;
 
--- name: ListCategoriesWithItemCount :many
-SELECT *, (SELECT COUNT(item.id) FROM item WHERE item.category_id = category.id) as item_count
+-- ListCategoriesPage returns a page of categories with their item counts.
+-- search filters by name, and sort is one of name, -name, id and -id. Pages
+-- after the first start after the row identified by after_name and after_id.
+-- name: ListCategoriesPage :many
+SELECT category.*, (SELECT COUNT(item.id) FROM item WHERE item.category_id = category.id) AS item_count
 FROM category
-ORDER BY category.name;
+WHERE (sqlc.arg(search)::TEXT = '' OR category.name ILIKE '%' || sqlc.arg(search)::TEXT || '%')
+    AND (sqlc.narg(after_id)::INTEGER IS NULL OR CASE sqlc.arg(sort)::TEXT
+        WHEN 'name' THEN (category.name, category.id) > (sqlc.arg(after_name)::TEXT, sqlc.narg(after_id)::INTEGER)
+        WHEN '-name' THEN (category.name, category.id) < (sqlc.arg(after_name)::TEXT, sqlc.narg(after_id)::INTEGER)
+        WHEN 'id' THEN category.id > sqlc.narg(after_id)::INTEGER
+        ELSE category.id < sqlc.narg(after_id)::INTEGER
+    END)
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::TEXT = 'name' THEN category.name END ASC,
+    CASE WHEN sqlc.arg(sort)::TEXT = '-name' THEN category.name END DESC,
+    CASE WHEN sqlc.arg(sort)::TEXT IN ('name', 'id') THEN category.id END ASC,
+    category.id DESC
+LIMIT sqlc.arg(page_limit);
 
 -- name: CreateCategory :one
 INSERT INTO category (name, store_id, description)
@@ -33,10 +48,26 @@ RETURNING *;
 UPDATE category SET
   name = $2,
   store_id = $3,
-  description = $4
+  description = $4,
+  version = version + 1
 WHERE id = $1
 RETURNING *;
 
 -- name: DeleteCategory :exec
 DELETE FROM category
 WHERE id = $1;
+
+-- name: GetCategoryForStoreByName :one
+SELECT *
+FROM category
+WHERE store_id = $1 AND name = $2 LIMIT 1;
+
+-- name: GetCategoryWithItemCount :one
+SELECT *, (SELECT COUNT(item.id) FROM item WHERE item.category_id = category.id) as item_count
+FROM category
+WHERE category.id = $1 LIMIT 1;
+
+-- name: LockCategoryVersion :one
+SELECT version FROM category
+WHERE id = $1
+FOR UPDATE;
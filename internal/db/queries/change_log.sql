@@ -0,0 +1,73 @@
+-- name: RecordChange :exec
+INSERT INTO change_log (entity, entity_id, op, field, changed_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: LatestChange :one
+SELECT changed_at FROM change_log
+WHERE entity = sqlc.arg(entity) AND entity_id = sqlc.arg(entity_id)
+    AND (sqlc.arg(field)::TEXT = '' OR field = '' OR field = sqlc.arg(field)::TEXT)
+ORDER BY changed_at DESC
+LIMIT 1;
+
+-- name: ListChangesSince :many
+SELECT latest.seq, latest.entity, latest.entity_id, latest.op, latest.field, latest.changed_at,
+    EXISTS (
+        SELECT 1 FROM change_log AS created
+        WHERE created.entity = latest.entity AND created.entity_id = latest.entity_id
+            AND created.seq > sqlc.arg(since) AND created.op = 'create'
+    ) AS created
+FROM (
+    SELECT DISTINCT ON (change_log.entity, change_log.entity_id) change_log.*
+    FROM change_log
+    WHERE change_log.seq > sqlc.arg(since)
+    ORDER BY change_log.entity, change_log.entity_id, change_log.seq DESC
+) AS latest
+ORDER BY latest.seq
+LIMIT sqlc.arg(max_changes);
+
+-- The Changed* queries load the entities with a change in the log between
+-- the since and until sequence numbers, so that a page of the change log is
+-- loaded with one query per entity.
+
+-- name: SummarizeChangedItems :many
+SELECT item.id, item.name, item.category_id, category.name AS category_name,
+	item_list.id AS list_id, item_list.quantity AS list_quantity, item_list.done AS list_done,
+	item.version, item_list.version AS list_version
+FROM item
+LEFT JOIN category ON (item.category_id = category.id)
+LEFT JOIN item_list ON (item_list.item_id = item.id)
+WHERE item.id IN (
+    SELECT entity_id FROM change_log
+    WHERE entity IN ('item', 'list_item') AND seq > sqlc.arg(since) AND seq <= sqlc.arg(until)
+);
+
+-- name: ListChangedItemAliases :many
+SELECT item_id, name FROM item_alias
+WHERE item_id IN (
+    SELECT entity_id FROM change_log
+    WHERE entity = 'item' AND seq > sqlc.arg(since) AND seq <= sqlc.arg(until)
+)
+ORDER BY item_id, name;
+
+-- name: ListChangedItemBarcodes :many
+SELECT item_id, code FROM item_barcode
+WHERE item_id IN (
+    SELECT entity_id FROM change_log
+    WHERE entity = 'item' AND seq > sqlc.arg(since) AND seq <= sqlc.arg(until)
+)
+ORDER BY item_id, code;
+
+-- name: ListChangedCategories :many
+SELECT *, (SELECT COUNT(item.id) FROM item WHERE item.category_id = category.id) AS item_count
+FROM category
+WHERE category.id IN (
+    SELECT entity_id FROM change_log
+    WHERE entity = 'category' AND seq > sqlc.arg(since) AND seq <= sqlc.arg(until)
+);
+
+-- name: ListChangedStores :many
+SELECT * FROM store
+WHERE store.id IN (
+    SELECT entity_id FROM change_log
+    WHERE entity = 'store' AND seq > sqlc.arg(since) AND seq <= sqlc.arg(until)
+);
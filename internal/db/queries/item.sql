@@ -7,7 +7,7 @@ SELECT * FROM item
 WHERE name = $1 LIMIT 1;
 
 -- name: SummarizeItem :one
-SELECT item.id, item.category_id, item.name, category.name AS category_name, item_list.id AS list_id
+SELECT item.id, item.category_id, item.name, category.name AS category_name, item_list.id AS list_id, item.version
 FROM item
 LEFT JOIN category ON (item.category_id = category.id)
 LEFT JOIN item_list ON (item_list.item_id = item.id)
@@ -24,12 +24,40 @@ ORDER BY name;
 
 -- name: SummarizeItems :many
 SELECT item.id, item.name, item.category_id, category.name AS category_name,
-	item_list.id AS list_id, item_list.quantity AS list_quantity, item_list.done AS list_done
+	item_list.id AS list_id, item_list.quantity AS list_quantity, item_list.done AS list_done,
+	item.version, item_list.version AS list_version
 FROM item
 LEFT JOIN category ON (item.category_id = category.id)
 LEFT JOIN item_list ON (item_list.item_id = item.id)
 ORDER BY category.name, item.name;
 
+-- SummarizeItemsPage returns a page of items, optionally filtered by category
+-- and by whether they are on the list. search filters by name, and sort is one
+-- of name, -name, id and -id. Pages after the first start after the row
+-- identified by after_name and after_id.
+-- name: SummarizeItemsPage :many
+SELECT item.id, item.name, item.category_id, category.name AS category_name,
+	item_list.id AS list_id, item_list.quantity AS list_quantity, item_list.done AS list_done,
+	item.version, item_list.version AS list_version
+FROM item
+LEFT JOIN category ON (item.category_id = category.id)
+LEFT JOIN item_list ON (item_list.item_id = item.id)
+WHERE (sqlc.narg(category_id)::INTEGER IS NULL OR item.category_id = sqlc.narg(category_id)::INTEGER)
+    AND (sqlc.narg(in_list)::BOOLEAN IS NULL OR (item_list.id IS NOT NULL) = sqlc.narg(in_list)::BOOLEAN)
+    AND (sqlc.arg(search)::TEXT = '' OR item.name ILIKE '%' || sqlc.arg(search)::TEXT || '%')
+    AND (sqlc.narg(after_id)::INTEGER IS NULL OR CASE sqlc.arg(sort)::TEXT
+        WHEN 'name' THEN (item.name, item.id) > (sqlc.arg(after_name)::TEXT, sqlc.narg(after_id)::INTEGER)
+        WHEN '-name' THEN (item.name, item.id) < (sqlc.arg(after_name)::TEXT, sqlc.narg(after_id)::INTEGER)
+        WHEN 'id' THEN item.id > sqlc.narg(after_id)::INTEGER
+        ELSE item.id < sqlc.narg(after_id)::INTEGER
+    END)
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::TEXT = 'name' THEN item.name END ASC,
+    CASE WHEN sqlc.arg(sort)::TEXT = '-name' THEN item.name END DESC,
+    CASE WHEN sqlc.arg(sort)::TEXT IN ('name', 'id') THEN item.id END ASC,
+    item.id DESC
+LIMIT sqlc.arg(page_limit);
+
 -- name: CreateItem :one
 INSERT INTO item (category_id, name)
 VALUES ($1, $2)
@@ -38,10 +66,43 @@ RETURNING *;
 -- name: UpdateItem :one
 UPDATE item SET
   category_id = $2,
-  name = $3
+  name = $3,
+  version = version + 1
 WHERE id = $1
 RETURNING *;
 
 -- name: DeleteItem :exec
 DELETE FROM item
 WHERE id = $1;
+
+-- name: FindItemByNameOrAlias :one
+SELECT item.id, item.category_id, item.name, category.name AS category_name, item.version,
+	EXISTS (SELECT 1 FROM item_list WHERE item_list.item_id = item.id) AS in_list
+FROM item
+LEFT JOIN category ON (item.category_id = category.id)
+WHERE lower(item.name) = lower(sqlc.arg(name))
+	OR item.id IN (SELECT item_id FROM item_alias WHERE lower(item_alias.name) = lower(sqlc.arg(name)))
+ORDER BY lower(item.name) = lower(sqlc.arg(name)) DESC, item.name = sqlc.arg(name) DESC
+LIMIT 1;
+
+-- name: BumpItemVersion :exec
+UPDATE item SET version = version + 1
+WHERE id = $1;
+
+-- name: UpdateItemCategory :exec
+UPDATE item SET category_id = $2, version = version + 1
+WHERE id = $1;
+
+-- name: CountItemsForCategory :one
+SELECT COUNT(*) FROM item
+WHERE category_id = $1;
+
+-- name: LockItemName :one
+SELECT name FROM item
+WHERE id = $1
+FOR UPDATE;
+
+-- name: LockItemVersion :one
+SELECT version FROM item
+WHERE id = $1
+FOR UPDATE;
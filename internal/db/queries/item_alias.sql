@@ -0,0 +1,34 @@
+-- name: ListItemAliases :many
+SELECT * FROM item_alias
+ORDER BY item_id, name;
+
+-- name: RestoreItemAlias :exec
+INSERT INTO item_alias (item_id, name)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: ListAliasesForItem :many
+SELECT name FROM item_alias
+WHERE item_id = $1
+ORDER BY name;
+
+-- name: AliasConflictsWithOtherItem :one
+SELECT EXISTS (SELECT 1 FROM item WHERE lower(item.name) = lower(sqlc.arg(name)) AND item.id <> sqlc.arg(item_id))
+	OR EXISTS (SELECT 1 FROM item_alias WHERE lower(item_alias.name) = lower(sqlc.arg(name)) AND item_alias.item_id <> sqlc.arg(item_id));
+
+-- name: CreateItemAlias :exec
+INSERT INTO item_alias (item_id, name)
+VALUES ($1, $2);
+
+-- name: DeleteItemAliases :exec
+DELETE FROM item_alias
+WHERE item_id = $1;
+
+-- name: MoveItemAliases :exec
+UPDATE item_alias SET item_id = sqlc.arg(target_id)
+WHERE item_id = sqlc.arg(source_id);
+
+-- name: CreateItemAliasIfUnused :exec
+INSERT INTO item_alias (item_id, name)
+SELECT sqlc.arg(item_id), sqlc.arg(name)::TEXT
+WHERE NOT EXISTS (SELECT 1 FROM item_alias WHERE lower(item_alias.name) = lower(sqlc.arg(name)::TEXT));
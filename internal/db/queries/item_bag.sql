@@ -0,0 +1,11 @@
+-- Bagged items predate the shopping list. They are only moved when items are
+-- merged.
+
+-- name: MoveItemBag :exec
+UPDATE item_bag SET item_id = sqlc.arg(target_id)
+WHERE item_bag.item_id = sqlc.arg(source_id)
+	AND NOT EXISTS (SELECT 1 FROM item_bag AS existing WHERE existing.item_id = sqlc.arg(target_id));
+
+-- name: DeleteItemBag :exec
+DELETE FROM item_bag
+WHERE item_id = $1;
@@ -0,0 +1,30 @@
+-- name: GetItemBarcode :one
+SELECT * FROM item_barcode
+WHERE code = $1 LIMIT 1;
+
+-- name: ListItemBarcodes :many
+SELECT * FROM item_barcode
+WHERE item_id = $1
+ORDER BY code;
+
+-- name: CreateItemBarcode :one
+INSERT INTO item_barcode (item_id, code)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: DeleteItemBarcode :execrows
+DELETE FROM item_barcode
+WHERE item_id = $1 AND code = $2;
+
+-- name: ListAllItemBarcodes :many
+SELECT * FROM item_barcode
+ORDER BY item_id, code;
+
+-- name: RestoreItemBarcode :exec
+INSERT INTO item_barcode (item_id, code)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: MoveItemBarcodes :exec
+UPDATE item_barcode SET item_id = sqlc.arg(target_id)
+WHERE item_id = sqlc.arg(source_id);
@@ -1,12 +1,13 @@
 -- name: GetListItem :one
-SELECT item_list.id, item_list.item_id, item.name, item.category_id, item_list.quantity, item_list.done
+SELECT item_list.id, item_list.item_id, item.name, item.category_id, item_list.quantity, item_list.done, item_list.version
 FROM item_list
 INNER JOIN item ON (item_list.item_id = item.id)
 WHERE item_list.id = $1;
 
 -- name: LoadList :many
 SELECT item.id, item.name, item.category_id, category.name AS category_name,
-	item_list.quantity AS list_quantity, item_list.id AS list_id, item_list.done AS list_done
+	item_list.quantity AS list_quantity, item_list.id AS list_id, item_list.done AS list_done,
+	item.version, item_list.version AS list_version
 FROM item_list
 INNER JOIN item ON (item.id = item_list.item_id)
 INNER JOIN category ON (item.category_id = category.id)
@@ -20,12 +21,13 @@ RETURNING *;
 -- name: UpdateListItem :one
 UPDATE item_list SET
     quantity = $2,
-    done = $3
+    done = $3,
+    version = version + 1
 WHERE item_id = $1
 RETURNING *;
 
 -- name: MarkItemDone :one
-UPDATE item_list SET done = $2
+UPDATE item_list SET done = $2, version = version + 1
 WHERE item_id = $1
 RETURNING *;
 
@@ -36,3 +38,45 @@ WHERE id = $1;
 -- name: FinishShopping :exec
 DELETE FROM item_list
 WHERE done = TRUE;
+
+-- name: ListListItems :many
+SELECT * FROM item_list
+ORDER BY id;
+
+-- name: RestoreListItem :execrows
+INSERT INTO item_list (item_id, quantity, done)
+VALUES ($1, $2, $3)
+ON CONFLICT (item_id) DO NOTHING;
+
+-- name: AddListItemIfMissing :one
+INSERT INTO item_list (item_id, quantity)
+VALUES ($1, $2)
+ON CONFLICT (item_id) DO NOTHING
+RETURNING id;
+
+-- name: GetListItemForItem :one
+SELECT * FROM item_list
+WHERE item_id = $1 LIMIT 1;
+
+-- name: UpdateListItemQuantity :one
+UPDATE item_list SET quantity = $2, version = version + 1
+WHERE item_id = $1
+RETURNING *;
+
+-- name: DeleteListItemForItem :execrows
+DELETE FROM item_list
+WHERE item_id = $1;
+
+-- name: ListDoneListItems :many
+SELECT * FROM item_list
+WHERE done = TRUE
+ORDER BY id;
+
+-- name: MoveListItem :exec
+UPDATE item_list SET item_id = sqlc.arg(target_id), version = version + 1
+WHERE item_id = sqlc.arg(source_id);
+
+-- name: LockListItemVersion :one
+SELECT id, version FROM item_list
+WHERE item_id = $1
+FOR UPDATE;
@@ -0,0 +1,40 @@
+-- name: GetItemRecurrence :one
+SELECT * FROM item_recurrence
+WHERE item_id = $1 LIMIT 1;
+
+-- name: ListItemRecurrences :many
+SELECT * FROM item_recurrence
+ORDER BY item_id;
+
+-- name: ListItemRecurrencesOffList :many
+SELECT * FROM item_recurrence
+WHERE NOT EXISTS (SELECT 1 FROM item_list WHERE item_list.item_id = item_recurrence.item_id)
+ORDER BY item_id;
+
+-- name: UpsertItemRecurrence :one
+INSERT INTO item_recurrence (item_id, interval_days, weekday, quantity)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (item_id) DO UPDATE SET
+  interval_days = EXCLUDED.interval_days,
+  weekday = EXCLUDED.weekday,
+  quantity = EXCLUDED.quantity
+RETURNING *;
+
+-- name: MarkItemRecurrenceAdded :exec
+UPDATE item_recurrence SET last_added_at = $2
+WHERE item_id = $1;
+
+-- name: DeleteItemRecurrence :exec
+DELETE FROM item_recurrence
+WHERE item_id = $1;
+
+-- name: RestoreItemRecurrence :exec
+INSERT INTO item_recurrence (item_id, interval_days, weekday, quantity, last_added_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (item_id) DO NOTHING;
+
+-- name: MoveItemRecurrence :exec
+-- The target's own schedule wins over the source's
+UPDATE item_recurrence SET item_id = sqlc.arg(target_id)
+WHERE item_recurrence.item_id = sqlc.arg(source_id)
+	AND NOT EXISTS (SELECT 1 FROM item_recurrence AS existing WHERE existing.item_id = sqlc.arg(target_id));
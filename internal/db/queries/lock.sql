@@ -0,0 +1,5 @@
+-- name: TryAdvisoryXactLock :one
+SELECT pg_try_advisory_xact_lock(sqlc.arg(lock_id)::BIGINT);
+
+-- name: AdvisoryXactLock :exec
+SELECT pg_advisory_xact_lock(sqlc.arg(lock_id)::BIGINT);
@@ -0,0 +1,76 @@
+-- name: GetPantryItem :one
+SELECT sqlc.embed(pantry_item), item.name AS item_name
+FROM pantry_item
+INNER JOIN item ON (item.id = pantry_item.item_id)
+WHERE pantry_item.item_id = $1 LIMIT 1;
+
+-- name: ListPantryItems :many
+SELECT sqlc.embed(pantry_item), item.name AS item_name
+FROM pantry_item
+INNER JOIN item ON (item.id = pantry_item.item_id)
+ORDER BY pantry_item.location, item.name;
+
+-- name: UpsertPantryItem :one
+INSERT INTO pantry_item (item_id, quantity, location, best_before, restock_threshold)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (item_id) DO UPDATE SET
+  quantity = EXCLUDED.quantity,
+  location = EXCLUDED.location,
+  best_before = EXCLUDED.best_before,
+  restock_threshold = EXCLUDED.restock_threshold
+RETURNING *;
+
+-- name: ConsumePantryItem :one
+UPDATE pantry_item SET
+  quantity = GREATEST(quantity - sqlc.arg(amount)::INTEGER, 0)
+WHERE item_id = $1
+RETURNING *;
+
+-- name: DeletePantryItem :exec
+DELETE FROM pantry_item
+WHERE item_id = $1;
+
+-- name: ListExpiringPantryItems :many
+SELECT sqlc.embed(pantry_item), item.name AS item_name
+FROM pantry_item
+INNER JOIN item ON (item.id = pantry_item.item_id)
+WHERE pantry_item.best_before <= sqlc.arg(before)::DATE
+  AND pantry_item.quantity > 0
+ORDER BY pantry_item.best_before, item.name;
+
+-- name: RestorePantryItem :exec
+INSERT INTO pantry_item (item_id, quantity, location, best_before, restock_threshold)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (item_id) DO NOTHING;
+
+-- name: AddPantryStock :exec
+INSERT INTO pantry_item (item_id, quantity)
+VALUES ($1, $2)
+ON CONFLICT (item_id) DO UPDATE SET
+  quantity = pantry_item.quantity + EXCLUDED.quantity;
+
+-- name: MergePantryStock :exec
+-- Stock on hand is combined, keeping the earliest best-before date
+UPDATE pantry_item AS target SET
+  quantity = target.quantity + source.quantity,
+  best_before = LEAST(target.best_before, source.best_before)
+FROM pantry_item AS source
+WHERE target.item_id = sqlc.arg(target_id) AND source.item_id = sqlc.arg(source_id);
+
+-- name: MovePantryItem :exec
+UPDATE pantry_item SET item_id = sqlc.arg(target_id)
+WHERE pantry_item.item_id = sqlc.arg(source_id)
+	AND NOT EXISTS (SELECT 1 FROM pantry_item AS existing WHERE existing.item_id = sqlc.arg(target_id));
+
+-- MarkExpiringPantryItems records the pantry items nearing their best-before
+-- date that clients have not been told about yet, and forgets those that are
+-- no longer expiring, returning every item whose state changed.
+-- name: MarkExpiringPantryItems :many
+UPDATE pantry_item SET
+  expiring_notified_for = CASE
+    WHEN best_before <= sqlc.arg(before)::DATE AND quantity > 0 THEN best_before
+  END
+WHERE expiring_notified_for IS DISTINCT FROM CASE
+    WHEN best_before <= sqlc.arg(before)::DATE AND quantity > 0 THEN best_before
+  END
+RETURNING item_id;
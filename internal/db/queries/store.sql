@@ -10,6 +10,25 @@ WHERE name = $1 LIMIT 1;
 SELECT * FROM store
 ORDER BY name;
 
+-- ListStoresPage returns a page of stores. search filters by name, and
+-- sort is one of name, -name, id and -id. Pages after the first start after
+-- the row identified by after_name and after_id.
+-- name: ListStoresPage :many
+SELECT store.* FROM store
+WHERE (sqlc.arg(search)::TEXT = '' OR store.name ILIKE '%' || sqlc.arg(search)::TEXT || '%')
+    AND (sqlc.narg(after_id)::INTEGER IS NULL OR CASE sqlc.arg(sort)::TEXT
+        WHEN 'name' THEN (store.name, store.id) > (sqlc.arg(after_name)::TEXT, sqlc.narg(after_id)::INTEGER)
+        WHEN '-name' THEN (store.name, store.id) < (sqlc.arg(after_name)::TEXT, sqlc.narg(after_id)::INTEGER)
+        WHEN 'id' THEN store.id > sqlc.narg(after_id)::INTEGER
+        ELSE store.id < sqlc.narg(after_id)::INTEGER
+    END)
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::TEXT = 'name' THEN store.name END ASC,
+    CASE WHEN sqlc.arg(sort)::TEXT = '-name' THEN store.name END DESC,
+    CASE WHEN sqlc.arg(sort)::TEXT IN ('name', 'id') THEN store.id END ASC,
+    store.id DESC
+LIMIT sqlc.arg(page_limit);
+
 -- name: CreateStore :one
 INSERT INTO store (name)
 VALUES ($1)
@@ -10,6 +10,25 @@ WHERE name = $1 LIMIT 1;
 SELECT * FROM "user"
 ORDER BY name;
 
+-- ListUsersPage returns a page of users. search filters by name, and
+-- sort is one of name, -name, id and -id. Pages after the first start after
+-- the row identified by after_name and after_id.
+-- name: ListUsersPage :many
+SELECT "user".* FROM "user"
+WHERE (sqlc.arg(search)::TEXT = '' OR "user".name ILIKE '%' || sqlc.arg(search)::TEXT || '%')
+    AND (sqlc.narg(after_id)::INTEGER IS NULL OR CASE sqlc.arg(sort)::TEXT
+        WHEN 'name' THEN ("user".name, "user".id) > (sqlc.arg(after_name)::TEXT, sqlc.narg(after_id)::INTEGER)
+        WHEN '-name' THEN ("user".name, "user".id) < (sqlc.arg(after_name)::TEXT, sqlc.narg(after_id)::INTEGER)
+        WHEN 'id' THEN "user".id > sqlc.narg(after_id)::INTEGER
+        ELSE "user".id < sqlc.narg(after_id)::INTEGER
+    END)
+ORDER BY
+    CASE WHEN sqlc.arg(sort)::TEXT = 'name' THEN "user".name END ASC,
+    CASE WHEN sqlc.arg(sort)::TEXT = '-name' THEN "user".name END DESC,
+    CASE WHEN sqlc.arg(sort)::TEXT IN ('name', 'id') THEN "user".id END ASC,
+    "user".id DESC
+LIMIT sqlc.arg(page_limit);
+
 -- name: CreateUser :one
 INSERT INTO "user" (name, admin)
 VALUES ($1, $2)
@@ -38,3 +57,12 @@ VALUES ($1, $2);
 -- name: RemoveUserFromGroup :exec
 DELETE FROM user_group
 WHERE user_id = $1 AND group_id = $2;
+
+-- name: ListUserGroups :many
+SELECT * FROM user_group
+ORDER BY user_id, group_id;
+
+-- name: RestoreUserGroup :exec
+INSERT INTO user_group (user_id, group_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
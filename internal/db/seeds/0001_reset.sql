@@ -1,7 +1,17 @@
 -- +goose Up
 -- +goose StatementBegin
+DELETE FROM change_log;
+ALTER SEQUENCE change_log_seq_seq RESTART WITH 1;
 DELETE FROM item_bag;
 ALTER SEQUENCE item_bag_id_seq RESTART WITH 1;
+DELETE FROM pantry_item;
+ALTER SEQUENCE pantry_item_id_seq RESTART WITH 1;
+DELETE FROM item_alias;
+ALTER SEQUENCE item_alias_id_seq RESTART WITH 1;
+DELETE FROM item_barcode;
+ALTER SEQUENCE item_barcode_id_seq RESTART WITH 1;
+DELETE FROM item_recurrence;
+ALTER SEQUENCE item_recurrence_id_seq RESTART WITH 1;
 DELETE FROM item_list;
 ALTER SEQUENCE item_list_id_seq RESTART WITH 1;
 DELETE FROM item;
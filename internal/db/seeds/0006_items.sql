@@ -78,6 +78,11 @@ INSERT INTO item_list (item_id, quantity, done) VALUES
 (8, '1.5oz', FALSE),
 (9, '0.5lb', FALSE);
 
+INSERT INTO item_alias (item_id, name) VALUES
+((SELECT id FROM item WHERE name = 'Scallions'), 'Green onions'),
+((SELECT id FROM item WHERE name = 'Cilantro'), 'Coriander leaves'),
+((SELECT id FROM item WHERE name = 'Arugula'), 'Rocket');
+
 -- +goose StatementEnd
 
 -- +goose Down
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The seeds insert rows directly, so record them as created for clients
+-- reading the change log from the start
+INSERT INTO change_log (entity, entity_id, op, changed_at)
+SELECT 'store', id, 'create', now() FROM store ORDER BY id;
+
+INSERT INTO change_log (entity, entity_id, op, changed_at)
+SELECT 'category', id, 'create', now() FROM category ORDER BY id;
+
+INSERT INTO change_log (entity, entity_id, op, changed_at)
+SELECT 'item', id, 'create', now() FROM item ORDER BY id;
+
+INSERT INTO change_log (entity, entity_id, op, changed_at)
+SELECT 'list_item', item_id, 'create', now() FROM item_list ORDER BY item_id;
+-- +goose StatementEnd
+
+-- +goose Down
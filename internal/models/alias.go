@@ -0,0 +1,69 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+// ErrAliasConflict is returned when an alias is already used as the name or
+// alias of a different item.
+var ErrAliasConflict = errors.New("alias conflicts with another item")
+
+// NormalizeAliases trims the given aliases and drops blanks, duplicates and
+// any alias that merely repeats the item's own name.
+func NormalizeAliases(name string, aliases []string) []string {
+	ret := []string{}
+	seen := map[string]bool{strings.ToLower(strings.TrimSpace(name)): true}
+
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		key := strings.ToLower(alias)
+		if alias == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		ret = append(ret, alias)
+	}
+
+	return ret
+}
+
+// SetItemAliases replaces the aliases of an item. Aliases are matched
+// case-insensitively and must not collide with the name or aliases of any
+// other item.
+func (r *Repository) SetItemAliases(ctx context.Context, id int, aliases []string) error {
+	return r.InTx(ctx, func(tx *Repository) error {
+		return setItemAliases(ctx, tx.q, int32(id), aliases)
+	})
+}
+
+func setItemAliases(ctx context.Context, q *dbmodels.Queries, id int32, aliases []string) error {
+	for _, alias := range aliases {
+		exists, err := q.AliasConflictsWithOtherItem(ctx, dbmodels.AliasConflictsWithOtherItemParams{
+			Name:   alias,
+			ItemID: id,
+		})
+		if err != nil {
+			return err
+		}
+		if exists.Bool {
+			return fmt.Errorf("%w: %q", ErrAliasConflict, alias)
+		}
+	}
+
+	if err := q.DeleteItemAliases(ctx, id); err != nil {
+		return err
+	}
+
+	for _, alias := range aliases {
+		if err := q.CreateItemAlias(ctx, dbmodels.CreateItemAliasParams{ItemID: id, Name: alias}); err != nil {
+			return err
+		}
+	}
+
+	return recordChange(ctx, q, EntityItem, id, ChangeUpdate)
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    string
+		aliases []string
+		want    []string
+	}{
+		{name: "nil", item: "Scallions", aliases: nil, want: []string{}},
+		{name: "trims and drops blanks", item: "Scallions", aliases: []string{" Green onions ", "", "  "}, want: []string{"Green onions"}},
+		{name: "drops duplicates case-insensitively", item: "Scallions", aliases: []string{"Green onions", "green Onions", "Spring onions"}, want: []string{"Green onions", "Spring onions"}},
+		{name: "drops the item's own name", item: "Scallions", aliases: []string{"scallions", "Green onions"}, want: []string{"Green onions"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeAliases(tt.item, tt.aliases); !slices.Equal(got, tt.want) {
+				t.Errorf("NormalizeAliases() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+// ErrBarcodeAssigned is returned when assigning a barcode that already
+// belongs to a different item.
+var ErrBarcodeAssigned = errors.New("barcode is already assigned to another item")
+
+// ItemByBarcode returns the item a normalized barcode has been assigned to, or
+// sql.ErrNoRows if the barcode is unknown.
+func (r *Repository) ItemByBarcode(ctx context.Context, code string) (Item, error) {
+	barcode, err := r.q.GetItemBarcode(ctx, code)
+	if err != nil {
+		return Item{}, err
+	}
+
+	return r.GetItem(ctx, int(barcode.ItemID))
+}
+
+// GetBarcode returns a normalized barcode along with the item it belongs to.
+func (r *Repository) GetBarcode(ctx context.Context, code string) (dbmodels.ItemBarcode, error) {
+	return r.q.GetItemBarcode(ctx, code)
+}
+
+// AssignBarcode associates a normalized barcode with an item. Assigning a
+// barcode the item already carries is a no-op, reported by created being false.
+// Otherwise the item's version is bumped and the change recorded.
+func (r *Repository) AssignBarcode(ctx context.Context, itemID int32, code string) (barcode dbmodels.ItemBarcode, created bool, err error) {
+	err = r.InTx(ctx, func(tx *Repository) error {
+		existing, err := tx.q.GetItemBarcode(ctx, code)
+		switch {
+		case err == nil && existing.ItemID == itemID:
+			barcode = existing
+			return nil
+		case err == nil:
+			return ErrBarcodeAssigned
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+
+		barcode, err = tx.q.CreateItemBarcode(ctx, dbmodels.CreateItemBarcodeParams{ItemID: itemID, Code: code})
+		if err != nil {
+			return err
+		}
+
+		created = true
+		return touchItem(ctx, tx.q, itemID)
+	})
+	if dbmodels.IsDuplicate(err) {
+		// Another request assigned the barcode after it was checked
+		err = ErrBarcodeAssigned
+	}
+	if err != nil {
+		return dbmodels.ItemBarcode{}, false, err
+	}
+
+	return barcode, created, nil
+}
+
+// RemoveBarcode takes a normalized barcode off an item, bumping the item's
+// version and recording the change when it carried the barcode.
+func (r *Repository) RemoveBarcode(ctx context.Context, itemID int32, code string) error {
+	return r.InTx(ctx, func(tx *Repository) error {
+		removed, err := tx.q.DeleteItemBarcode(ctx, dbmodels.DeleteItemBarcodeParams{ItemID: itemID, Code: code})
+		if err != nil || removed == 0 {
+			return err
+		}
+
+		return touchItem(ctx, tx.q, itemID)
+	})
+}
+
+// touchItem bumps the version of an item whose barcodes changed and records
+// the change, so that clients holding the item see it as stale.
+func touchItem(ctx context.Context, q *dbmodels.Queries, itemID int32) error {
+	if err := q.BumpItemVersion(ctx, itemID); err != nil {
+		return err
+	}
+
+	return recordChange(ctx, q, EntityItem, itemID, ChangeUpdate)
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// TestRepository_AssignBarcodeRace checks that a barcode assigned by another
+// request between the check and the insert is reported as already assigned.
+func TestRepository_AssignBarcodeRace(t *testing.T) {
+	db := &scriptedDB{fail: "CreateItemBarcode", failErr: &pgconn.PgError{Code: "23505"}}
+
+	_, created, err := db.repository().AssignBarcode(context.Background(), 7, "0123456789012")
+	if !errors.Is(err, ErrBarcodeAssigned) {
+		t.Fatalf("AssignBarcode() error = %v, want %v", err, ErrBarcodeAssigned)
+	}
+	if created {
+		t.Errorf("AssignBarcode() created = true, want false")
+	}
+
+	wantLog := []string{"begin", "AdvisoryXactLock", "GetItemBarcode", "CreateItemBarcode", "rollback"}
+	if !reflect.DeepEqual(db.log, wantLog) {
+		t.Errorf("AssignBarcode() ran %q, want %q", db.log, wantLog)
+	}
+}
@@ -2,8 +2,11 @@ package models
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
 )
 
 type Category struct {
@@ -12,19 +15,24 @@ type Category struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	ItemCount   int    `json:"item_count"`
+	Version     int    `json:"version"`
 }
 
 const UncategorizedCategoryID int = 0
 
-func (c *Category) Items(ctx context.Context) ([]Item, error) {
-	items, err := LoadItems(ctx)
+// ErrCategoryInUse is returned when deleting a category that still has items.
+var ErrCategoryInUse = errors.New("category is still in use")
+
+// CategoryItems returns the items filed under a category.
+func (r *Repository) CategoryItems(ctx context.Context, id int) ([]Item, error) {
+	items, err := r.LoadItems(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	var ret []Item
 	for _, item := range items {
-		if item.CategoryID == c.ID {
+		if item.CategoryID == id {
 			ret = append(ret, item)
 		}
 	}
@@ -32,127 +40,158 @@ func (c *Category) Items(ctx context.Context) ([]Item, error) {
 	return ret, nil
 }
 
-func (c *Category) Validate(ctx context.Context) error {
+// Validate checks the category's own fields. ValidateCategory also checks it
+// against the stored categories.
+func (c *Category) Validate() error {
 	var vErr error
 
 	if len(c.Name) < 3 {
-		vErr = errors.Join(vErr, errors.New("provided name needs to be at least 3 characters"))
+		vErr = errors.Join(vErr, &dbmodels.FieldError{Field: "name", Err: dbmodels.ErrTooShort, Message: "provided name needs to be at least 3 characters"})
 	}
 
+	return vErr
+}
+
+// ValidateCategory reports every problem with a category before it is
+// stored, including a new category duplicating an existing one.
+func (r *Repository) ValidateCategory(ctx context.Context, c Category) error {
+	vErr := c.Validate()
+
 	// Check for existing category
 	if c.ID == 0 {
-		if existingCategories, err := LoadCategories(ctx); err != nil {
+		_, err := r.q.GetCategoryForStoreByName(ctx, dbmodels.GetCategoryForStoreByNameParams{
+			StoreID: int32(c.StoreID),
+			Name:    c.Name,
+		})
+		if err == nil {
+			vErr = errors.Join(vErr, &dbmodels.FieldError{Field: "name", Err: dbmodels.ErrDuplicate, Message: "category already exists"})
+		} else if !errors.Is(err, sql.ErrNoRows) {
 			vErr = errors.Join(vErr, fmt.Errorf("could not load categories: %w", err))
-		} else {
-			for _, cat := range existingCategories {
-				if cat.Name == c.Name && cat.StoreID == c.StoreID {
-					vErr = errors.Join(vErr, errors.New("category already exists"))
-				}
-			}
 		}
 	}
 
 	return vErr
 }
 
-func LoadCategories(ctx context.Context) ([]Category, error) {
-	rows, err := db.QueryContext(ctx, `
-SELECT id, store_id, name, description, (SELECT COUNT(id) FROM item WHERE item.category_id = category.id)
-FROM category
-ORDER BY name`)
+// LoadCategories returns every category as stored, ordered by name, for
+// exports that list the whole catalog.
+func (r *Repository) LoadCategories(ctx context.Context) ([]dbmodels.Category, error) {
+	return r.q.ListCategories(ctx)
+}
+
+// LoadCategoriesPage returns a page of categories with their item counts.
+func (r *Repository) LoadCategoriesPage(ctx context.Context, query PageQuery) ([]Category, error) {
+	rows, err := r.q.ListCategoriesPage(ctx, dbmodels.ListCategoriesPageParams{
+		Search:    query.pattern(),
+		AfterID:   query.afterID(),
+		Sort:      query.Sort,
+		AfterName: query.afterName(),
+		PageLimit: int32(query.Limit),
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	ret := []Category{}
-	for rows.Next() {
-		// Load the category
-		var cat Category
-		if err := rows.Scan(&cat.ID, &cat.StoreID, &cat.Name, &cat.Description, &cat.ItemCount); err != nil {
-			return nil, err
-		}
 
-		ret = append(ret, cat)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+	ret := make([]Category, 0, len(rows))
+	for _, row := range rows {
+		ret = append(ret, Category{
+			ID:          int(row.ID),
+			StoreID:     int(row.StoreID),
+			Name:        row.Name,
+			Description: row.Description,
+			ItemCount:   int(row.ItemCount),
+			Version:     int(row.Version),
+		})
 	}
 
 	return ret, nil
 }
 
-func GetCategory(ctx context.Context, id int) (Category, error) {
-	row := db.QueryRowContext(ctx, `
-SELECT id, store_id, name, description,
- (SELECT COUNT(id) FROM item WHERE item.category_id = category.id)
-FROM category
-WHERE id = $1
-ORDER BY name`, id)
-	if row.Err() != nil {
-		return Category{}, row.Err()
-	}
-
-	// Load the category
-	var cat Category
-	err := row.Scan(&cat.ID, &cat.StoreID, &cat.Name, &cat.Description, &cat.ItemCount)
+func (r *Repository) GetCategory(ctx context.Context, id int) (Category, error) {
+	row, err := r.q.GetCategoryWithItemCount(ctx, int32(id))
 	if err != nil {
-		return cat, err
+		return Category{}, err
 	}
 
-	return cat, err
+	return Category{
+		ID:          int(row.ID),
+		StoreID:     int(row.StoreID),
+		Name:        row.Name,
+		Description: row.Description,
+		ItemCount:   int(row.ItemCount),
+		Version:     int(row.Version),
+	}, nil
 }
 
-func AddCategory(ctx context.Context, cat Category) error {
-	if err := cat.Validate(ctx); err != nil {
-		return fmt.Errorf("invalid category: %w", err)
-	}
+// AddCategory creates a new category and returns its ID.
+func (r *Repository) AddCategory(ctx context.Context, cat Category) (int, error) {
+	if err := r.ValidateCategory(ctx, cat); err != nil {
+		return 0, fmt.Errorf("invalid category: %w", err)
+	}
+
+	var id int32
+	err := r.InTx(ctx, func(tx *Repository) error {
+		created, err := tx.q.CreateCategory(ctx, dbmodels.CreateCategoryParams{
+			Name:        cat.Name,
+			StoreID:     int32(cat.StoreID),
+			Description: cat.Description,
+		})
+		if err != nil {
+			return err
+		}
+
+		id = created.ID
+		return recordChange(ctx, tx.q, EntityCategory, id, ChangeCreate)
+	})
 
-	_, err := db.ExecContext(ctx, "INSERT INTO category (name, store_id, description) VALUES ($1, $2, $3)", cat.Name, cat.StoreID, cat.Description)
-	return err
+	return int(id), err
 }
 
-func EditCategory(ctx context.Context, cat Category) error {
-	if err := cat.Validate(ctx); err != nil {
+func (r *Repository) EditCategory(ctx context.Context, cat Category) error {
+	if err := r.ValidateCategory(ctx, cat); err != nil {
 		return fmt.Errorf("invalid category: %w", err)
 	}
 
-	_, err := db.ExecContext(ctx, `
-UPDATE category SET
-	name = $2,
-	store_id = $3,
-	description = $4
-WHERE id = $1`, cat.ID, cat.Name, cat.StoreID, cat.Description)
-	return err
+	return r.InTx(ctx, func(tx *Repository) error {
+		_, err := tx.q.UpdateCategory(ctx, dbmodels.UpdateCategoryParams{
+			ID:          int32(cat.ID),
+			Name:        cat.Name,
+			StoreID:     int32(cat.StoreID),
+			Description: cat.Description,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, tx.q, EntityCategory, int32(cat.ID), ChangeUpdate)
+	})
 }
 
-func DeleteCategory(ctx context.Context, id int) error {
+// LockCategoryVersion is LockItemVersion for a category.
+func (r *Repository) LockCategoryVersion(ctx context.Context, id int) (int, error) {
+	version, err := r.q.LockCategoryVersion(ctx, int32(id))
+	return int(version), err
+}
+
+func (r *Repository) DeleteCategory(ctx context.Context, id int) error {
+	return r.InTx(ctx, func(tx *Repository) error {
+		return deleteCategory(ctx, tx.q, id)
+	})
+}
+
+func deleteCategory(ctx context.Context, q *dbmodels.Queries, id int) error {
 	// Prevent deletion if category is still in use
-	items, err := LoadItems(ctx)
+	count, err := q.CountItemsForCategory(ctx, int32(id))
 	if err != nil {
 		return fmt.Errorf("could not enumerate item categories: %w", err)
 	}
-
-	for _, item := range items {
-		if item.CategoryID == id {
-			return errors.New("category is still in use")
-		}
+	if count > 0 {
+		return ErrCategoryInUse
 	}
 
-	tx, err := db.Begin()
-	if err != nil {
+	if err := q.DeleteCategory(ctx, int32(id)); err != nil {
 		return err
 	}
 
-	_, err = db.ExecContext(ctx, "DELETE FROM item WHERE category_id = $1", id)
-	if err != nil {
-		return errors.Join(tx.Rollback(), err)
-	}
-
-	_, err = db.ExecContext(ctx, "DELETE FROM category WHERE id = $1", id)
-	if err != nil {
-		return errors.Join(tx.Rollback(), err)
-	}
-
-	return tx.Commit()
+	return recordChange(ctx, q, EntityCategory, int32(id), ChangeDelete)
 }
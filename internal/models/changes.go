@@ -0,0 +1,203 @@
+package models
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+// Entities recorded in the change log. List entries are identified by the ID
+// of their item, which unlike the entry's own ID survives the item being
+// removed from the list and added again.
+const (
+	EntityItem     = "item"
+	EntityCategory = "category"
+	EntityListItem = "list_item"
+	EntityStore    = "store"
+)
+
+// ChangeOp is the kind of change made to an entity.
+type ChangeOp string
+
+const (
+	ChangeCreate ChangeOp = "create"
+	ChangeUpdate ChangeOp = "update"
+	ChangeDelete ChangeOp = "delete"
+)
+
+// Fields of a list entry that are changed independently of each other. A
+// change without a field touches the whole entity.
+const (
+	FieldDone     = "done"
+	FieldQuantity = "quantity"
+)
+
+// changeLogLockID is the Postgres advisory lock key held by every transaction
+// that writes to the change log. InTx takes it before anything else.
+const changeLogLockID int64 = 0x67726f6363686e67 // "grocchng"
+
+// Change is an entry in the change log. Seq increases with every change and
+// orders them in the order their transactions committed, so a client that has
+// seen every change up to a Seq has seen everything that happened before it.
+type Change struct {
+	Seq       int64
+	Entity    string
+	EntityID  int
+	Op        ChangeOp
+	Field     string
+	ChangedAt time.Time
+}
+
+// RecordChange appends a change to the change log. Mutations in this package
+// record their own changes; RecordChange is for code that writes through the
+// generated queries directly, and must be called with the Queries of a
+// transaction started by InTx.
+//
+// InTx holds the change log lock until commit, which keeps sequence numbers in
+// commit order. Without it a reader could see seq 8 before the transaction
+// holding seq 7 commits, and a client at cursor 8 would never receive 7. A
+// zero ChangedAt records the change as happening now.
+func RecordChange(ctx context.Context, q *dbmodels.Queries, c Change) error {
+	if c.ChangedAt.IsZero() {
+		c.ChangedAt = time.Now()
+	}
+
+	return q.RecordChange(ctx, dbmodels.RecordChangeParams{
+		Entity:    c.Entity,
+		EntityID:  int32(c.EntityID),
+		Op:        string(c.Op),
+		Field:     c.Field,
+		ChangedAt: c.ChangedAt,
+	})
+}
+
+// ChangesSince returns the latest change to each entity changed after the
+// given sequence number, in sequence order, up to limit entities. The Op of
+// each summarizes everything that happened since: ChangeDelete when the
+// entity was deleted last, ChangeCreate when it was created since, and
+// ChangeUpdate otherwise. Entities deleted since are included as tombstones
+// even when they were also created since, as a client cannot tell whether it
+// saw them from an earlier read.
+//
+// The Seq of the last change returned is the cursor for the next read. When
+// an entity's creation and its latest change fall in different pages it is
+// reported as updated, so clients should apply creates and updates alike.
+func (r *Repository) ChangesSince(ctx context.Context, seq int64, limit int) ([]Change, error) {
+	rows, err := r.q.ListChangesSince(ctx, dbmodels.ListChangesSinceParams{
+		Since:      seq,
+		MaxChanges: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]Change, 0, len(rows))
+	for _, row := range rows {
+		op := ChangeOp(row.Op)
+		if op != ChangeDelete {
+			op = ChangeUpdate
+			if row.Created {
+				op = ChangeCreate
+			}
+		}
+
+		ret = append(ret, Change{
+			Seq:       row.Seq,
+			Entity:    row.Entity,
+			EntityID:  int(row.EntityID),
+			Op:        op,
+			Field:     row.Field,
+			ChangedAt: row.ChangedAt,
+		})
+	}
+
+	return ret, nil
+}
+
+// ChangedEntities holds the current state of the entities changed within a
+// range of the change log, keyed by ID. Entities deleted since are missing.
+// Items hold both items and list entries, the latter in their List.
+type ChangedEntities struct {
+	Items      map[int]Item
+	Categories map[int]Category
+	Stores     map[int]dbmodels.Store
+}
+
+// LoadChanged loads the entities changed after the since sequence number up to
+// and including until, using one query per kind of entity.
+func (r *Repository) LoadChanged(ctx context.Context, since, until int64) (ChangedEntities, error) {
+	window := dbmodels.SummarizeChangedItemsParams{Since: since, Until: until}
+	ret := ChangedEntities{
+		Items:      map[int]Item{},
+		Categories: map[int]Category{},
+		Stores:     map[int]dbmodels.Store{},
+	}
+
+	items, err := r.q.SummarizeChangedItems(ctx, window)
+	if err != nil {
+		return ret, err
+	}
+	for _, row := range items {
+		item := itemFromRow(dbmodels.SummarizeItemsRow(row))
+		if item.List != nil {
+			item.List.ItemID = item.ID
+			item.List.Name = item.Name
+			item.List.CategoryID = strconv.Itoa(item.CategoryID)
+		}
+		ret.Items[item.ID] = item
+	}
+
+	barcodes, err := r.q.ListChangedItemBarcodes(ctx, dbmodels.ListChangedItemBarcodesParams(window))
+	if err != nil {
+		return ret, err
+	}
+	for _, row := range barcodes {
+		if item, ok := ret.Items[int(row.ItemID)]; ok {
+			item.Barcodes = append(item.Barcodes, row.Code)
+			ret.Items[item.ID] = item
+		}
+	}
+
+	aliases, err := r.q.ListChangedItemAliases(ctx, dbmodels.ListChangedItemAliasesParams(window))
+	if err != nil {
+		return ret, err
+	}
+	for _, row := range aliases {
+		if item, ok := ret.Items[int(row.ItemID)]; ok {
+			item.Aliases = append(item.Aliases, row.Name)
+			ret.Items[item.ID] = item
+		}
+	}
+
+	categories, err := r.q.ListChangedCategories(ctx, dbmodels.ListChangedCategoriesParams(window))
+	if err != nil {
+		return ret, err
+	}
+	for _, row := range categories {
+		ret.Categories[int(row.ID)] = Category{
+			ID:          int(row.ID),
+			StoreID:     int(row.StoreID),
+			Name:        row.Name,
+			Description: row.Description,
+			ItemCount:   int(row.ItemCount),
+			Version:     int(row.Version),
+		}
+	}
+
+	stores, err := r.q.ListChangedStores(ctx, dbmodels.ListChangedStoresParams(window))
+	if err != nil {
+		return ret, err
+	}
+	for _, store := range stores {
+		ret.Stores[int(store.ID)] = store
+	}
+
+	return ret, nil
+}
+
+// recordChange records a change to an entity happening now.
+func recordChange(ctx context.Context, q *dbmodels.Queries, entity string, id int32, op ChangeOp) error {
+	return RecordChange(ctx, q, Change{Entity: entity, EntityID: int(id), Op: op})
+}
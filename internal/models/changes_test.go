@@ -0,0 +1,85 @@
+package models
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRepository_ChangesSince(t *testing.T) {
+	at := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		op      string
+		created bool
+		want    ChangeOp
+	}{
+		{name: "created since the cursor", op: "update", created: true, want: ChangeCreate},
+		{name: "created before the cursor", op: "update", want: ChangeUpdate},
+		{name: "deleted", op: "delete", want: ChangeDelete},
+		{name: "created and deleted since the cursor", op: "delete", created: true, want: ChangeDelete},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &scriptedDB{rows: map[string][]driver.Value{
+				"ListChangesSince": {int64(12), EntityItem, int64(7), tt.op, "", at, tt.created},
+			}}
+
+			got, err := db.repository().ChangesSince(context.Background(), 10, 100)
+			if err != nil {
+				t.Fatalf("ChangesSince() error = %v", err)
+			}
+			if len(got) != 1 || got[0].Op != tt.want || got[0].Seq != 12 || got[0].EntityID != 7 {
+				t.Errorf("ChangesSince() = %+v, want a single %s of item 7 at 12", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepository_LoadChanged(t *testing.T) {
+	db := &scriptedDB{rows: map[string][]driver.Value{
+		"SummarizeChangedItems":   {int64(7), "Milk", int64(1), "Dairy", int64(3), "2", false, int64(4), int64(2)},
+		"ListChangedItemBarcodes": {int64(7), "0123456789012"},
+		"ListChangedItemAliases":  {int64(7), "Moo juice"},
+		"ListChangedCategories":   {int64(1), "Dairy", "", int64(1), int64(5), int64(1)},
+		"ListChangedStores":       {int64(1), "Corner shop"},
+	}}
+
+	got, err := db.repository().LoadChanged(context.Background(), 10, 20)
+	if err != nil {
+		t.Fatalf("LoadChanged() error = %v", err)
+	}
+
+	wantLog := []string{
+		"SummarizeChangedItems", "ListChangedItemBarcodes", "ListChangedItemAliases",
+		"ListChangedCategories", "ListChangedStores",
+	}
+	if !reflect.DeepEqual(db.log, wantLog) {
+		t.Errorf("LoadChanged() ran %q, want %q", db.log, wantLog)
+	}
+
+	wantItem := Item{
+		ID:         7,
+		CategoryID: 1,
+		Name:       "Milk",
+		List:       &ListItem{ID: 3, ItemID: 7, CategoryID: "1", Quantity: "2", Version: 2, Name: "Milk"},
+		Barcodes:   []string{"0123456789012"},
+		Aliases:    []string{"Moo juice"},
+		Version:    4,
+
+		categoryName: "Dairy",
+	}
+	if !reflect.DeepEqual(got.Items[7], wantItem) {
+		t.Errorf("LoadChanged() item = %+v, want %+v", got.Items[7], wantItem)
+	}
+	if got.Categories[1].ItemCount != 1 || got.Categories[1].Version != 5 {
+		t.Errorf("LoadChanged() category = %+v, want version 5 with 1 item", got.Categories[1])
+	}
+	if got.Stores[1].Name != "Corner shop" {
+		t.Errorf("LoadChanged() store = %+v, want Corner shop", got.Stores[1])
+	}
+}
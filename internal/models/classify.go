@@ -0,0 +1,169 @@
+package models
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CategorySuggestion is the category the classifier expects an item to
+// belong to. Confidence is the share of the vote of similar catalog items won
+// by the suggested category, and Assigned reports whether the item was filed
+// there.
+type CategorySuggestion struct {
+	CategoryID   int
+	CategoryName string
+	Confidence   float64
+	Assigned     bool
+}
+
+const (
+	// classifierNeighbors is how many of the most similar catalog items vote
+	// on the category of a new item.
+	classifierNeighbors = 5
+
+	// minClassifierSimilarity is lower than the search threshold because
+	// sharing a single word, such as "tomatoes", is already a strong signal.
+	minClassifierSimilarity = 0.2
+
+	// minClassifierEvidence is the least total similarity a vote is counted
+	// out of. When the neighbors are fewer or weaker than that, the shortfall
+	// counts as abstaining, so that a single weak match, such as "Bagels" with
+	// "Garbage bags", cannot win the whole vote by itself.
+	minClassifierEvidence = 0.35
+
+	// autoAssignConfidence is the vote share at which a new item is filed
+	// under the suggested category instead of being left uncategorized.
+	autoAssignConfidence = 0.75
+)
+
+// SuggestCategory suggests a category for an item name, learning from the
+// category assignments of the existing catalog. It returns nil when no
+// sufficiently similar item has been categorized yet.
+func (r *Repository) SuggestCategory(ctx context.Context, name string) (*CategorySuggestion, error) {
+	items, err := r.LoadItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return classify(items, name), nil
+}
+
+// AddItemByName creates a new item, filing it under the suggested category
+// when the classifier is confident and leaving it uncategorized otherwise.
+func (r *Repository) AddItemByName(ctx context.Context, name string) (Item, *CategorySuggestion, error) {
+	suggestion, err := r.SuggestCategory(ctx, name)
+	if err != nil {
+		return Item{}, nil, err
+	}
+
+	return r.addSuggestedItem(ctx, name, suggestion)
+}
+
+// addSuggestedItem creates a new item from a category suggestion already made
+// for it, so that callers holding the catalog need not load it again.
+func (r *Repository) addSuggestedItem(ctx context.Context, name string, suggestion *CategorySuggestion) (Item, *CategorySuggestion, error) {
+	newItem := Item{
+		Name:       name,
+		CategoryID: assignCategory(suggestion),
+	}
+
+	id, err := r.AddItem(ctx, newItem)
+	if err != nil {
+		return Item{}, nil, fmt.Errorf("unable to add item: %w", err)
+	}
+
+	item, err := r.GetItem(ctx, id)
+	return item, suggestion, err
+}
+
+// assignCategory returns the category a new item should be filed under,
+// marking the suggestion as assigned when the classifier is confident.
+func assignCategory(suggestion *CategorySuggestion) int {
+	if suggestion == nil || suggestion.Confidence < autoAssignConfidence {
+		return UncategorizedCategoryID
+	}
+
+	suggestion.Assigned = true
+	return suggestion.CategoryID
+}
+
+// classify performs a nearest-neighbor vote: the categorized items most
+// similar to the name, by shared words and trigrams, vote for their category
+// weighted by their similarity. A lone neighbor therefore only carries the
+// vote when it is a close match.
+func classify(items []Item, name string) *CategorySuggestion {
+	features := classifierFeatures(name)
+
+	type neighbor struct {
+		item       Item
+		similarity float64
+	}
+	neighbors := []neighbor{}
+	for _, item := range items {
+		if item.CategoryID == UncategorizedCategoryID {
+			continue
+		}
+
+		sim := similarity(features, classifierFeatures(item.Name))
+		if sim >= minClassifierSimilarity {
+			neighbors = append(neighbors, neighbor{item: item, similarity: sim})
+		}
+	}
+	if len(neighbors) == 0 {
+		return nil
+	}
+
+	slices.SortStableFunc(neighbors, func(a, b neighbor) int {
+		return cmp.Compare(b.similarity, a.similarity)
+	})
+	if len(neighbors) > classifierNeighbors {
+		neighbors = neighbors[:classifierNeighbors]
+	}
+
+	votes := map[int]float64{}
+	total := 0.0
+	for _, n := range neighbors {
+		votes[n.item.CategoryID] += n.similarity
+		total += n.similarity
+	}
+
+	// Neighbors are sorted, so the first one seen breaks ties between equal votes
+	var best *CategorySuggestion
+	for _, n := range neighbors {
+		if best == nil || votes[n.item.CategoryID] > votes[best.CategoryID] {
+			best = &CategorySuggestion{
+				CategoryID:   n.item.CategoryID,
+				CategoryName: n.item.CategoryName(),
+			}
+		}
+	}
+	best.Confidence = votes[best.CategoryID] / max(total, minClassifierEvidence)
+
+	return best
+}
+
+// classifierFeatures combines the words of a name with its trigrams, so that
+// sharing a whole word counts for more than sharing a few letters.
+func classifierFeatures(name string) map[string]struct{} {
+	ret := trigrams(name)
+	for word := range strings.FieldsSeq(strings.ToLower(name)) {
+		ret["w:"+singular(word)] = struct{}{}
+	}
+	return ret
+}
+
+// singular strips common English plural endings from a lowercase word.
+func singular(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 4:
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "oes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
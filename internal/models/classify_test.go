@@ -0,0 +1,73 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	items := []Item{
+		{Name: "Cherry tomatoes", CategoryID: 1, categoryName: "Produce"},
+		{Name: "Sweet potatoes", CategoryID: 1, categoryName: "Produce"},
+		{Name: "Russet potatoes", CategoryID: 1, categoryName: "Produce"},
+		{Name: "Whole milk", CategoryID: 2, categoryName: "Dairy"},
+		{Name: "Oat milk", CategoryID: 2, categoryName: "Dairy"},
+		{Name: "Cheddar cheese", CategoryID: 2, categoryName: "Dairy"},
+		{Name: "Garbage bags", CategoryID: 4, categoryName: "Household"},
+		{Name: "Mystery box", CategoryID: UncategorizedCategoryID},
+	}
+
+	tests := []struct {
+		name           string
+		item           string
+		wantCategoryID int
+		wantConfident  bool
+	}{
+		{name: "shared word", item: "Skim milk", wantCategoryID: 2, wantConfident: true},
+		{name: "plural", item: "Roma tomato", wantCategoryID: 1, wantConfident: true},
+		{name: "several neighbors", item: "Yukon gold potatoes", wantCategoryID: 1, wantConfident: true},
+		{name: "lone weak neighbor", item: "Bagels", wantCategoryID: 4},
+		{name: "lone neighbor sharing letters", item: "Garbanzo beans", wantCategoryID: 4},
+		{name: "lone close neighbor", item: "Cheese", wantCategoryID: 2, wantConfident: true},
+		{name: "unrelated", item: "Dish soap"},
+		{name: "ignores uncategorized items", item: "Mystery box"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classify(items, tt.item)
+			if tt.wantCategoryID == 0 {
+				if got != nil {
+					t.Fatalf("classify(%q) = %+v, want nil", tt.item, got)
+				}
+				return
+			}
+
+			if got == nil {
+				t.Fatalf("classify(%q) = nil, want category %d", tt.item, tt.wantCategoryID)
+			}
+			if got.CategoryID != tt.wantCategoryID {
+				t.Errorf("classify(%q) category = %d, want %d", tt.item, got.CategoryID, tt.wantCategoryID)
+			}
+			if confident := got.Confidence >= autoAssignConfidence; confident != tt.wantConfident {
+				t.Errorf("classify(%q) confidence = %v, want confident %v", tt.item, got.Confidence, tt.wantConfident)
+			}
+		})
+	}
+}
+
+func TestSingular(t *testing.T) {
+	tests := map[string]string{
+		"tomatoes":     "tomato",
+		"strawberries": "strawberry",
+		"bags":         "bag",
+		"cheese":       "cheese",
+		"glass":        "glass",
+		"pies":         "pie",
+	}
+
+	for word, want := range tests {
+		if got := singular(word); got != want {
+			t.Errorf("singular(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+// ErrGroupInUse is returned when deleting a group that still has members.
+var ErrGroupInUse = errors.New("group is still in use")
+
+// LoadGroups returns every group, ordered by name.
+func (r *Repository) LoadGroups(ctx context.Context) ([]dbmodels.Group, error) {
+	return r.q.ListGroups(ctx)
+}
+
+func (r *Repository) GetGroup(ctx context.Context, id int32) (dbmodels.Group, error) {
+	return r.q.GetGroup(ctx, id)
+}
+
+// AddGroup creates a new group.
+func (r *Repository) AddGroup(ctx context.Context, name string) (dbmodels.Group, error) {
+	if err := r.q.ValidateGroup(ctx, dbmodels.Group{Name: name}); err != nil {
+		return dbmodels.Group{}, fmt.Errorf("invalid group: %w", err)
+	}
+
+	return r.q.CreateGroup(ctx, name)
+}
+
+// RenameGroup changes the name of a group, returning sql.ErrNoRows if it does
+// not exist.
+func (r *Repository) RenameGroup(ctx context.Context, id int32, name string) (dbmodels.Group, error) {
+	if err := r.q.ValidateGroup(ctx, dbmodels.Group{ID: id, Name: name}); err != nil {
+		return dbmodels.Group{}, fmt.Errorf("invalid group: %w", err)
+	}
+
+	return r.q.UpdateGroup(ctx, dbmodels.UpdateGroupParams{ID: id, Name: name})
+}
+
+func (r *Repository) DeleteGroup(ctx context.Context, id int32) error {
+	err := r.q.DeleteGroup(ctx, id)
+	if dbmodels.IsReferenced(err) {
+		return ErrGroupInUse
+	}
+	return err
+}
@@ -0,0 +1,261 @@
+package models
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Formats understood by ParseImport. ImportFormatAuto detects the format from
+// the content.
+const (
+	ImportFormatAuto     = ""
+	ImportFormatText     = "text"
+	ImportFormatMarkdown = "markdown"
+	ImportFormatCSV      = "csv"
+)
+
+// ErrUnknownImportFormat is returned by ParseImport for an unsupported format.
+var ErrUnknownImportFormat = errors.New("format must be one of text, markdown or csv")
+
+// ImportLine is an item parsed from an imported list. Line is the 1-based line
+// (or CSV record) it was read from.
+type ImportLine struct {
+	Line     int
+	Name     string
+	Quantity string
+}
+
+var (
+	// listMarker matches the bullet, numbering and checkbox that may precede
+	// an item in a Markdown or plain text list
+	listMarker = regexp.MustCompile(`^(?:[-*+•]|\d+[.)])\s+(?:\[([ xX])\]\s*)?`)
+
+	// amountToken matches a leading amount such as "2", "1.5", "1/2", "½",
+	// "2x" or "500g"
+	amountToken = regexp.MustCompile(`^(\d+(?:[.,]\d+)?|\d+/\d+|[½⅓⅔¼¾⅛])([a-zA-Z]*)$`)
+
+	// trailingAmount matches a count or measurement after the name, as in
+	// "apples x2" or "apples (2 lbs)"
+	trailingAmount = regexp.MustCompile(`\s+(?:[xX](\d+)|\((\d[^()]*)\))$`)
+)
+
+// importUnits are the units recognized after a leading amount, so that
+// "2 lbs apples" is two pounds of apples rather than two "lbs apples".
+var importUnits = []string{
+	"bag", "bags", "bottle", "bottles", "box", "boxes", "bunch", "bunches",
+	"can", "cans", "carton", "cartons", "ct", "cup", "cups", "dozen", "g",
+	"gal", "gallon", "gallons", "head", "heads", "jar", "jars", "kg", "l",
+	"lb", "lbs", "loaf", "loaves", "ml", "oz", "pack", "packs", "package",
+	"packages", "pint", "pints", "pkg", "pound", "pounds", "qt", "quart",
+	"quarts", "tbsp", "tsp",
+}
+
+// ParseImport reads a shopping list written as plain text with one item per
+// line, as a Markdown checklist, or as CSV. Leading and trailing amounts are
+// split from the item names, and checked-off Markdown items are skipped.
+func ParseImport(r io.Reader, format string) ([]ImportLine, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimPrefix(string(content), "\ufeff")
+
+	if format == ImportFormatAuto {
+		format = detectImportFormat(text)
+	}
+
+	switch format {
+	case ImportFormatText:
+		return parseImportLines(text, false)
+	case ImportFormatMarkdown:
+		return parseImportLines(text, true)
+	case ImportFormatCSV:
+		return parseImportCSV(text)
+	default:
+		return nil, ErrUnknownImportFormat
+	}
+}
+
+// detectImportFormat treats content as Markdown when it contains a list item,
+// as CSV when its first line is a header naming the item column, and as plain
+// text otherwise.
+func detectImportFormat(text string) string {
+	firstLine := ""
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if firstLine == "" {
+			firstLine = line
+		}
+		if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") || strings.HasPrefix(line, "+ ") {
+			return ImportFormatMarkdown
+		}
+	}
+
+	if strings.Contains(firstLine, ",") {
+		header, err := csv.NewReader(strings.NewReader(firstLine)).Read()
+		if err == nil && csvColumn(header, "name", "item", "product") >= 0 {
+			return ImportFormatCSV
+		}
+	}
+
+	return ImportFormatText
+}
+
+// parseImportLines parses one item per line. In Markdown only list items are
+// read, so that headings and notes around the checklist are ignored.
+func parseImportLines(text string, markdown bool) ([]ImportLine, error) {
+	ret := []ImportLine{}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		marker := listMarker.FindStringSubmatch(line)
+		if marker == nil && markdown {
+			continue
+		}
+		if marker != nil {
+			if strings.EqualFold(marker[1], "x") {
+				continue
+			}
+			line = strings.TrimSpace(line[len(marker[0]):])
+		}
+
+		quantity, name := splitQuantity(line)
+		if name == "" {
+			continue
+		}
+		ret = append(ret, ImportLine{Line: lineNo, Name: name, Quantity: quantity})
+	}
+
+	return ret, scanner.Err()
+}
+
+// parseImportCSV reads the name and quantity columns named by a header row.
+// Without a header the first column is the item, optionally preceded by its
+// amount, and the second column is the quantity.
+func parseImportCSV(text string) ([]ImportLine, error) {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+	if len(records) == 0 {
+		return []ImportLine{}, nil
+	}
+
+	nameCol, quantityCol, unitCol := 0, 1, -1
+	first := 0
+	if col := csvColumn(records[0], "name", "item", "product"); col >= 0 {
+		nameCol = col
+		quantityCol = csvColumn(records[0], "quantity", "qty", "amount")
+		unitCol = csvColumn(records[0], "unit", "units")
+		first = 1
+	}
+
+	ret := []ImportLine{}
+	for i, record := range records[first:] {
+		if nameCol >= len(record) {
+			continue
+		}
+
+		quantity, name := splitQuantity(strings.TrimSpace(record[nameCol]))
+		if quantityCol >= 0 && quantityCol < len(record) && strings.TrimSpace(record[quantityCol]) != "" {
+			quantity = strings.TrimSpace(record[quantityCol])
+		}
+		if unitCol >= 0 && unitCol < len(record) && quantity != "" {
+			quantity = strings.TrimSpace(quantity + " " + strings.TrimSpace(record[unitCol]))
+		}
+		if name == "" {
+			continue
+		}
+
+		ret = append(ret, ImportLine{Line: first + i + 1, Name: name, Quantity: quantity})
+	}
+
+	return ret, nil
+}
+
+// csvColumn returns the index of the first header matching one of names, or
+// -1 when there is none.
+func csvColumn(header []string, names ...string) int {
+	return slices.IndexFunc(header, func(h string) bool {
+		return slices.Contains(names, strings.ToLower(strings.TrimSpace(h)))
+	})
+}
+
+// splitQuantity separates the amount from an item written as "2 lbs apples",
+// "2 cans of beans", "3x lemons", "apples x2" or "apples (2 lbs)". A line
+// without an amount is returned as the name with an empty quantity.
+func splitQuantity(line string) (quantity, name string) {
+	line = strings.Join(strings.Fields(line), " ")
+
+	if m := trailingAmount.FindStringSubmatch(line); m != nil && len(m[0]) < len(line) {
+		return strings.TrimSpace(m[1] + m[2]), line[:len(line)-len(m[0])]
+	}
+
+	words := strings.Fields(line)
+	if len(words) == 0 {
+		return "", line
+	}
+
+	m := amountToken.FindStringSubmatch(words[0])
+	if m == nil || !isAmountSuffix(m[2]) {
+		return "", line
+	}
+
+	// "3x lemons" is a count of three
+	amount := []string{words[0]}
+	if strings.EqualFold(m[2], "x") {
+		amount[0] = m[1]
+	}
+
+	rest := words[1:]
+	if len(rest) > 0 && strings.Contains(rest[0], "/") && amountToken.MatchString(rest[0]) {
+		// A mixed number such as "1 1/2"
+		amount, rest = append(amount, rest[0]), rest[1:]
+	}
+	if m[2] == "" && len(rest) > 0 && isImportUnit(rest[0]) {
+		amount, rest = append(amount, rest[0]), rest[1:]
+	}
+	if len(rest) == 0 {
+		return "", line
+	}
+
+	quantity = strings.Join(amount, " ")
+	if len(rest) > 1 && strings.EqualFold(rest[0], "of") {
+		rest = rest[1:]
+	}
+
+	return quantity, strings.Join(rest, " ")
+}
+
+// isAmountSuffix reports whether letters attached to a leading number, as in
+// "2x" or "500g", make it an amount rather than part of a name like "7up".
+func isAmountSuffix(suffix string) bool {
+	return suffix == "" || strings.EqualFold(suffix, "x") || isImportUnit(suffix)
+}
+
+func isImportUnit(word string) bool {
+	word = strings.TrimRightFunc(strings.ToLower(word), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	return slices.Contains(importUnits, word)
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitQuantity(t *testing.T) {
+	tests := []struct {
+		line         string
+		wantQuantity string
+		wantName     string
+	}{
+		{line: "apples", wantName: "apples"},
+		{line: "2 apples", wantQuantity: "2", wantName: "apples"},
+		{line: "2 lbs apples", wantQuantity: "2 lbs", wantName: "apples"},
+		{line: "2 cans of black beans", wantQuantity: "2 cans", wantName: "black beans"},
+		{line: "1 1/2 cups flour", wantQuantity: "1 1/2 cups", wantName: "flour"},
+		{line: "½ lb butter", wantQuantity: "½ lb", wantName: "butter"},
+		{line: "500g mince", wantQuantity: "500g", wantName: "mince"},
+		{line: "3x lemons", wantQuantity: "3", wantName: "lemons"},
+		{line: "lemons x3", wantQuantity: "3", wantName: "lemons"},
+		{line: "apples (2 lbs)", wantQuantity: "2 lbs", wantName: "apples"},
+		{line: "Apples (Gala)", wantName: "Apples (Gala)"},
+		{line: "7up", wantName: "7up"},
+		{line: "2 lbs", wantName: "2 lbs"},
+		{line: "  2   bags  ice ", wantQuantity: "2 bags", wantName: "ice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			quantity, name := splitQuantity(tt.line)
+			if quantity != tt.wantQuantity || name != tt.wantName {
+				t.Errorf("splitQuantity(%q) = %q, %q, want %q, %q", tt.line, quantity, name, tt.wantQuantity, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestParseImport(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		format  string
+		want    []ImportLine
+		wantErr error
+	}{
+		{
+			name:    "text",
+			content: "milk\n\n2 lbs apples\n",
+			want: []ImportLine{
+				{Line: 1, Name: "milk"},
+				{Line: 3, Name: "apples", Quantity: "2 lbs"},
+			},
+		},
+		{
+			name:    "markdown checklist",
+			content: "# Groceries\n\nFor the weekend:\n- [ ] 2 lbs apples\n- [x] bread\n* eggs\n",
+			want: []ImportLine{
+				{Line: 4, Name: "apples", Quantity: "2 lbs"},
+				{Line: 6, Name: "eggs"},
+			},
+		},
+		{
+			name:    "numbered text",
+			content: "1. milk\n2) 6 eggs",
+			format:  ImportFormatText,
+			want: []ImportLine{
+				{Line: 1, Name: "milk"},
+				{Line: 2, Name: "eggs", Quantity: "6"},
+			},
+		},
+		{
+			name:    "csv with header",
+			content: "Quantity,Item,Unit\n2,apples,lbs\n,milk,\n",
+			want: []ImportLine{
+				{Line: 2, Name: "apples", Quantity: "2 lbs"},
+				{Line: 3, Name: "milk"},
+			},
+		},
+		{
+			name:    "csv without header",
+			content: "apples,2 lbs\n3 lemons\n",
+			format:  ImportFormatCSV,
+			want: []ImportLine{
+				{Line: 1, Name: "apples", Quantity: "2 lbs"},
+				{Line: 2, Name: "lemons", Quantity: "3"},
+			},
+		},
+		{
+			name:    "unknown format",
+			content: "milk",
+			format:  "xml",
+			wantErr: ErrUnknownImportFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseImport(strings.NewReader(tt.content), tt.format)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseImport() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseImport() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,9 +2,12 @@ package models
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
 )
 
 type Item struct {
@@ -12,6 +15,9 @@ type Item struct {
 	CategoryID   int       `json:"category_id"`
 	Name         string    `json:"name"`
 	List         *ListItem `json:"list"`
+	Barcodes     []string  `json:"barcodes,omitempty"`
+	Aliases      []string  `json:"aliases,omitempty"`
+	Version      int       `json:"version"`
 	categoryName string
 }
 
@@ -22,6 +28,9 @@ func (i Item) MarshalJSON() ([]byte, error) {
 		CategoryName string    `json:"category_name"`
 		Name         string    `json:"name"`
 		List         *ListItem `json:"list"`
+		Barcodes     []string  `json:"barcodes,omitempty"`
+		Aliases      []string  `json:"aliases,omitempty"`
+		Version      int       `json:"version"`
 	}
 
 	return json.Marshal(itemJSON{
@@ -30,6 +39,9 @@ func (i Item) MarshalJSON() ([]byte, error) {
 		CategoryName: i.categoryName,
 		Name:         i.Name,
 		List:         i.List,
+		Barcodes:     i.Barcodes,
+		Aliases:      i.Aliases,
+		Version:      i.Version,
 	})
 }
 
@@ -37,15 +49,14 @@ func (i *Item) CategoryName() string {
 	return i.categoryName
 }
 
-func (i *Item) Category(ctx context.Context) (Category, error) {
-	return GetCategory(ctx, i.CategoryID)
-}
-
-func (i *Item) Validate(ctx context.Context) error {
+// ValidateItem reports every problem with an item before it is stored.
+func (r *Repository) ValidateItem(ctx context.Context, i Item) error {
 	var vErr error
 
-	if _, err := GetCategory(ctx, i.CategoryID); err != nil {
-		vErr = errors.Join(vErr, fmt.Errorf("category not found: %w", err))
+	if _, err := r.q.GetCategory(ctx, int32(i.CategoryID)); errors.Is(err, sql.ErrNoRows) {
+		vErr = errors.Join(vErr, &dbmodels.FieldError{Field: "category_id", Err: dbmodels.ErrUnknownRef, Message: "category not found"})
+	} else if err != nil {
+		vErr = errors.Join(vErr, fmt.Errorf("could not load category: %w", err))
 	}
 
 	if i.List != nil {
@@ -55,148 +66,214 @@ func (i *Item) Validate(ctx context.Context) error {
 	return vErr
 }
 
-func LoadItems(ctx context.Context) ([]Item, error) {
-	rows, err := db.QueryContext(ctx, `
-SELECT item.id, item.name, item.category_id, category.name AS category_name,
-	item_list.id AS list_id, item_list.quantity AS list_quantity, item_list.done AS list_done
-FROM item
-LEFT JOIN category ON (item.category_id = category.id)
-LEFT JOIN item_list ON (item_list.item_id = item.id)
-ORDER BY category.name, item.name`)
+func (r *Repository) LoadItems(ctx context.Context) ([]Item, error) {
+	rows, err := r.q.SummarizeItems(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	ret := []Item{}
-	for rows.Next() {
-		// Load the item
-		item := Item{}
-		var listID *int
-		var listQuantity *string
-		var listDone *bool
-		if err := rows.Scan(&item.ID, &item.Name, &item.CategoryID, &item.categoryName, &listID, &listQuantity, &listDone); err != nil {
-			return nil, err
-		}
 
-		if listID != nil {
-			item.List = &ListItem{
-				ID:       *listID,
-				Quantity: *listQuantity,
-				Done:     *listDone,
-			}
-		}
+	ret := make([]Item, 0, len(rows))
+	for _, row := range rows {
+		ret = append(ret, itemFromRow(row))
+	}
+
+	return ret, nil
+}
+
+// ItemQuery selects a page of items, optionally only those in a category or
+// those that are or are not on the list.
+type ItemQuery struct {
+	PageQuery
+	CategoryID *int
+	InList     *bool
+}
 
-		ret = append(ret, item)
+// LoadItemsPage returns a page of the items LoadItems would return.
+func (r *Repository) LoadItemsPage(ctx context.Context, query ItemQuery) ([]Item, error) {
+	params := dbmodels.SummarizeItemsPageParams{
+		Search:    query.pattern(),
+		AfterID:   query.afterID(),
+		Sort:      query.Sort,
+		AfterName: query.afterName(),
+		PageLimit: int32(query.Limit),
+	}
+	if query.CategoryID != nil {
+		params.CategoryID = sql.NullInt32{Int32: int32(*query.CategoryID), Valid: true}
 	}
-	if err := rows.Err(); err != nil {
+	if query.InList != nil {
+		params.InList = sql.NullBool{Bool: *query.InList, Valid: true}
+	}
+
+	rows, err := r.q.SummarizeItemsPage(ctx, params)
+	if err != nil {
 		return nil, err
 	}
 
+	ret := make([]Item, 0, len(rows))
+	for _, row := range rows {
+		ret = append(ret, itemFromRow(dbmodels.SummarizeItemsRow(row)))
+	}
+
 	return ret, nil
 }
 
-func GetItem(ctx context.Context, id int) (Item, error) {
-	ret := Item{}
-	var listID *int
-	err := db.QueryRowContext(ctx, `
-SELECT item.id, item.category_id, item.name, category.name AS category_name, item_list.id AS list_id
-FROM item
-LEFT JOIN category ON (item.category_id = category.id)
-LEFT JOIN item_list ON (item_list.item_id = item.id)
-WHERE item.id = $1`, id).
-		Scan(&ret.ID, &ret.CategoryID, &ret.Name, &ret.categoryName, &listID)
-	if err != nil {
-		return ret, err
+func itemFromRow(row dbmodels.SummarizeItemsRow) Item {
+	item := Item{
+		ID:           int(row.ID),
+		CategoryID:   int(row.CategoryID),
+		Name:         row.Name,
+		Version:      int(row.Version),
+		categoryName: row.CategoryName.String,
 	}
 
-	if listID != nil {
-		ret.List, err = GetListItem(ctx, *listID)
+	if row.ListID.Valid {
+		item.List = &ListItem{
+			ID:       int(row.ListID.Int32),
+			Quantity: row.ListQuantity.String,
+			Done:     row.ListDone.Bool,
+			Version:  int(row.ListVersion.Int32),
+		}
 	}
 
-	return ret, err
+	return item
 }
 
-func GetItemByName(ctx context.Context, name string) (Item, error) {
-	ret := Item{}
-	var inList *bool
-	err := db.QueryRowContext(ctx, `
-SELECT item.id, item.category_id, item.name, category.name AS category_name,
-	(SELECT TRUE FROM item_list WHERE item_list.item_id = item.id) AS in_list
-FROM item
-LEFT JOIN category ON (item.category_id = category.id)
-WHERE item.name = $1`, name).
-		Scan(&ret.ID, &ret.CategoryID, &ret.Name, &ret.categoryName, &inList)
+func (r *Repository) GetItem(ctx context.Context, id int) (Item, error) {
+	row, err := r.q.SummarizeItem(ctx, int32(id))
+	if err != nil {
+		return Item{}, err
+	}
 
-	if inList != nil {
-		ret.List = &ListItem{}
+	ret := itemFromSummary(row)
+	if row.ListID.Valid {
+		ret.List, err = r.GetListItem(ctx, int(row.ListID.Int32))
+		if err != nil {
+			return ret, err
+		}
+	}
+
+	barcodes, err := r.q.ListItemBarcodes(ctx, row.ID)
+	if err != nil {
+		return ret, err
+	}
+	for _, barcode := range barcodes {
+		ret.Barcodes = append(ret.Barcodes, barcode.Code)
 	}
 
+	ret.Aliases, err = r.q.ListAliasesForItem(ctx, row.ID)
 	return ret, err
 }
 
-func ItemChangeCategory(ctx context.Context, id int, categoryID int) error {
-	_, err := db.ExecContext(ctx, `UPDATE item SET category_id = $2 WHERE id = $1`, id, categoryID)
-	return err
+// GetItemByName returns the item with the given name or alias, both matched
+// case-insensitively. An item named exactly as given is preferred, then any
+// other item so named, and only then an alias.
+func (r *Repository) GetItemByName(ctx context.Context, name string) (Item, error) {
+	return getItemByName(ctx, r.q, name)
 }
 
-func AddItem(ctx context.Context, i Item) error {
-	if err := i.Validate(ctx); err != nil {
-		return fmt.Errorf("invalid item: %w", err)
+func getItemByName(ctx context.Context, q *dbmodels.Queries, name string) (Item, error) {
+	row, err := q.FindItemByNameOrAlias(ctx, name)
+	if err != nil {
+		return Item{}, err
 	}
 
-	tx, err := db.Begin()
-	if err != nil {
-		return err
+	ret := Item{
+		ID:           int(row.ID),
+		CategoryID:   int(row.CategoryID),
+		Name:         row.Name,
+		Version:      int(row.Version),
+		categoryName: row.CategoryName.String,
+	}
+	if row.InList {
+		ret.List = &ListItem{}
 	}
 
-	if i.ID == 0 {
-		_, err = insertWithID(ctx, tx,
-			`INSERT INTO item (category_id, name) VALUES ($1, $2) RETURNING id`,
-			i.CategoryID,
-			i.Name,
-		)
+	return ret, nil
+}
+
+func (r *Repository) ItemChangeCategory(ctx context.Context, id int, categoryID int) error {
+	return r.InTx(ctx, func(tx *Repository) error {
+		err := tx.q.UpdateItemCategory(ctx, dbmodels.UpdateItemCategoryParams{
+			ID:         int32(id),
+			CategoryID: int32(categoryID),
+		})
 		if err != nil {
-			return errors.Join(tx.Rollback(), err)
+			return err
 		}
-	}
 
-	return tx.Commit()
+		return recordChange(ctx, tx.q, EntityItem, int32(id), ChangeUpdate)
+	})
 }
 
-func EditItem(ctx context.Context, i Item) error {
-	if err := i.Validate(ctx); err != nil {
-		return fmt.Errorf("invalid item: %w", err)
+// AddItem creates a new item and returns its ID.
+func (r *Repository) AddItem(ctx context.Context, i Item) (int, error) {
+	if err := r.ValidateItem(ctx, i); err != nil {
+		return 0, fmt.Errorf("invalid item: %w", err)
 	}
 
-	tx, err := db.Begin()
-	if err != nil {
-		return err
-	}
+	var id int32
+	err := r.InTx(ctx, func(tx *Repository) error {
+		created, err := tx.q.CreateItem(ctx, dbmodels.CreateItemParams{
+			CategoryID: int32(i.CategoryID),
+			Name:       i.Name,
+		})
+		if err != nil {
+			return err
+		}
 
-	_, err = db.ExecContext(ctx, `
-UPDATE item SET
-	category_id = $2,
-	name = $3
-WHERE id = $1`, i.ID, i.CategoryID, i.Name)
-	if err != nil {
-		return errors.Join(tx.Rollback(), err)
+		id = created.ID
+		return recordChange(ctx, tx.q, EntityItem, id, ChangeCreate)
+	})
+
+	return int(id), err
+}
+
+// EditItem updates an item's name and category. Its list entry is versioned
+// separately and changed with ListChangeQuantity and MarkItemDone.
+func (r *Repository) EditItem(ctx context.Context, i Item) error {
+	if err := r.ValidateItem(ctx, i); err != nil {
+		return fmt.Errorf("invalid item: %w", err)
 	}
 
-	if i.List != nil {
-		_, err := db.ExecContext(ctx, `
-UPDATE item_list SET
-	quantity = $2
-WHERE id = $1`, i.List.ID, i.List.Quantity)
+	return r.InTx(ctx, func(tx *Repository) error {
+		_, err := tx.q.UpdateItem(ctx, dbmodels.UpdateItemParams{
+			ID:         int32(i.ID),
+			CategoryID: int32(i.CategoryID),
+			Name:       i.Name,
+		})
 		if err != nil {
-			return errors.Join(tx.Rollback(), err)
+			return err
 		}
-	}
 
-	return tx.Commit()
+		return recordChange(ctx, tx.q, EntityItem, int32(i.ID), ChangeUpdate)
+	})
 }
 
-func DeleteItem(ctx context.Context, id int) error {
-	_, err := db.ExecContext(ctx, `DELETE FROM item WHERE id = $1`, id)
-	return err
+// LockItemVersion locks an item for the rest of the unit of work and returns
+// its current version, so that a write can be checked against the version
+// the client last saw.
+func (r *Repository) LockItemVersion(ctx context.Context, id int) (int, error) {
+	version, err := r.q.LockItemVersion(ctx, int32(id))
+	return int(version), err
+}
+
+func (r *Repository) DeleteItem(ctx context.Context, id int) error {
+	return r.InTx(ctx, func(tx *Repository) error {
+		if err := tx.q.DeleteItem(ctx, int32(id)); err != nil {
+			return err
+		}
+
+		return recordChange(ctx, tx.q, EntityItem, int32(id), ChangeDelete)
+	})
+}
+
+// itemFromSummary converts a catalog row without list details.
+func itemFromSummary(row dbmodels.SummarizeItemRow) Item {
+	return Item{
+		ID:           int(row.ID),
+		CategoryID:   int(row.CategoryID),
+		Name:         row.Name,
+		Version:      int(row.Version),
+		categoryName: row.CategoryName.String,
+	}
 }
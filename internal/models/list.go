@@ -2,7 +2,14 @@ package models
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
 )
 
 type ListItem struct {
@@ -11,6 +18,7 @@ type ListItem struct {
 	CategoryID string `json:"category_id"`
 	Quantity   string `json:"quantity"`
 	Done       bool   `json:"done"`
+	Version    int    `json:"version"`
 
 	Name string `json:"name"`
 }
@@ -19,83 +27,201 @@ func (i *ListItem) Validate(ctx context.Context) error {
 	return nil
 }
 
-func LoadList(ctx context.Context) ([]Item, error) {
-	rows, err := db.QueryContext(ctx, `
-SELECT item.id, item.name, item.category_id, category.name AS category_name,
-	item_list.quantity AS list_quantity, item_list.id AS list_id, item_list.done AS list_done
-FROM item_list
-INNER JOIN item ON (item.id = item_list.item_id)
-INNER JOIN category ON (item.category_id = category.id)
-ORDER BY category.name, item.name`)
+func (r *Repository) LoadList(ctx context.Context) ([]Item, error) {
+	rows, err := r.q.LoadList(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	ret := []Item{}
-	for rows.Next() {
-		item := Item{
-			List: &ListItem{},
-		}
-		if err := rows.Scan(&item.ID, &item.Name, &item.CategoryID, &item.categoryName, &item.List.Quantity, &item.List.ID, &item.List.Done); err != nil {
-			return nil, err
-		}
-		ret = append(ret, item)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+	ret := make([]Item, 0, len(rows))
+	for _, row := range rows {
+		ret = append(ret, Item{
+			ID:           int(row.ID),
+			Name:         row.Name,
+			CategoryID:   int(row.CategoryID),
+			Version:      int(row.Version),
+			categoryName: row.CategoryName,
+			List: &ListItem{
+				ID:       int(row.ListID),
+				Quantity: row.ListQuantity,
+				Done:     row.ListDone,
+				Version:  int(row.ListVersion),
+			},
+		})
 	}
 
 	return ret, nil
 }
 
-func GetListItem(ctx context.Context, id int) (*ListItem, error) {
+func (r *Repository) GetListItem(ctx context.Context, id int) (*ListItem, error) {
 	if id == 0 {
 		return nil, errors.New("not a valid item")
 	}
 
-	row := db.QueryRowContext(ctx, `
-SELECT item_list.id, item_list.item_id, item.name, item.category_id, item_list.quantity, item_list.done
-FROM item_list
-INNER JOIN item ON (item_list.item_id = item.id)
-WHERE item_list.id = $1`, id)
-	if row.Err() != nil {
-		return nil, row.Err()
-	}
-
-	ret := &ListItem{}
-	if err := row.Scan(&ret.ID, &ret.ItemID, &ret.Name, &ret.CategoryID, &ret.Quantity, &ret.Done); err != nil {
+	row, err := r.q.GetListItem(ctx, int32(id))
+	if err != nil {
 		return nil, err
 	}
 
-	return ret, nil
+	return &ListItem{
+		ID:         int(row.ID),
+		ItemID:     int(row.ItemID),
+		Name:       row.Name,
+		CategoryID: strconv.Itoa(int(row.CategoryID)),
+		Quantity:   row.Quantity,
+		Done:       row.Done,
+		Version:    int(row.Version),
+	}, nil
+}
+
+// LockListItemVersion is LockItemVersion for the list entry of an item. It
+// also returns the ID of the entry, as the version starts over each time the
+// item is added to the list.
+func (r *Repository) LockListItemVersion(ctx context.Context, itemID int) (id, version int, err error) {
+	row, err := r.q.LockListItemVersion(ctx, int32(itemID))
+	return int(row.ID), int(row.Version), err
 }
 
-func ListAddItem(ctx context.Context, id int, quantity string) error {
+func (r *Repository) ListAddItem(ctx context.Context, id int, quantity string) error {
 	if id == 0 {
 		return errors.New("not a valid item")
 	}
 
-	_, err := db.ExecContext(ctx, `INSERT INTO item_list (item_id, quantity) VALUES ($1, $2)`, id, quantity)
-	return err
+	return r.InTx(ctx, func(tx *Repository) error {
+		_, err := tx.q.CreateListItem(ctx, dbmodels.CreateListItemParams{ItemID: int32(id), Quantity: quantity})
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, tx.q, EntityListItem, int32(id), ChangeCreate)
+	})
 }
 
-func MarkItemDone(ctx context.Context, id string, value bool) error {
-	_, err := db.ExecContext(ctx,
-		`UPDATE item_list SET done = $2 WHERE item_id = $1`,
-		id,
-		value,
-	)
+// ListChangeQuantity changes the quantity of an item on the list. Items that
+// are not on the list are ignored.
+func (r *Repository) ListChangeQuantity(ctx context.Context, id int, quantity string) error {
+	return r.InTx(ctx, func(tx *Repository) error {
+		_, err := changeListQuantity(ctx, tx.q, int32(id), quantity, time.Time{})
+		return err
+	})
+}
 
-	return err
+// MarkItemDone moves an item into or out of the cart. Items that are not on
+// the list are ignored.
+func (r *Repository) MarkItemDone(ctx context.Context, id int, value bool) error {
+	return r.InTx(ctx, func(tx *Repository) error {
+		_, err := markListItemDone(ctx, tx.q, int32(id), value, time.Time{})
+		return err
+	})
 }
 
-func DeleteFromList(ctx context.Context, id string) error {
-	_, err := db.ExecContext(ctx, `DELETE FROM item_list WHERE item_id = $1`, id)
-	return err
+func (r *Repository) DeleteFromList(ctx context.Context, id int) error {
+	return r.InTx(ctx, func(tx *Repository) error {
+		_, err := removeListItem(ctx, tx.q, int32(id), time.Time{})
+		return err
+	})
 }
 
-func FinishShopping(ctx context.Context) error {
-	_, err := db.ExecContext(ctx, "DELETE FROM item_list WHERE done = TRUE")
-	return err
+// The list entry helpers below report whether the item was on the list, and
+// record their change as made at the given time, or now when it is zero.
+
+func changeListQuantity(ctx context.Context, q *dbmodels.Queries, itemID int32, quantity string, at time.Time) (bool, error) {
+	_, err := q.UpdateListItemQuantity(ctx, dbmodels.UpdateListItemQuantityParams{ItemID: itemID, Quantity: quantity})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, recordListChange(ctx, q, itemID, ChangeUpdate, FieldQuantity, at)
+}
+
+func markListItemDone(ctx context.Context, q *dbmodels.Queries, itemID int32, done bool, at time.Time) (bool, error) {
+	_, err := q.MarkItemDone(ctx, dbmodels.MarkItemDoneParams{ItemID: itemID, Done: done})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, recordListChange(ctx, q, itemID, ChangeUpdate, FieldDone, at)
+}
+
+func removeListItem(ctx context.Context, q *dbmodels.Queries, itemID int32, at time.Time) (bool, error) {
+	removed, err := q.DeleteListItemForItem(ctx, itemID)
+	if err != nil || removed == 0 {
+		return false, err
+	}
+
+	return true, recordListChange(ctx, q, itemID, ChangeDelete, "", at)
+}
+
+func recordListChange(ctx context.Context, q *dbmodels.Queries, itemID int32, op ChangeOp, field string, at time.Time) error {
+	return RecordChange(ctx, q, Change{
+		Entity:    EntityListItem,
+		EntityID:  int(itemID),
+		Op:        op,
+		Field:     field,
+		ChangedAt: at,
+	})
+}
+
+// FinishShopping removes every item marked done from the list and adds the
+// purchased quantities to the pantry.
+func (r *Repository) FinishShopping(ctx context.Context) error {
+	return r.InTx(ctx, func(tx *Repository) error {
+		return finishShopping(ctx, tx.q)
+	})
+}
+
+func finishShopping(ctx context.Context, q *dbmodels.Queries) error {
+	purchased, err := q.ListDoneListItems(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range purchased {
+		err := q.AddPantryStock(ctx, dbmodels.AddPantryStockParams{
+			ItemID:   entry.ItemID,
+			Quantity: int32(purchasedUnits(entry.Quantity)),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := q.FinishShopping(ctx); err != nil {
+		return err
+	}
+
+	for _, entry := range purchased {
+		if err := recordChange(ctx, q, EntityListItem, entry.ItemID, ChangeDelete); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// purchasedUnits derives a whole number of units from a free-form list
+// quantity such as "2", "12 eggs" or "1 package". Quantities that do not start
+// with a whole number, like "0.5lb" or "", count as a single unit.
+func purchasedUnits(quantity string) int {
+	quantity = strings.TrimSpace(quantity)
+	end := strings.IndexFunc(quantity, func(r rune) bool { return !unicode.IsDigit(r) })
+	if end == -1 {
+		end = len(quantity)
+	}
+
+	// "1.5oz" is a measurement of one thing, not one-and-a-half things
+	if end < len(quantity) && (quantity[end] == '.' || quantity[end] == ',') {
+		return 1
+	}
+
+	units, err := strconv.Atoi(quantity[:end])
+	if err != nil || units < 1 {
+		return 1
+	}
+
+	return units
 }
@@ -0,0 +1,197 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+// ErrAlreadyListed is returned for a list entry whose item is already on the
+// shopping list.
+var ErrAlreadyListed = errors.New("item is already on the list")
+
+// maxEntryLength mirrors the VARCHAR(255) columns holding an entry's item name
+// and quantity.
+const maxEntryLength = 255
+
+// ListEntry is an item to add to the shopping list, identified by ID or,
+// when ItemID is zero, by name.
+type ListEntry struct {
+	ItemID   int
+	Name     string
+	Quantity string
+}
+
+// ListEntryResult reports the outcome of adding one ListEntry. Err is set
+// when the entry could not be added, because it is invalid (a
+// *dbmodels.FieldError), the item does not exist (sql.ErrNoRows) or it is
+// already on the list (ErrAlreadyListed).
+// When the entry's name matched no existing item a new item was created, and
+// Suggestions and Category describe how it was named and filed.
+type ListEntryResult struct {
+	Item        Item
+	Created     bool
+	Suggestions []ItemMatch
+	Category    *CategorySuggestion
+	Err         error
+}
+
+// ListAddItems adds many items to the shopping list in a single transaction.
+// Names are resolved the same way as a single addition, with FindItemByName
+// and, when nothing matches, AddItemByName, except that the catalog is loaded
+// once for the whole batch. Entries that cannot be added are reported in their
+// result without affecting the others; any other error rolls back the whole
+// batch.
+func (r *Repository) ListAddItems(ctx context.Context, entries []ListEntry) ([]ListEntryResult, error) {
+	ret := make([]ListEntryResult, 0, len(entries))
+	err := r.InTx(ctx, func(tx *Repository) error {
+		catalog := &batchCatalog{}
+		for _, entry := range entries {
+			result, err := listAddEntry(ctx, tx, catalog, entry)
+			if err != nil {
+				return err
+			}
+			ret = append(ret, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func listAddEntry(ctx context.Context, tx *Repository, catalog *batchCatalog, entry ListEntry) (ListEntryResult, error) {
+	var ret ListEntryResult
+	var err error
+
+	// Invalid entries are caught before reaching the database, where a failed
+	// statement would abort the whole transaction
+	entry.Name = strings.TrimSpace(entry.Name)
+	if err := validateListEntry(entry); err != nil {
+		ret.Err = err
+		return ret, nil
+	}
+
+	if entry.ItemID != 0 {
+		ret.Item, err = getItemForList(ctx, tx.q, entry.ItemID)
+	} else {
+		ret, err = resolveListEntry(ctx, tx, catalog, entry.Name)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		ret.Err = err
+		return ret, nil
+	} else if err != nil {
+		return ret, err
+	}
+
+	listItem := ListItem{Quantity: entry.Quantity}
+	id, err := tx.q.AddListItemIfMissing(ctx, dbmodels.AddListItemIfMissingParams{
+		ItemID:   int32(ret.Item.ID),
+		Quantity: entry.Quantity,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		ret.Err = ErrAlreadyListed
+		return ret, nil
+	} else if err != nil {
+		return ret, err
+	}
+
+	if err := recordChange(ctx, tx.q, EntityListItem, int32(ret.Item.ID), ChangeCreate); err != nil {
+		return ret, err
+	}
+
+	listItem.ID = int(id)
+	ret.Item.List = &listItem
+	return ret, nil
+}
+
+// validateListEntry checks the parts of an entry that the database would
+// otherwise reject.
+func validateListEntry(entry ListEntry) error {
+	var vErr error
+
+	if entry.ItemID == 0 && entry.Name == "" {
+		vErr = errors.Join(vErr, &dbmodels.FieldError{Field: "name", Err: dbmodels.ErrRequired, Message: "name is required"})
+	} else if entry.ItemID == 0 && utf8.RuneCountInString(entry.Name) > maxEntryLength {
+		vErr = errors.Join(vErr, &dbmodels.FieldError{Field: "name", Err: dbmodels.ErrTooLong, Message: "name cannot be longer than 255 characters"})
+	}
+
+	if utf8.RuneCountInString(entry.Quantity) > maxEntryLength {
+		vErr = errors.Join(vErr, &dbmodels.FieldError{Field: "quantity", Err: dbmodels.ErrTooLong, Message: "quantity cannot be longer than 255 characters"})
+	}
+
+	return vErr
+}
+
+// batchCatalog is the item catalog shared by the entries of a batch. It is
+// loaded when the first entry needs it and extended with the items the batch
+// creates, so that later entries see them as a fresh load would.
+type batchCatalog struct {
+	items  []Item
+	loaded bool
+}
+
+func (c *batchCatalog) load(ctx context.Context, tx *Repository) ([]Item, error) {
+	if c.loaded {
+		return c.items, nil
+	}
+
+	items, err := tx.LoadItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.items, c.loaded = items, true
+	return c.items, nil
+}
+
+func (c *batchCatalog) add(item Item) {
+	if c.loaded {
+		c.items = append(c.items, item)
+	}
+}
+
+// resolveListEntry finds the item with the given name or alias, creating it
+// when nothing matches. It runs in the caller's transaction, so items created
+// for earlier entries are found by later ones. Suggestions and the category
+// of a new item come from the batch's catalog, ranked in Go with the same
+// similarity measure as SearchItems.
+func resolveListEntry(ctx context.Context, tx *Repository, catalog *batchCatalog, name string) (ListEntryResult, error) {
+	ret := ListEntryResult{}
+
+	item, err := tx.GetItemByName(ctx, name)
+	if !errors.Is(err, sql.ErrNoRows) {
+		ret.Item = item
+		return ret, err
+	}
+
+	items, err := catalog.load(ctx, tx)
+	if err != nil {
+		return ret, err
+	}
+
+	ret.Created = true
+	ret.Suggestions = rankItems(items, name, nameSuggestions)
+	ret.Item, ret.Category, err = tx.addSuggestedItem(ctx, name, classify(items, name))
+	if err != nil {
+		return ret, err
+	}
+
+	catalog.add(ret.Item)
+	return ret, nil
+}
+
+func getItemForList(ctx context.Context, q *dbmodels.Queries, id int) (Item, error) {
+	row, err := q.SummarizeItem(ctx, int32(id))
+	if err != nil {
+		return Item{}, err
+	}
+
+	return itemFromSummary(row), nil
+}
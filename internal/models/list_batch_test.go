@@ -0,0 +1,61 @@
+package models
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+// TestRepository_ListAddItemsSuggests checks that a batch entry resembling an
+// existing item creates a new item, with the existing one suggested, rather
+// than being filed under it. The catalog is loaded once for the whole batch,
+// and an invalid entry is reported without reaching the database.
+func TestRepository_ListAddItemsSuggests(t *testing.T) {
+	db := &scriptedDB{rows: map[string][]driver.Value{
+		"sql":                  {false},
+		"SummarizeItems":       {int64(7), "Milk", int64(1), "Dairy", nil, nil, nil, int64(1), nil},
+		"GetCategory":          dairyRow,
+		"CreateItem":           {int64(8), int64(1), "Milkk", int64(1)},
+		"SummarizeItem":        {int64(8), int64(1), "Milkk", "Dairy", nil, int64(1)},
+		"AddListItemIfMissing": {int64(3)},
+	}}
+
+	got, err := db.repository().ListAddItems(context.Background(), []ListEntry{
+		{Name: "Milkk"},
+		{Name: strings.Repeat("a", 256)},
+		{Name: "Oat milk"},
+	})
+	if err != nil {
+		t.Fatalf("ListAddItems() error = %v", err)
+	}
+	if len(got) != 3 || got[0].Err != nil || got[2].Err != nil {
+		t.Fatalf("ListAddItems() = %+v, want the first and last entries added", got)
+	}
+	if !errors.Is(got[1].Err, dbmodels.ErrTooLong) {
+		t.Errorf("ListAddItems() entry error = %v, want %v", got[1].Err, dbmodels.ErrTooLong)
+	}
+
+	if !got[0].Created || got[0].Item.ID != 8 {
+		t.Errorf("ListAddItems() added item %d, created %v, want new item 8", got[0].Item.ID, got[0].Created)
+	}
+	if len(got[0].Suggestions) != 1 || got[0].Suggestions[0].Item.ID != 7 {
+		t.Errorf("ListAddItems() suggested %v, want item 7", got[0].Suggestions)
+	}
+
+	wantLog := []string{
+		"begin", "AdvisoryXactLock",
+		"FindItemByNameOrAlias", "SummarizeItems", "GetCategory", "CreateItem", "RecordChange", "SummarizeItem",
+		"ListItemBarcodes", "ListAliasesForItem", "AddListItemIfMissing", "RecordChange",
+		"FindItemByNameOrAlias", "GetCategory", "CreateItem", "RecordChange", "SummarizeItem",
+		"ListItemBarcodes", "ListAliasesForItem", "AddListItemIfMissing", "RecordChange",
+		"commit",
+	}
+	if !reflect.DeepEqual(db.log, wantLog) {
+		t.Errorf("ListAddItems() ran %q, want %q", db.log, wantLog)
+	}
+}
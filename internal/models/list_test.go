@@ -59,3 +59,29 @@ func TestListItem_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestPurchasedUnits(t *testing.T) {
+	tests := []struct {
+		quantity string
+		want     int
+	}{
+		{quantity: "", want: 1},
+		{quantity: "2", want: 2},
+		{quantity: " 3 ", want: 3},
+		{quantity: "12 eggs", want: 12},
+		{quantity: "1 package", want: 1},
+		{quantity: "4x", want: 4},
+		{quantity: "0.5lb", want: 1},
+		{quantity: "1.5oz", want: 1},
+		{quantity: "0", want: 1},
+		{quantity: "a few", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.quantity, func(t *testing.T) {
+			if got := purchasedUnits(tt.quantity); got != tt.want {
+				t.Errorf("purchasedUnits(%q) = %d, want %d", tt.quantity, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+// ErrMergeIntoSelf is returned when an item is merged into itself.
+var ErrMergeIntoSelf = errors.New("an item cannot be merged into itself")
+
+// MergeItem folds the source item into the target item and deletes the
+// source. Everything that references the source moves to the target: its list
+// entry, barcodes, aliases, staple schedule and pantry stock. Where both items
+// have an entry that may only exist once, the two are combined or the
+// target's is kept. The source's name becomes an alias of the target so that
+// adding it by name keeps working.
+func (r *Repository) MergeItem(ctx context.Context, sourceID, targetID int) error {
+	if sourceID == targetID {
+		return ErrMergeIntoSelf
+	}
+
+	return r.InTx(ctx, func(tx *Repository) error {
+		return mergeItem(ctx, tx.q, int32(sourceID), int32(targetID))
+	})
+}
+
+func mergeItem(ctx context.Context, q *dbmodels.Queries, sourceID, targetID int32) error {
+	sourceName, err := q.LockItemName(ctx, sourceID)
+	if err != nil {
+		return err
+	}
+	targetName, err := q.LockItemName(ctx, targetID)
+	if err != nil {
+		return err
+	}
+
+	if err := mergeListEntry(ctx, q, sourceID, targetID); err != nil {
+		return err
+	}
+
+	// Bagged items predate the shopping list and are kept on the target
+	if err := q.MoveItemBag(ctx, dbmodels.MoveItemBagParams{SourceID: sourceID, TargetID: targetID}); err != nil {
+		return err
+	}
+	if err := q.DeleteItemBag(ctx, sourceID); err != nil {
+		return err
+	}
+
+	if err := q.MoveItemBarcodes(ctx, dbmodels.MoveItemBarcodesParams{SourceID: sourceID, TargetID: targetID}); err != nil {
+		return err
+	}
+	if err := q.MoveItemAliases(ctx, dbmodels.MoveItemAliasesParams{SourceID: sourceID, TargetID: targetID}); err != nil {
+		return err
+	}
+
+	// The target's own schedule wins over the source's
+	if err := q.MoveItemRecurrence(ctx, dbmodels.MoveItemRecurrenceParams{SourceID: sourceID, TargetID: targetID}); err != nil {
+		return err
+	}
+
+	// Stock on hand is combined, keeping the earliest best-before date
+	if err := q.MergePantryStock(ctx, dbmodels.MergePantryStockParams{SourceID: sourceID, TargetID: targetID}); err != nil {
+		return err
+	}
+	if err := q.MovePantryItem(ctx, dbmodels.MovePantryItemParams{SourceID: sourceID, TargetID: targetID}); err != nil {
+		return err
+	}
+
+	if err := q.DeleteItem(ctx, sourceID); err != nil {
+		return err
+	}
+	if err := recordChange(ctx, q, EntityItem, sourceID, ChangeDelete); err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(sourceName, targetName) {
+		err := q.CreateItemAliasIfUnused(ctx, dbmodels.CreateItemAliasIfUnusedParams{ItemID: targetID, Name: sourceName})
+		if err != nil {
+			return err
+		}
+	}
+
+	// The target gained the source's aliases and barcodes
+	return recordChange(ctx, q, EntityItem, targetID, ChangeUpdate)
+}
+
+// mergeListEntry moves the source's list entry to the target. When both are
+// on the list their quantities are combined, and the merged entry is only done
+// if both were.
+func mergeListEntry(ctx context.Context, q *dbmodels.Queries, sourceID, targetID int32) error {
+	source, err := q.GetListItemForItem(ctx, sourceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	target, err := q.GetListItemForItem(ctx, targetID)
+	if errors.Is(err, sql.ErrNoRows) {
+		if err := q.MoveListItem(ctx, dbmodels.MoveListItemParams{SourceID: sourceID, TargetID: targetID}); err != nil {
+			return err
+		}
+		if err := recordChange(ctx, q, EntityListItem, sourceID, ChangeDelete); err != nil {
+			return err
+		}
+		return recordChange(ctx, q, EntityListItem, targetID, ChangeCreate)
+	} else if err != nil {
+		return err
+	}
+
+	if _, err := removeListItem(ctx, q, sourceID, time.Time{}); err != nil {
+		return err
+	}
+
+	_, err = q.UpdateListItem(ctx, dbmodels.UpdateListItemParams{
+		ItemID:   targetID,
+		Quantity: combineQuantities(target.Quantity, source.Quantity),
+		Done:     target.Done && source.Done,
+	})
+	if err != nil {
+		return err
+	}
+
+	return recordChange(ctx, q, EntityListItem, targetID, ChangeUpdate)
+}
+
+// combineQuantities joins two free-text list quantities.
+func combineQuantities(a, b string) string {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	switch {
+	case a == "":
+		return b
+	case b == "", strings.EqualFold(a, b):
+		return a
+	}
+	return a + " + " + b
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestCombineQuantities(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{a: "", b: "", want: ""},
+		{a: "2", b: "", want: "2"},
+		{a: "", b: "1 lb", want: "1 lb"},
+		{a: "1 dozen", b: "1 Dozen", want: "1 dozen"},
+		{a: "2", b: "1 lb", want: "2 + 1 lb"},
+	}
+
+	for _, tt := range tests {
+		if got := combineQuantities(tt.a, tt.b); got != tt.want {
+			t.Errorf("combineQuantities(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
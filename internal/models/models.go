@@ -7,36 +7,144 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"os"
-	"strings"
+	"sync"
+	"time"
 
-	internalDB "github.com/taiidani/groceries/internal/db"
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
 )
 
-var db *sql.DB
+// Repository loads and stores items, categories and the shopping list. It
+// builds on the generated queries, adding the validation, name matching and
+// multi-statement transactions the handlers need.
+//
+// Besides its connection a Repository only remembers whether pg_trgm is
+// installed, so each server creates its own from the shared connection pool
+// and tests can run one against a test database.
+type Repository struct {
+	// conn starts transactions. It is nil for a Repository bound to a
+	// transaction by InTx.
+	conn *sql.DB
+	db   dbmodels.DBTX
+	q    *dbmodels.Queries
 
-func InitDB(ctx context.Context) error {
-	switch os.Getenv("DB_TYPE") {
-	case "postgres":
-		client, err := internalDB.New(ctx, os.Getenv("DATABASE_URL"))
-		db = client
-		return err
-	default:
-		return errors.New("unknown DB_TYPE database version specified")
+	trigram *trigramSupport
+}
+
+// trigramSupport remembers whether the pg_trgm extension is installed.
+type trigramSupport struct {
+	conn *sql.DB
+
+	mu        sync.Mutex
+	probed    bool
+	available bool
+}
+
+// NewRepository returns a Repository that runs its queries on conn.
+func NewRepository(conn *sql.DB) *Repository {
+	r := &Repository{
+		conn:    conn,
+		db:      conn,
+		q:       dbmodels.New(conn),
+		trigram: &trigramSupport{conn: conn},
 	}
+	r.trigram.enabled()
+	return r
 }
 
-func insertWithID(ctx context.Context, tx *sql.Tx, query string, args ...any) (int, error) {
-	var id int
+// trigramProbeTimeout bounds the check for pg_trgm.
+const trigramProbeTimeout = 5 * time.Second
+
+// enabled reports whether pg_trgm is installed. The answer is looked up once,
+// on the connection pool and with its own context, so that a cancelled request
+// cannot fail the check and a failed check cannot abort a transaction. A check
+// that fails is retried on the next call.
+func (t *trigramSupport) enabled() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
-	if !strings.Contains(query, "RETURNING") {
-		return id, errors.New("inserting with ID requires the use of the RETURNING directive")
+	if !t.probed {
+		ctx, cancel := context.WithTimeout(context.Background(), trigramProbeTimeout)
+		defer cancel()
+
+		err := t.conn.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm')`).
+			Scan(&t.available)
+		t.probed = err == nil
 	}
 
-	err := tx.QueryRowContext(ctx, query, args...).Scan(&id)
+	return t.available
+}
+
+// InTx runs fn as a single unit of work. Every query made through the
+// Repository passed to fn runs in one transaction, which is committed when fn
+// returns nil and rolled back when it returns an error or panics.
+//
+// Methods that write several rows use InTx themselves. Called on a Repository
+// that is already bound to a transaction they join it instead of starting
+// their own, so a handler can combine several of them into one unit of work.
+//
+// Every unit of work holds the change log lock from its first statement, see
+// RecordChange. Taking it before any row lock means transactions always lock
+// in the same order and cannot deadlock on it.
+func (r *Repository) InTx(ctx context.Context, fn func(tx *Repository) error) error {
+	if r.conn == nil {
+		return fn(r)
+	}
+
+	return r.inTx(ctx, nil, func(tx *Repository) error {
+		if err := tx.q.AdvisoryXactLock(ctx, changeLogLockID); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// ReadSnapshot runs fn in a read-only transaction that sees the data as it was
+// when the transaction began, for reads such as backups that must be
+// consistent across many queries.
+func (r *Repository) ReadSnapshot(ctx context.Context, fn func(tx *Repository) error) error {
+	return r.inTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, fn)
+}
+
+func (r *Repository) inTx(ctx context.Context, opts *sql.TxOptions, fn func(tx *Repository) error) error {
+	if r.conn == nil {
+		return fn(r)
+	}
+
+	tx, err := r.conn.BeginTx(ctx, opts)
 	if err != nil {
-		return id, err
+		return err
 	}
 
-	return id, nil
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	bound := &Repository{
+		db:      tx,
+		q:       r.q.WithTx(tx),
+		trigram: r.trigram,
+	}
+	if err := fn(bound); err != nil {
+		return errors.Join(tx.Rollback(), err)
+	}
+
+	return tx.Commit()
+}
+
+// TryXactLock takes the Postgres advisory lock with the given key for the
+// rest of the transaction, reporting false if another session holds it. It is
+// only meaningful on a Repository bound to a transaction by InTx.
+func (r *Repository) TryXactLock(ctx context.Context, key int64) (bool, error) {
+	return r.q.TryAdvisoryXactLock(ctx, key)
+}
+
+// Queries exposes the generated queries beneath the Repository. It exists for
+// bulk operations such as backup and restore, which copy every table as is
+// and have no use for the Repository's validation. Everything else should go
+// through the Repository's own methods.
+func (r *Repository) Queries() *dbmodels.Queries {
+	return r.q
 }
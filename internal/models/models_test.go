@@ -0,0 +1,390 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+var errQueryFailed = errors.New("query failed")
+
+// scriptedDB is a database/sql driver that answers the generated queries by
+// name and records every query and transaction boundary. It lets the
+// Repository's transactional contract be tested without a database.
+type scriptedDB struct {
+	// rows holds the single row returned by each query. Queries without one
+	// return no rows.
+	rows map[string][]driver.Value
+	// fail names the query that returns failErr, or errQueryFailed when
+	// failErr is nil.
+	fail    string
+	failErr error
+
+	mu  sync.Mutex
+	log []string
+}
+
+var queryName = regexp.MustCompile(`^-- name: (\w+)`)
+
+// repository returns a Repository on db, leaving out of the log the check for
+// pg_trgm made when it is created.
+func (db *scriptedDB) repository() *Repository {
+	r := NewRepository(sql.OpenDB(db))
+	db.log = nil
+	return r
+}
+
+func (db *scriptedDB) record(entry string) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.log = append(db.log, entry)
+}
+
+func (db *scriptedDB) run(query string) ([]driver.Value, error) {
+	name := "sql"
+	if m := queryName.FindStringSubmatch(query); m != nil {
+		name = m[1]
+	}
+
+	db.record(name)
+	if name == db.fail && db.failErr != nil {
+		return nil, db.failErr
+	} else if name == db.fail {
+		return nil, fmt.Errorf("%s: %w", name, errQueryFailed)
+	}
+	return db.rows[name], nil
+}
+
+func (db *scriptedDB) Connect(context.Context) (driver.Conn, error) { return scriptedConn{db}, nil }
+func (db *scriptedDB) Driver() driver.Driver                        { return nil }
+
+type scriptedConn struct{ db *scriptedDB }
+
+func (c scriptedConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c scriptedConn) Close() error                        { return nil }
+func (c scriptedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c scriptedConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.db.record("begin")
+	return scriptedTx(c), nil
+}
+
+func (c scriptedConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if _, err := c.db.run(query); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c scriptedConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	row, err := c.db.run(query)
+	if err != nil {
+		return nil, err
+	}
+	return &scriptedRows{row: row}, nil
+}
+
+type scriptedTx struct{ db *scriptedDB }
+
+func (tx scriptedTx) Commit() error   { tx.db.record("commit"); return nil }
+func (tx scriptedTx) Rollback() error { tx.db.record("rollback"); return nil }
+
+type scriptedRows struct {
+	row  []driver.Value
+	done bool
+}
+
+func (r *scriptedRows) Columns() []string {
+	cols := make([]string, len(r.row))
+	for i := range cols {
+		cols[i] = fmt.Sprintf("column%d", i)
+	}
+	return cols
+}
+
+func (r *scriptedRows) Close() error { return nil }
+
+func (r *scriptedRows) Next(dest []driver.Value) error {
+	if r.done || r.row == nil {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.row)
+	return nil
+}
+
+// Rows returned by the scripted queries
+var (
+	dairyRow   = []driver.Value{int64(1), "Dairy", "", int64(1), int64(1)}
+	milkRow    = []driver.Value{int64(7), int64(1), "Milk", int64(1)}
+	summaryRow = []driver.Value{int64(7), int64(1), "Milk", "Dairy", nil, int64(1)}
+)
+
+func TestRepository_InTx(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func(tx *Repository) error
+		wantErr error
+		wantLog []string
+	}{
+		{
+			name:    "commits on success",
+			fn:      func(tx *Repository) error { return tx.DeleteItem(context.Background(), 7) },
+			wantLog: []string{"begin", "AdvisoryXactLock", "DeleteItem", "RecordChange", "commit"},
+		},
+		{
+			name: "rolls back on error",
+			fn: func(tx *Repository) error {
+				if err := tx.DeleteItem(context.Background(), 7); err != nil {
+					return err
+				}
+				return errQueryFailed
+			},
+			wantErr: errQueryFailed,
+			wantLog: []string{"begin", "AdvisoryXactLock", "DeleteItem", "RecordChange", "rollback"},
+		},
+		{
+			name: "nested units join the outer transaction",
+			fn: func(tx *Repository) error {
+				return tx.InTx(context.Background(), func(nested *Repository) error {
+					return nested.DeleteItem(context.Background(), 7)
+				})
+			},
+			wantLog: []string{"begin", "AdvisoryXactLock", "DeleteItem", "RecordChange", "commit"},
+		},
+		{
+			name: "multi-step methods join the outer transaction",
+			fn: func(tx *Repository) error {
+				if err := tx.SetItemAliases(context.Background(), 7, []string{"Moo juice"}); err != nil {
+					return err
+				}
+				return errQueryFailed
+			},
+			wantErr: errQueryFailed,
+			wantLog: []string{"begin", "AdvisoryXactLock", "AliasConflictsWithOtherItem", "DeleteItemAliases", "CreateItemAlias", "RecordChange", "rollback"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &scriptedDB{rows: map[string][]driver.Value{"AliasConflictsWithOtherItem": {false}}}
+
+			err := db.repository().InTx(context.Background(), tt.fn)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("InTx() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(db.log, tt.wantLog) {
+				t.Errorf("InTx() ran %q, want %q", db.log, tt.wantLog)
+			}
+		})
+	}
+}
+
+// TestRepository_RejectedEditKeepsAliases checks that an item edit that fails
+// validation also undoes the alias changes made in the same unit of work.
+func TestRepository_RejectedEditKeepsAliases(t *testing.T) {
+	// GetCategory returns no rows, so the edit names an unknown category
+	db := &scriptedDB{rows: map[string][]driver.Value{"AliasConflictsWithOtherItem": {false}}}
+	ctx := context.Background()
+
+	err := db.repository().InTx(ctx, func(tx *Repository) error {
+		if err := tx.SetItemAliases(ctx, 7, []string{"Moo juice"}); err != nil {
+			return err
+		}
+		return tx.EditItem(ctx, Item{ID: 7, CategoryID: 99, Name: "Milk"})
+	})
+	if _, ok := dbmodels.FieldErrors(err); !ok {
+		t.Fatalf("InTx() error = %v, want a validation error", err)
+	}
+
+	if !slices.Contains(db.log, "CreateItemAlias") {
+		t.Fatalf("InTx() ran %q, want the aliases to be written first", db.log)
+	}
+	if slices.Contains(db.log, "UpdateItem") || slices.Contains(db.log, "commit") {
+		t.Errorf("InTx() ran %q, want the edit to be rejected", db.log)
+	}
+	if last := db.log[len(db.log)-1]; last != "rollback" {
+		t.Errorf("InTx() ran %q, want the alias changes rolled back", db.log)
+	}
+}
+
+func TestRepository_InTxPanic(t *testing.T) {
+	db := &scriptedDB{}
+
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Errorf("recovered %v, want the original panic", p)
+		}
+		if want := []string{"begin", "AdvisoryXactLock", "rollback"}; !reflect.DeepEqual(db.log, want) {
+			t.Errorf("InTx() ran %q, want %q", db.log, want)
+		}
+	}()
+
+	_ = db.repository().InTx(context.Background(), func(*Repository) error { panic("boom") })
+}
+
+// TestRepository_RollsBackPartialWrites fails the last write of each
+// multi-step mutation and checks that the earlier writes are rolled back.
+func TestRepository_RollsBackPartialWrites(t *testing.T) {
+	tests := []struct {
+		name   string
+		rows   map[string][]driver.Value
+		fail   string
+		mutate func(ctx context.Context, r *Repository) error
+	}{
+		{
+			name: "DeleteItem",
+			fail: "RecordChange",
+			mutate: func(ctx context.Context, r *Repository) error {
+				return r.DeleteItem(ctx, 7)
+			},
+		},
+		{
+			name: "MarkItemDone",
+			rows: map[string][]driver.Value{"MarkItemDone": {int64(3), int64(7), "2", true, int64(2)}},
+			fail: "RecordChange",
+			mutate: func(ctx context.Context, r *Repository) error {
+				return r.MarkItemDone(ctx, 7, true)
+			},
+		},
+		{
+			name: "DeleteCategory",
+			rows: map[string][]driver.Value{"CountItemsForCategory": {int64(0)}},
+			fail: "DeleteCategory",
+			mutate: func(ctx context.Context, r *Repository) error {
+				return r.DeleteCategory(ctx, 1)
+			},
+		},
+		{
+			name: "SetItemAliases",
+			rows: map[string][]driver.Value{"AliasConflictsWithOtherItem": {false}},
+			fail: "CreateItemAlias",
+			mutate: func(ctx context.Context, r *Repository) error {
+				return r.SetItemAliases(ctx, 7, []string{"Moo juice"})
+			},
+		},
+		{
+			name: "MergeItem",
+			rows: map[string][]driver.Value{"LockItemName": {"Milk"}},
+			fail: "DeleteItem",
+			mutate: func(ctx context.Context, r *Repository) error {
+				return r.MergeItem(ctx, 7, 8)
+			},
+		},
+		{
+			name: "FinishShopping",
+			rows: map[string][]driver.Value{"ListDoneListItems": {int64(3), int64(7), "2", true, int64(1)}},
+			fail: "FinishShopping",
+			mutate: func(ctx context.Context, r *Repository) error {
+				return r.FinishShopping(ctx)
+			},
+		},
+		{
+			name: "ConsumePantryItem",
+			rows: map[string][]driver.Value{
+				"ConsumePantryItem": {int64(1), int64(7), int64(0), "pantry", nil, int64(1), nil},
+				"SummarizeItem":     summaryRow,
+				"CreateListItem":    {int64(3), int64(7), "", false, int64(1)},
+			},
+			fail: "RecordChange",
+			mutate: func(ctx context.Context, r *Repository) error {
+				_, _, err := r.ConsumePantryItem(ctx, 7, 1)
+				return err
+			},
+		},
+		{
+			name: "AddDueRecurringItems",
+			rows: map[string][]driver.Value{
+				"ListItemRecurrencesOffList": {int64(1), int64(7), int64(7), nil, "2", nil},
+				"CreateListItem":             {int64(3), int64(7), "2", false, int64(1)},
+			},
+			fail: "MarkItemRecurrenceAdded",
+			mutate: func(ctx context.Context, r *Repository) error {
+				_, err := r.AddDueRecurringItems(ctx, time.Now())
+				return err
+			},
+		},
+		{
+			name: "AssignBarcode",
+			rows: map[string][]driver.Value{"CreateItemBarcode": {int64(1), int64(7), "0123456789012"}},
+			fail: "RecordChange",
+			mutate: func(ctx context.Context, r *Repository) error {
+				_, _, err := r.AssignBarcode(ctx, 7, "0123456789012")
+				return err
+			},
+		},
+		{
+			name: "RemoveBarcode",
+			fail: "RecordChange",
+			mutate: func(ctx context.Context, r *Repository) error {
+				return r.RemoveBarcode(ctx, 7, "0123456789012")
+			},
+		},
+		{
+			name: "ListAddItems",
+			rows: map[string][]driver.Value{
+				"SummarizeItems": {int64(7), "Milk", int64(1), "Dairy", nil, nil, nil, int64(1), nil},
+				"SummarizeItem":  summaryRow,
+			},
+			fail: "AddListItemIfMissing",
+			mutate: func(ctx context.Context, r *Repository) error {
+				_, err := r.ListAddItems(ctx, []ListEntry{{ItemID: 7}})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &scriptedDB{rows: tt.rows, fail: tt.fail}
+
+			err := tt.mutate(context.Background(), db.repository())
+			if !errors.Is(err, errQueryFailed) {
+				t.Fatalf("%s() error = %v, want %v", tt.name, err, errQueryFailed)
+			}
+
+			begin := slices.Index(db.log, "begin")
+			if begin == -1 {
+				t.Fatalf("%s() ran %q outside a transaction", tt.name, db.log)
+			}
+			writes := db.log[begin+1 : len(db.log)-1]
+			if len(writes) < 2 || writes[len(writes)-1] != tt.fail {
+				t.Errorf("%s() ran %q, want several statements ending in %s", tt.name, writes, tt.fail)
+			}
+			if last := db.log[len(db.log)-1]; last != "rollback" || slices.Contains(db.log, "commit") {
+				t.Errorf("%s() ran %q, want a rollback and no commit", tt.name, db.log)
+			}
+		})
+	}
+}
+
+func TestRepository_AddReturnsCreatedIDs(t *testing.T) {
+	db := &scriptedDB{rows: map[string][]driver.Value{
+		"GetCategory":    dairyRow,
+		"CreateItem":     milkRow,
+		"CreateCategory": {int64(4), "Bakery", "", int64(1), int64(1)},
+	}}
+	r := db.repository()
+	ctx := context.Background()
+
+	if id, err := r.AddItem(ctx, Item{CategoryID: 1, Name: "Milk"}); err != nil || id != 7 {
+		t.Errorf("AddItem() = %d, %v, want 7", id, err)
+	}
+
+	if id, err := r.AddCategory(ctx, Category{StoreID: 1, Name: "Bakery"}); err != nil || id != 4 {
+		t.Errorf("AddCategory() = %d, %v, want 4", id, err)
+	}
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+// Orders a paged listing can be sorted in. A leading "-" sorts descending.
+// Rows with the same name are ordered by ID, so that every row falls on
+// exactly one page.
+const (
+	SortName     = "name"
+	SortNameDesc = "-name"
+	SortID       = "id"
+	SortIDDesc   = "-id"
+)
+
+// SortOrders lists the orders accepted by PageQuery.Sort.
+var SortOrders = []string{SortName, SortNameDesc, SortID, SortIDDesc}
+
+// PageQuery selects up to Limit rows of a listing, in the order given by
+// Sort, whose names contain Search. Pages after the first continue from the
+// row identified by After, which is the last row of the previous page.
+type PageQuery struct {
+	Search string
+	Sort   string
+	After  *PageKey
+	Limit  int
+}
+
+// PageKey identifies a row's position in a paged listing.
+type PageKey struct {
+	Name string
+	ID   int
+}
+
+// LoadStoresPage returns a page of stores.
+func (r *Repository) LoadStoresPage(ctx context.Context, query PageQuery) ([]dbmodels.Store, error) {
+	return r.q.ListStoresPage(ctx, dbmodels.ListStoresPageParams{
+		Search:    query.pattern(),
+		AfterID:   query.afterID(),
+		Sort:      query.Sort,
+		AfterName: query.afterName(),
+		PageLimit: int32(query.Limit),
+	})
+}
+
+// LoadUsersPage returns a page of users, searching and sorting by name.
+func (r *Repository) LoadUsersPage(ctx context.Context, query PageQuery) ([]dbmodels.User, error) {
+	return r.q.ListUsersPage(ctx, dbmodels.ListUsersPageParams{
+		Search:    query.pattern(),
+		AfterID:   query.afterID(),
+		Sort:      query.Sort,
+		AfterName: query.afterName(),
+		PageLimit: int32(query.Limit),
+	})
+}
+
+// likeEscaper escapes the characters LIKE treats specially, so that a search
+// matches them literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (p PageQuery) pattern() string {
+	return likeEscaper.Replace(p.Search)
+}
+
+func (p PageQuery) afterID() sql.NullInt32 {
+	if p.After == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: int32(p.After.ID), Valid: true}
+}
+
+func (p PageQuery) afterName() string {
+	if p.After == nil {
+		return ""
+	}
+	return p.After.Name
+}
@@ -0,0 +1,23 @@
+package models
+
+import "testing"
+
+func TestPageQuery_pattern(t *testing.T) {
+	tests := []struct {
+		search string
+		want   string
+	}{
+		{search: "milk", want: "milk"},
+		{search: "100%", want: `100\%`},
+		{search: "half_and_half", want: `half\_and\_half`},
+		{search: `a\b`, want: `a\\b`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			if got := (PageQuery{Search: tt.search}).pattern(); got != tt.want {
+				t.Errorf("pattern() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
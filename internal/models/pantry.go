@@ -0,0 +1,74 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+// LoadPantry returns the stock on hand, ordered by location and item name.
+func (r *Repository) LoadPantry(ctx context.Context) ([]dbmodels.ListPantryItemsRow, error) {
+	return r.q.ListPantryItems(ctx)
+}
+
+// ExpiringPantryItems returns the stocked pantry items whose best-before date
+// falls on or before the given day.
+func (r *Repository) ExpiringPantryItems(ctx context.Context, before time.Time) ([]dbmodels.ListExpiringPantryItemsRow, error) {
+	return r.q.ListExpiringPantryItems(ctx, before)
+}
+
+// MarkExpiringPantryItems records which stocked pantry items have a
+// best-before date on or before the given day, returning how many started or
+// stopped expiring since the last call. The state is kept in the database, so
+// each change is reported once however many schedulers are running.
+func (r *Repository) MarkExpiringPantryItems(ctx context.Context, before time.Time) (int, error) {
+	changed, err := r.q.MarkExpiringPantryItems(ctx, before)
+	return len(changed), err
+}
+
+func (r *Repository) GetPantryItem(ctx context.Context, itemID int32) (dbmodels.GetPantryItemRow, error) {
+	return r.q.GetPantryItem(ctx, itemID)
+}
+
+// SetPantryItem records the stock on hand for an item, replacing any previous
+// record.
+func (r *Repository) SetPantryItem(ctx context.Context, p dbmodels.PantryItem) (dbmodels.PantryItem, error) {
+	if err := r.q.ValidatePantryItem(ctx, p); err != nil {
+		return p, fmt.Errorf("invalid pantry item: %w", err)
+	}
+
+	return r.q.UpsertPantryItem(ctx, dbmodels.UpsertPantryItemParams{
+		ItemID:           p.ItemID,
+		Quantity:         p.Quantity,
+		Location:         p.Location,
+		BestBefore:       p.BestBefore,
+		RestockThreshold: p.RestockThreshold,
+	})
+}
+
+func (r *Repository) DeletePantryItem(ctx context.Context, itemID int32) error {
+	return r.q.DeletePantryItem(ctx, itemID)
+}
+
+// ConsumePantryItem takes an amount out of stock. When that drops the stock
+// below its restock threshold the item is placed back on the shopping list,
+// which is reported by addedToList.
+func (r *Repository) ConsumePantryItem(ctx context.Context, itemID int32, amount int32) (p dbmodels.PantryItem, addedToList bool, err error) {
+	err = r.InTx(ctx, func(tx *Repository) error {
+		p, err = tx.q.ConsumePantryItem(ctx, dbmodels.ConsumePantryItemParams{ItemID: itemID, Amount: amount})
+		if err != nil || !p.NeedsRestock() {
+			return err
+		}
+
+		item, err := tx.GetItem(ctx, int(itemID))
+		if err != nil || item.List != nil {
+			return err
+		}
+
+		addedToList = true
+		return tx.ListAddItem(ctx, item.ID, "")
+	})
+	return p, addedToList, err
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+func (r *Repository) GetItemRecurrence(ctx context.Context, itemID int32) (dbmodels.ItemRecurrence, error) {
+	return r.q.GetItemRecurrence(ctx, itemID)
+}
+
+// SetItemRecurrence makes an item a staple that is placed back on the list on
+// the given schedule, replacing any previous schedule.
+func (r *Repository) SetItemRecurrence(ctx context.Context, rec dbmodels.ItemRecurrence) (dbmodels.ItemRecurrence, error) {
+	if err := r.q.ValidateItemRecurrence(ctx, rec); err != nil {
+		return rec, fmt.Errorf("invalid recurrence: %w", err)
+	}
+
+	return r.q.UpsertItemRecurrence(ctx, dbmodels.UpsertItemRecurrenceParams{
+		ItemID:       rec.ItemID,
+		IntervalDays: rec.IntervalDays,
+		Weekday:      rec.Weekday,
+		Quantity:     rec.Quantity,
+	})
+}
+
+func (r *Repository) DeleteItemRecurrence(ctx context.Context, itemID int32) error {
+	return r.q.DeleteItemRecurrence(ctx, itemID)
+}
+
+// AddDueRecurringItems places every staple that is due at the given time and
+// not already on the list back onto it, returning how many were added.
+func (r *Repository) AddDueRecurringItems(ctx context.Context, now time.Time) (int, error) {
+	added := 0
+
+	err := r.InTx(ctx, func(tx *Repository) error {
+		recurrences, err := tx.q.ListItemRecurrencesOffList(ctx)
+		if err != nil {
+			return fmt.Errorf("could not load recurring items: %w", err)
+		}
+
+		for _, rec := range recurrences {
+			if !rec.Due(now) {
+				continue
+			}
+
+			if err := tx.ListAddItem(ctx, int(rec.ItemID), rec.Quantity); err != nil {
+				return fmt.Errorf("could not add recurring item %d: %w", rec.ItemID, err)
+			}
+
+			err = tx.q.MarkItemRecurrenceAdded(ctx, dbmodels.MarkItemRecurrenceAddedParams{
+				ItemID:      rec.ItemID,
+				LastAddedAt: sql.NullTime{Time: now, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("could not mark recurring item %d: %w", rec.ItemID, err)
+			}
+
+			added++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return added, nil
+}
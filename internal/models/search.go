@@ -0,0 +1,181 @@
+package models
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ItemMatch is an item returned by SearchItems. Score ranks the match, with
+// exact, prefix and substring matches ahead of purely fuzzy ones. Similarity
+// is the raw trigram similarity between the query and the item name.
+type ItemMatch struct {
+	Item       Item
+	Score      float64
+	Similarity float64
+}
+
+const (
+	// minSimilarity mirrors pg_trgm's default similarity threshold.
+	minSimilarity = 0.3
+
+	// nameSuggestions is how many similar items FindItemByName suggests.
+	nameSuggestions = 10
+)
+
+// SearchItems returns up to limit items whose names resemble the query,
+// best match first. Matching is case-insensitive and tolerant of typos. The
+// pg_trgm extension is used when installed, otherwise items are ranked in Go
+// using the same trigram similarity measure.
+func (r *Repository) SearchItems(ctx context.Context, query string, limit int) ([]ItemMatch, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []ItemMatch{}, nil
+	}
+
+	if r.trigram.enabled() {
+		return r.searchItemsTrigram(ctx, query, limit)
+	}
+
+	items, err := r.LoadItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rankItems(items, query, limit), nil
+}
+
+// FindItemByName returns the item with the given name or alias. When there
+// is none it returns sql.ErrNoRows along with the existing items whose names
+// resemble it, best match first. A similar item is never substituted for the
+// name: the caller offers the suggestions, such as "Banana" for "bannana",
+// and lets the user pick one.
+func (r *Repository) FindItemByName(ctx context.Context, name string) (Item, []ItemMatch, error) {
+	item, err := r.GetItemByName(ctx, name)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return item, nil, err
+	}
+
+	suggestions, err := r.SearchItems(ctx, name, nameSuggestions)
+	if err != nil {
+		return item, nil, err
+	}
+
+	return item, suggestions, sql.ErrNoRows
+}
+
+// searchItemsTrigram ranks items in the database. It is written by hand rather
+// than generated because pg_trgm is optional, and sqlc cannot check queries
+// against functions and operators from an extension that may be missing.
+func (r *Repository) searchItemsTrigram(ctx context.Context, query string, limit int) ([]ItemMatch, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, name, category_id, category_name, similarity, GREATEST(similarity, boost) AS score
+FROM (
+	SELECT item.id, item.name, item.category_id, category.name AS category_name,
+		similarity(lower(item.name), lower($1)) AS similarity,
+		CASE
+			WHEN lower(item.name) = lower($1) THEN 1.0
+			WHEN starts_with(lower(item.name), lower($1)) THEN 0.9
+			WHEN strpos(lower(item.name), lower($1)) > 0 THEN 0.8
+			ELSE 0
+		END AS boost
+	FROM item
+	LEFT JOIN category ON (item.category_id = category.id)
+	WHERE lower(item.name) % lower($1) OR strpos(lower(item.name), lower($1)) > 0
+) AS candidates
+ORDER BY score DESC, name
+LIMIT $2`, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := []ItemMatch{}
+	for rows.Next() {
+		match := ItemMatch{}
+		if err := rows.Scan(&match.Item.ID, &match.Item.Name, &match.Item.CategoryID, &match.Item.categoryName, &match.Similarity, &match.Score); err != nil {
+			return nil, err
+		}
+		ret = append(ret, match)
+	}
+
+	return ret, rows.Err()
+}
+
+// rankItems is the in-Go equivalent of searchItemsTrigram.
+func rankItems(items []Item, query string, limit int) []ItemMatch {
+	query = strings.ToLower(query)
+	queryTrigrams := trigrams(query)
+
+	ret := []ItemMatch{}
+	for _, item := range items {
+		name := strings.ToLower(item.Name)
+		match := ItemMatch{Item: item, Similarity: similarity(queryTrigrams, trigrams(name))}
+
+		switch {
+		case name == query:
+			match.Score = 1
+		case strings.HasPrefix(name, query):
+			match.Score = 0.9
+		case strings.Contains(name, query):
+			match.Score = 0.8
+		case match.Similarity < minSimilarity:
+			continue
+		}
+		match.Score = max(match.Score, match.Similarity)
+
+		ret = append(ret, match)
+	}
+
+	slices.SortStableFunc(ret, func(a, b ItemMatch) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Item.Name, b.Item.Name)
+	})
+
+	if limit > 0 && len(ret) > limit {
+		ret = ret[:limit]
+	}
+
+	return ret
+}
+
+// trigrams returns the set of trigrams in s the way pg_trgm computes them:
+// each alphanumeric word is padded with two spaces in front and one behind.
+func trigrams(s string) map[string]struct{} {
+	ret := map[string]struct{}{}
+
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			ret[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+
+	return ret
+}
+
+// similarity is the Jaccard index of two trigram sets, matching pg_trgm's
+// similarity() function.
+func similarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for trigram := range a {
+		if _, ok := b[trigram]; ok {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		// Values cross-checked against pg_trgm's similarity()
+		{a: "banana", b: "banana", want: 1},
+		{a: "bannana", b: "banana", want: 0.75},
+		{a: "Banana", b: "BANANA", want: 1},
+		{a: "word", b: "two words", want: 0.363636},
+		{a: "milk", b: "eggs", want: 0},
+		{a: "", b: "eggs", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			got := similarity(trigrams(tt.a), trigrams(tt.b))
+			if math.Abs(got-tt.want) > 0.0001 {
+				t.Errorf("similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankItems(t *testing.T) {
+	items := []Item{
+		{ID: 1, Name: "Bananas"},
+		{ID: 2, Name: "Banana bread"},
+		{ID: 3, Name: "Milk"},
+		{ID: 4, Name: "Oat milk"},
+		{ID: 5, Name: "Bandages"},
+	}
+
+	names := func(matches []ItemMatch) []string {
+		ret := []string{}
+		for _, m := range matches {
+			ret = append(ret, m.Item.Name)
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []string
+	}{
+		{name: "exact beats substring", query: "milk", want: []string{"Milk", "Oat milk"}},
+		{name: "prefix", query: "bana", want: []string{"Banana bread", "Bananas"}},
+		{name: "typo", query: "bannanas", want: []string{"Bananas", "Banana bread"}},
+		{name: "limit", query: "milk", limit: 1, want: []string{"Milk"}},
+		{name: "no match", query: "coffee", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := names(rankItems(items, tt.query, tt.limit))
+			if len(got) != len(tt.want) {
+				t.Fatalf("rankItems(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("rankItems(%q) = %v, want %v", tt.query, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRepository_FindItemByNameSuggests(t *testing.T) {
+	db := &scriptedDB{rows: map[string][]driver.Value{
+		"sql":            {false},
+		"SummarizeItems": {int64(7), "Milk", int64(1), "Dairy", nil, nil, nil, int64(1), nil},
+	}}
+
+	item, suggestions, err := db.repository().FindItemByName(context.Background(), "Milkk")
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("FindItemByName() error = %v, want %v", err, sql.ErrNoRows)
+	}
+	if item.ID != 0 {
+		t.Errorf("FindItemByName() = item %d, want none", item.ID)
+	}
+	if len(suggestions) != 1 || suggestions[0].Item.ID != 7 {
+		t.Errorf("FindItemByName() suggested %v, want item 7", suggestions)
+	}
+}
+
+func TestTrigramSupport_RetriesFailedCheck(t *testing.T) {
+	db := &scriptedDB{fail: "sql"}
+	r := db.repository()
+
+	if r.trigram.enabled() {
+		t.Fatal("enabled() = true after a failed check")
+	}
+
+	db.fail = ""
+	db.rows = map[string][]driver.Value{"sql": {true}}
+	if !r.trigram.enabled() {
+		t.Fatal("enabled() = false, want the failed check retried")
+	}
+
+	db.rows = nil
+	db.log = nil
+	if !r.trigram.enabled() || len(db.log) != 0 {
+		t.Errorf("enabled() ran %q, want the answer remembered", db.log)
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+// ErrStoreInUse is returned when deleting a store that still has categories.
+var ErrStoreInUse = errors.New("store is still in use")
+
+// LoadStores returns every store, ordered by name.
+func (r *Repository) LoadStores(ctx context.Context) ([]dbmodels.Store, error) {
+	return r.q.ListStores(ctx)
+}
+
+func (r *Repository) GetStore(ctx context.Context, id int32) (dbmodels.Store, error) {
+	return r.q.GetStore(ctx, id)
+}
+
+// StoreCategories returns the categories filed under a store.
+func (r *Repository) StoreCategories(ctx context.Context, id int32) ([]dbmodels.Category, error) {
+	return r.q.ListCategoriesForStore(ctx, id)
+}
+
+// AddStore creates a new store.
+func (r *Repository) AddStore(ctx context.Context, name string) (dbmodels.Store, error) {
+	if err := r.q.ValidateStore(ctx, dbmodels.Store{Name: name}); err != nil {
+		return dbmodels.Store{}, fmt.Errorf("invalid store: %w", err)
+	}
+
+	var store dbmodels.Store
+	err := r.InTx(ctx, func(tx *Repository) (err error) {
+		store, err = tx.q.CreateStore(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, tx.q, EntityStore, store.ID, ChangeCreate)
+	})
+
+	return store, err
+}
+
+// RenameStore changes the name of a store, returning sql.ErrNoRows if it does
+// not exist.
+func (r *Repository) RenameStore(ctx context.Context, id int32, name string) (dbmodels.Store, error) {
+	if err := r.q.ValidateStore(ctx, dbmodels.Store{ID: id, Name: name}); err != nil {
+		return dbmodels.Store{}, fmt.Errorf("invalid store: %w", err)
+	}
+
+	var store dbmodels.Store
+	err := r.InTx(ctx, func(tx *Repository) (err error) {
+		store, err = tx.q.UpdateStore(ctx, dbmodels.UpdateStoreParams{ID: id, Name: name})
+		if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, tx.q, EntityStore, store.ID, ChangeUpdate)
+	})
+
+	return store, err
+}
+
+func (r *Repository) DeleteStore(ctx context.Context, id int32) error {
+	return r.InTx(ctx, func(tx *Repository) error {
+		err := tx.q.DeleteStore(ctx, id)
+		if dbmodels.IsReferenced(err) {
+			return ErrStoreInUse
+		} else if err != nil {
+			return err
+		}
+
+		return recordChange(ctx, tx.q, EntityStore, id, ChangeDelete)
+	})
+}
@@ -0,0 +1,213 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+// SyncOpType is an offline change to the shopping list.
+type SyncOpType string
+
+const (
+	SyncAdd      SyncOpType = "add"
+	SyncCheck    SyncOpType = "check"
+	SyncQuantity SyncOpType = "quantity"
+	SyncRemove   SyncOpType = "remove"
+)
+
+// SyncOp is a change a client made to the shopping list while offline, at
+// the time At on the client's clock. The item is identified by ItemID or,
+// when ItemID is zero, by Name, so that clients can refer to items they added
+// by name before learning their ID.
+type SyncOp struct {
+	Type     SyncOpType
+	ItemID   int
+	Name     string
+	Quantity string
+	Done     bool
+	At       time.Time
+}
+
+// SyncStatus is the outcome of applying a SyncOp.
+type SyncStatus string
+
+const (
+	// SyncApplied means the change was made, or that the list was already in
+	// the state it asked for.
+	SyncApplied SyncStatus = "applied"
+	// SyncSuperseded means a later change to the same entry won.
+	SyncSuperseded SyncStatus = "superseded"
+	// SyncNotFound means the item does not exist, or is not on the list for
+	// an operation that changes an existing entry.
+	SyncNotFound SyncStatus = "not_found"
+	// SyncInvalid means the operation is malformed.
+	SyncInvalid SyncStatus = "invalid"
+)
+
+// SyncResult reports the outcome of one SyncOp. ItemID is the item the
+// operation resolved to, and is set for items created by an add.
+type SyncResult struct {
+	Status SyncStatus
+	ItemID int
+}
+
+// ApplySync applies a batch of offline changes to the shopping list in a
+// single transaction, returning a result for each operation in order.
+//
+// Conflicts are resolved per list entry by the time each change was made, so
+// that the outcome does not depend on which client synced first:
+//   - Operations are applied in order of their timestamps, ties keeping their
+//     order in the batch.
+//   - Checking an item off and changing its quantity touch different fields of
+//     the entry and never conflict with each other. Adding and removing touch
+//     the whole entry.
+//   - An operation is superseded when the change log holds a later change to
+//     any field it touches, whether made by another client or on the server.
+//
+// Timestamps ahead of the server's clock are treated as now, so that a client
+// with a fast clock cannot lock an entry against every other writer.
+func (r *Repository) ApplySync(ctx context.Context, ops []SyncOp) ([]SyncResult, error) {
+	now := time.Now()
+	order := make([]int, len(ops))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return ops[a].At.Compare(ops[b].At)
+	})
+
+	ret := make([]SyncResult, len(ops))
+	err := r.InTx(ctx, func(tx *Repository) error {
+		catalog := &batchCatalog{}
+		for _, i := range order {
+			op := ops[i]
+			if op.At.IsZero() || op.At.After(now) {
+				op.At = now
+			}
+
+			result, err := applySyncOp(ctx, tx, catalog, op)
+			if err != nil {
+				return err
+			}
+			ret[i] = result
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func applySyncOp(ctx context.Context, tx *Repository, catalog *batchCatalog, op SyncOp) (SyncResult, error) {
+	field := ""
+	switch op.Type {
+	case SyncAdd, SyncRemove:
+	case SyncCheck:
+		field = FieldDone
+	case SyncQuantity:
+		field = FieldQuantity
+	default:
+		return SyncResult{Status: SyncInvalid}, nil
+	}
+
+	// Overlong values would fail in the database, aborting the whole sync
+	if utf8.RuneCountInString(op.Name) > maxEntryLength || utf8.RuneCountInString(op.Quantity) > maxEntryLength {
+		return SyncResult{Status: SyncInvalid}, nil
+	}
+
+	itemID, err := resolveSyncItem(ctx, tx, catalog, op)
+	if errors.Is(err, sql.ErrNoRows) {
+		return SyncResult{Status: SyncNotFound}, nil
+	} else if err != nil {
+		return SyncResult{}, err
+	}
+	ret := SyncResult{Status: SyncApplied, ItemID: int(itemID)}
+
+	// Locking the item serializes syncs touching the same entry, so that the
+	// conflict check below stays true until the change is made
+	if _, err := tx.q.LockItemVersion(ctx, itemID); errors.Is(err, sql.ErrNoRows) {
+		return SyncResult{Status: SyncNotFound}, nil
+	} else if err != nil {
+		return ret, err
+	}
+
+	latest, err := tx.q.LatestChange(ctx, dbmodels.LatestChangeParams{
+		Entity:   EntityListItem,
+		EntityID: itemID,
+		Field:    field,
+	})
+	if err == nil && latest.After(op.At) {
+		ret.Status = SyncSuperseded
+		return ret, nil
+	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return ret, err
+	}
+
+	var listed bool
+	switch op.Type {
+	case SyncAdd:
+		listed, err = syncAdd(ctx, tx.q, itemID, op)
+	case SyncCheck:
+		listed, err = markListItemDone(ctx, tx.q, itemID, op.Done, op.At)
+	case SyncQuantity:
+		listed, err = changeListQuantity(ctx, tx.q, itemID, op.Quantity, op.At)
+	case SyncRemove:
+		// Removing an entry that is already gone leaves the list as asked
+		_, err = removeListItem(ctx, tx.q, itemID, op.At)
+		listed = true
+	}
+	if err != nil {
+		return ret, err
+	}
+	if !listed {
+		ret.Status = SyncNotFound
+	}
+
+	return ret, nil
+}
+
+// resolveSyncItem finds the item an operation refers to. Adding a name that
+// matches no item creates it, as adding it online would.
+func resolveSyncItem(ctx context.Context, tx *Repository, catalog *batchCatalog, op SyncOp) (int32, error) {
+	if op.ItemID != 0 {
+		return int32(op.ItemID), nil
+	}
+
+	name := strings.TrimSpace(op.Name)
+	if name == "" {
+		return 0, sql.ErrNoRows
+	}
+	if op.Type != SyncAdd {
+		item, err := getItemByName(ctx, tx.q, name)
+		return int32(item.ID), err
+	}
+
+	result, err := resolveListEntry(ctx, tx, catalog, name)
+	return int32(result.Item.ID), err
+}
+
+// syncAdd puts an item on the list. An item that is already listed keeps its
+// entry, taking the operation's quantity if it has one.
+func syncAdd(ctx context.Context, q *dbmodels.Queries, itemID int32, op SyncOp) (bool, error) {
+	_, err := q.AddListItemIfMissing(ctx, dbmodels.AddListItemIfMissingParams{ItemID: itemID, Quantity: op.Quantity})
+	if err == nil {
+		return true, recordListChange(ctx, q, itemID, ChangeCreate, "", op.At)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+
+	if op.Quantity == "" {
+		return true, nil
+	}
+
+	return changeListQuantity(ctx, q, itemID, op.Quantity, op.At)
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRepository_ApplySync(t *testing.T) {
+	at := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	listRow := []driver.Value{int64(3), int64(7), "2", true, int64(2)}
+
+	tests := []struct {
+		name       string
+		rows       map[string][]driver.Value
+		op         SyncOp
+		want       SyncStatus
+		wantChange bool
+	}{
+		{
+			name:       "applies a change to an unchanged entry",
+			rows:       map[string][]driver.Value{"MarkItemDone": listRow},
+			op:         SyncOp{Type: SyncCheck, ItemID: 7, Done: true, At: at},
+			want:       SyncApplied,
+			wantChange: true,
+		},
+		{
+			name: "applies a change made after the latest one",
+			rows: map[string][]driver.Value{
+				"LatestChange":           {at.Add(-time.Minute)},
+				"UpdateListItemQuantity": listRow,
+			},
+			op:         SyncOp{Type: SyncQuantity, ItemID: 7, Quantity: "3", At: at},
+			want:       SyncApplied,
+			wantChange: true,
+		},
+		{
+			name: "a tie goes to the operation",
+			rows: map[string][]driver.Value{
+				"LatestChange": {at},
+				"MarkItemDone": listRow,
+			},
+			op:         SyncOp{Type: SyncCheck, ItemID: 7, Done: true, At: at},
+			want:       SyncApplied,
+			wantChange: true,
+		},
+		{
+			name: "a later change supersedes the operation",
+			rows: map[string][]driver.Value{"LatestChange": {at.Add(time.Minute)}},
+			op:   SyncOp{Type: SyncRemove, ItemID: 7, At: at},
+			want: SyncSuperseded,
+		},
+		{
+			name: "checking off an entry that is gone",
+			op:   SyncOp{Type: SyncCheck, ItemID: 7, Done: true, At: at},
+			want: SyncNotFound,
+		},
+		{
+			name:       "removing an entry",
+			op:         SyncOp{Type: SyncRemove, ItemID: 7, At: at},
+			want:       SyncApplied,
+			wantChange: true,
+		},
+		{
+			name:       "adding an item",
+			rows:       map[string][]driver.Value{"AddListItemIfMissing": {int64(3)}},
+			op:         SyncOp{Type: SyncAdd, ItemID: 7, Quantity: "2", At: at},
+			want:       SyncApplied,
+			wantChange: true,
+		},
+		{
+			name: "adding an item that is already listed",
+			op:   SyncOp{Type: SyncAdd, ItemID: 7, At: at},
+			want: SyncApplied,
+		},
+		{
+			name: "unknown operation",
+			op:   SyncOp{Type: "rename", ItemID: 7, At: at},
+			want: SyncInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := map[string][]driver.Value{"LockItemVersion": {int64(1)}}
+			for name, row := range tt.rows {
+				rows[name] = row
+			}
+			db := &scriptedDB{rows: rows}
+
+			got, err := db.repository().ApplySync(context.Background(), []SyncOp{tt.op})
+			if err != nil {
+				t.Fatalf("ApplySync() error = %v", err)
+			}
+			if got[0].Status != tt.want {
+				t.Errorf("ApplySync() status = %q, want %q", got[0].Status, tt.want)
+			}
+			if recorded := slices.Contains(db.log, "RecordChange"); recorded != tt.wantChange {
+				t.Errorf("ApplySync() ran %q, want a recorded change: %v", db.log, tt.wantChange)
+			}
+		})
+	}
+}
+
+func TestRepository_ApplySyncOrder(t *testing.T) {
+	at := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	db := &scriptedDB{rows: map[string][]driver.Value{
+		"LockItemVersion": {int64(1)},
+		"MarkItemDone":    {int64(3), int64(7), "2", true, int64(2)},
+	}}
+
+	// Operations are applied oldest first and reported in request order
+	got, err := db.repository().ApplySync(context.Background(), []SyncOp{
+		{Type: SyncQuantity, ItemID: 7, Quantity: "3", At: at.Add(time.Minute)},
+		{Type: SyncCheck, ItemID: 7, Done: true, At: at},
+	})
+	if err != nil {
+		t.Fatalf("ApplySync() error = %v", err)
+	}
+
+	want := []SyncResult{{Status: SyncNotFound, ItemID: 7}, {Status: SyncApplied, ItemID: 7}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplySync() = %+v, want %+v", got, want)
+	}
+
+	done := slices.Index(db.log, "MarkItemDone")
+	quantity := slices.Index(db.log, "UpdateListItemQuantity")
+	if done == -1 || quantity == -1 || done > quantity {
+		t.Errorf("ApplySync() ran %q, want the check off before the quantity change", db.log)
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	dbmodels "github.com/taiidani/groceries/internal/db/models"
+)
+
+// LoadUsers returns every user, ordered by name.
+func (r *Repository) LoadUsers(ctx context.Context) ([]dbmodels.User, error) {
+	return r.q.ListUsers(ctx)
+}
+
+func (r *Repository) GetUser(ctx context.Context, id int32) (dbmodels.User, error) {
+	return r.q.GetUser(ctx, id)
+}
+
+func (r *Repository) GetUserByName(ctx context.Context, name string) (dbmodels.User, error) {
+	return r.q.GetUserByName(ctx, name)
+}
+
+// AddUser creates a new user. User names must be unique.
+func (r *Repository) AddUser(ctx context.Context, u dbmodels.User) (dbmodels.User, error) {
+	vErr := r.q.ValidateUser(ctx, u)
+
+	_, err := r.q.GetUserByName(ctx, u.Name)
+	if err == nil {
+		vErr = errors.Join(vErr, &dbmodels.FieldError{
+			Field:   "name",
+			Err:     dbmodels.ErrDuplicate,
+			Message: fmt.Sprintf("user %q already exists", u.Name),
+		})
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return u, fmt.Errorf("could not look up user: %w", err)
+	}
+
+	if vErr != nil {
+		return u, fmt.Errorf("invalid user: %w", vErr)
+	}
+
+	return r.q.CreateUser(ctx, dbmodels.CreateUserParams{Name: u.Name, Admin: u.Admin})
+}
+
+// EditUser changes the name and role of a user, returning sql.ErrNoRows if it
+// does not exist.
+func (r *Repository) EditUser(ctx context.Context, u dbmodels.User) (dbmodels.User, error) {
+	if err := r.q.ValidateUser(ctx, u); err != nil {
+		return u, fmt.Errorf("invalid user: %w", err)
+	}
+
+	return r.q.UpdateUser(ctx, dbmodels.UpdateUserParams{ID: u.ID, Name: u.Name, Admin: u.Admin})
+}
+
+func (r *Repository) DeleteUser(ctx context.Context, id int32) error {
+	return r.q.DeleteUser(ctx, id)
+}
@@ -0,0 +1,36 @@
+// Package products resolves UPC/EAN barcodes to product details using a
+// pluggable lookup provider. It is consulted when a scanned barcode has not yet
+// been associated with an item, so that the item can be named automatically.
+package products
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// Product describes a packaged product identified by its barcode.
+type Product struct {
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Brand string `json:"brand"`
+}
+
+// Provider looks up product details for a normalized barcode.
+type Provider interface {
+	Lookup(ctx context.Context, code string) (Product, error)
+}
+
+var (
+	ErrNotFound = errors.New("product not found")
+)
+
+// NewFromEnv returns the provider configured by the PRODUCT_LOOKUP_URL env var.
+// When it is unset, barcodes are only resolved against known items.
+func NewFromEnv() Provider {
+	if url, ok := os.LookupEnv("PRODUCT_LOOKUP_URL"); ok && url != "" {
+		return NewOpenFoodFacts(url)
+	}
+
+	return Offline{}
+}
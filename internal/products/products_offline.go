@@ -0,0 +1,18 @@
+package products
+
+import "context"
+
+// Offline is a fixed catalog of products keyed by barcode. It never touches
+// the network, making it suitable for tests and for deployments without an
+// external lookup service.
+type Offline map[string]Product
+
+func (o Offline) Lookup(ctx context.Context, code string) (Product, error) {
+	product, ok := o[code]
+	if !ok {
+		return Product{}, ErrNotFound
+	}
+
+	product.Code = code
+	return product, nil
+}
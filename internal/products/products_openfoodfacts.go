@@ -0,0 +1,72 @@
+package products
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const openFoodFactsTimeout = 5 * time.Second
+
+// OpenFoodFacts looks up products using the Open Food Facts v2 product API, or
+// any service exposing the same response shape.
+type OpenFoodFacts struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewOpenFoodFacts(baseURL string) *OpenFoodFacts {
+	return &OpenFoodFacts{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: openFoodFactsTimeout},
+	}
+}
+
+func (o *OpenFoodFacts) Lookup(ctx context.Context, code string) (Product, error) {
+	endpoint := fmt.Sprintf("%s/api/v2/product/%s.json?fields=product_name,brands", o.baseURL, url.PathEscape(code))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Product{}, err
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return Product{}, fmt.Errorf("could not reach product lookup: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return Product{}, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return Product{}, fmt.Errorf("product lookup returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Status  int `json:"status"`
+		Product struct {
+			Name   string `json:"product_name"`
+			Brands string `json:"brands"`
+		} `json:"product"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Product{}, fmt.Errorf("could not decode product lookup response: %w", err)
+	}
+
+	if body.Status != 1 || body.Product.Name == "" {
+		return Product{}, ErrNotFound
+	}
+
+	// Brands are a comma separated list with the primary brand first
+	brand, _, _ := strings.Cut(body.Product.Brands, ",")
+
+	return Product{
+		Code:  code,
+		Name:  strings.TrimSpace(body.Product.Name),
+		Brand: strings.TrimSpace(brand),
+	}, nil
+}
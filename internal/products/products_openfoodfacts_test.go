@@ -0,0 +1,72 @@
+package products
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenFoodFacts_Lookup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/product/4006381333931.json":
+			w.Write([]byte(`{"status":1,"product":{"product_name":" Highlighter ","brands":"Stabilo, Schwan"}}`))
+		case "/api/v2/product/0000000000000.json":
+			w.Write([]byte(`{"status":0,"status_verbose":"product not found"}`))
+		case "/api/v2/product/1111111111116.json":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	provider := NewOpenFoodFacts(srv.URL + "/")
+
+	t.Run("found", func(t *testing.T) {
+		got, err := provider.Lookup(t.Context(), "4006381333931")
+		if err != nil {
+			t.Fatalf("Lookup() error = %v", err)
+		}
+		want := Product{Code: "4006381333931", Name: "Highlighter", Brand: "Stabilo"}
+		if got != want {
+			t.Errorf("Lookup() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		if _, err := provider.Lookup(t.Context(), "0000000000000"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Lookup() error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		if _, err := provider.Lookup(t.Context(), "96385074"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Lookup() error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		_, err := provider.Lookup(t.Context(), "1111111111116")
+		if err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Lookup() error = %v, want a non-ErrNotFound error", err)
+		}
+	})
+}
+
+func TestOffline_Lookup(t *testing.T) {
+	provider := Offline{"96385074": {Name: "Sparkling Water"}}
+
+	got, err := provider.Lookup(t.Context(), "96385074")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if got.Name != "Sparkling Water" || got.Code != "96385074" {
+		t.Errorf("Lookup() = %+v", got)
+	}
+
+	if _, err := provider.Lookup(t.Context(), "4006381333931"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup() error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,27 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/taiidani/groceries/internal/db/models"
+	legacy "github.com/taiidani/groceries/internal/models"
+)
+
+// notifyExpiringItems tells connected clients when the set of pantry items
+// nearing their best-before date changes.
+func (s *Scheduler) notifyExpiringItems(ctx context.Context, tx *legacy.Repository) (string, error) {
+	before := time.Now().AddDate(0, 0, models.ExpiringSoonDays)
+	changed, err := tx.MarkExpiringPantryItems(ctx, before)
+	if err != nil {
+		return "", fmt.Errorf("could not mark expiring pantry items: %w", err)
+	}
+	if changed == 0 {
+		return "", nil
+	}
+
+	slog.InfoContext(ctx, "Pantry items expiring soon changed", "count", changed)
+	return sseEventExpiring, nil
+}
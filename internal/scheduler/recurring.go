@@ -0,0 +1,21 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	legacy "github.com/taiidani/groceries/internal/models"
+)
+
+// addRecurringItems places every due staple item that is not already on the
+// shopping list back onto it.
+func (s *Scheduler) addRecurringItems(ctx context.Context, tx *legacy.Repository) (string, error) {
+	added, err := tx.AddDueRecurringItems(ctx, time.Now())
+	if err != nil || added == 0 {
+		return "", err
+	}
+
+	slog.InfoContext(ctx, "Added recurring items to the list", "count", added)
+	return sseEventList, nil
+}
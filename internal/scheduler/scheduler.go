@@ -0,0 +1,124 @@
+// Package scheduler runs periodic background jobs for the groceries application,
+// such as placing recurring staple items back onto the shopping list and
+// announcing pantry items that are about to expire.
+//
+// Every replica runs a scheduler, but each run is guarded by a Postgres advisory
+// lock so that only one replica performs the jobs at a time.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/taiidani/groceries/internal/events"
+	legacy "github.com/taiidani/groceries/internal/models"
+)
+
+const (
+	// defaultInterval is how often the scheduler wakes up to look for work.
+	defaultInterval = time.Minute * 15
+
+	// leaderLockID is the Postgres advisory lock key held while jobs run.
+	leaderLockID int64 = 0x67726f63 // "groc"
+
+	// sseEventList mirrors the web server's event for list changes.
+	sseEventList = "list"
+
+	// sseEventExpiring mirrors the web server's event for pantry items nearing
+	// their best-before date.
+	sseEventExpiring = "expiring"
+)
+
+// Scheduler periodically executes background jobs.
+type Scheduler struct {
+	repo      *legacy.Repository
+	sseServer events.PubSub
+	interval  time.Duration
+}
+
+// New creates a Scheduler that works through the given repository and
+// publishes events over Redis.
+func New(repo *legacy.Repository, rds *redis.Client) *Scheduler {
+	return &Scheduler{
+		repo:      repo,
+		sseServer: events.NewRedisPubSub(rds),
+		interval:  defaultInterval,
+	}
+}
+
+// Run executes the jobs immediately and then on every interval until ctx is
+// cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.runJobs(ctx)
+
+		select {
+		case <-ctx.Done():
+			slog.Info("Scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// jobFunc is one unit of background work. It runs inside the leader's
+// transaction and returns the event, if any, that connected clients should
+// receive about its changes.
+type jobFunc func(ctx context.Context, tx *legacy.Repository) (event string, err error)
+
+func (s *Scheduler) runJobs(ctx context.Context) {
+	jobs := []struct {
+		name string
+		run  jobFunc
+	}{
+		{name: "recurring items", run: s.addRecurringItems},
+		{name: "expiring items", run: s.notifyExpiringItems},
+	}
+
+	for _, job := range jobs {
+		event := ""
+		err := s.withLeaderLock(ctx, func(ctx context.Context, tx *legacy.Repository) (err error) {
+			event, err = job.run(ctx, tx)
+			return err
+		})
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				slog.ErrorContext(ctx, "Scheduled job failed", "job", job.name, "error", err)
+			}
+			continue
+		}
+
+		// Clients are only told once the changes are committed, so that they
+		// do not reload before the changes are visible
+		if event != "" {
+			if err := s.sseServer.Publish(ctx, event, nil); err != nil {
+				slog.WarnContext(ctx, "Could not publish scheduler event", "job", job.name, "event", event, "error", err)
+			}
+		}
+	}
+}
+
+// withLeaderLock runs fn inside a transaction holding the scheduler's advisory
+// lock. If another replica already holds the lock, fn is skipped. The lock is
+// released when the transaction ends.
+func (s *Scheduler) withLeaderLock(ctx context.Context, fn func(context.Context, *legacy.Repository) error) error {
+	return s.repo.InTx(ctx, func(tx *legacy.Repository) error {
+		locked, err := tx.TryXactLock(ctx, leaderLockID)
+		if err != nil {
+			return fmt.Errorf("could not acquire leader lock: %w", err)
+		}
+		if !locked {
+			slog.DebugContext(ctx, "Another replica holds the scheduler lock")
+			return nil
+		}
+
+		return fn(ctx, tx)
+	})
+}
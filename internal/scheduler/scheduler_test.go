@@ -0,0 +1,171 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/taiidani/groceries/internal/events"
+	legacy "github.com/taiidani/groceries/internal/models"
+)
+
+func TestScheduler_RunJobs(t *testing.T) {
+	lastWeek := time.Now().AddDate(0, 0, -7)
+
+	tests := []struct {
+		name    string
+		rows    map[string][]driver.Value
+		wantLog []string
+	}{
+		{
+			name: "due items are added before clients are told",
+			rows: map[string][]driver.Value{
+				"TryAdvisoryXactLock":        {true},
+				"ListItemRecurrencesOffList": {int64(1), int64(7), int64(7), nil, "2", lastWeek},
+				"CreateListItem":             {int64(3), int64(7), "2", false, int64(1)},
+			},
+			wantLog: []string{
+				"begin", "AdvisoryXactLock", "TryAdvisoryXactLock", "ListItemRecurrencesOffList",
+				"CreateListItem", "RecordChange", "MarkItemRecurrenceAdded", "commit",
+				"publish list",
+				"begin", "AdvisoryXactLock", "TryAdvisoryXactLock", "MarkExpiringPantryItems", "commit",
+			},
+		},
+		{
+			name: "items that are not due are left alone",
+			rows: map[string][]driver.Value{
+				"TryAdvisoryXactLock":        {true},
+				"ListItemRecurrencesOffList": {int64(1), int64(7), int64(7), nil, "2", time.Now()},
+			},
+			wantLog: []string{
+				"begin", "AdvisoryXactLock", "TryAdvisoryXactLock", "ListItemRecurrencesOffList", "commit",
+				"begin", "AdvisoryXactLock", "TryAdvisoryXactLock", "MarkExpiringPantryItems", "commit",
+			},
+		},
+		{
+			name: "newly expiring pantry items are announced",
+			rows: map[string][]driver.Value{
+				"TryAdvisoryXactLock":     {true},
+				"MarkExpiringPantryItems": {int64(7)},
+			},
+			wantLog: []string{
+				"begin", "AdvisoryXactLock", "TryAdvisoryXactLock", "ListItemRecurrencesOffList", "commit",
+				"begin", "AdvisoryXactLock", "TryAdvisoryXactLock", "MarkExpiringPantryItems", "commit",
+				"publish expiring",
+			},
+		},
+		{
+			name: "jobs are skipped while another replica holds the lock",
+			rows: map[string][]driver.Value{
+				"TryAdvisoryXactLock":        {false},
+				"ListItemRecurrencesOffList": {int64(1), int64(7), int64(7), nil, "2", nil},
+			},
+			wantLog: []string{
+				"begin", "AdvisoryXactLock", "TryAdvisoryXactLock", "commit",
+				"begin", "AdvisoryXactLock", "TryAdvisoryXactLock", "commit",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &scriptedDB{rows: tt.rows}
+			s := &Scheduler{
+				repo:      legacy.NewRepository(sql.OpenDB(db)),
+				sseServer: scriptedPubSub{db},
+			}
+			db.log = nil
+
+			s.runJobs(context.Background())
+
+			if !reflect.DeepEqual(db.log, tt.wantLog) {
+				t.Errorf("runJobs() ran %q, want %q", db.log, tt.wantLog)
+			}
+		})
+	}
+}
+
+// scriptedDB is a database/sql driver that answers the generated queries by
+// name with a single scripted row, and records every query and transaction
+// boundary.
+type scriptedDB struct {
+	rows map[string][]driver.Value
+	log  []string
+}
+
+var queryName = regexp.MustCompile(`^-- name: (\w+)`)
+
+func (db *scriptedDB) run(query string) []driver.Value {
+	name := "sql"
+	if m := queryName.FindStringSubmatch(query); m != nil {
+		name = m[1]
+	}
+
+	db.log = append(db.log, name)
+	return db.rows[name]
+}
+
+func (db *scriptedDB) Connect(context.Context) (driver.Conn, error) { return scriptedConn{db}, nil }
+func (db *scriptedDB) Driver() driver.Driver                        { return nil }
+
+type scriptedConn struct{ db *scriptedDB }
+
+func (c scriptedConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c scriptedConn) Close() error                        { return nil }
+func (c scriptedConn) Begin() (driver.Tx, error) {
+	c.db.log = append(c.db.log, "begin")
+	return c, nil
+}
+
+func (c scriptedConn) Commit() error   { c.db.log = append(c.db.log, "commit"); return nil }
+func (c scriptedConn) Rollback() error { c.db.log = append(c.db.log, "rollback"); return nil }
+
+func (c scriptedConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.db.run(query)
+	return driver.RowsAffected(1), nil
+}
+
+func (c scriptedConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	return &scriptedRows{row: c.db.run(query)}, nil
+}
+
+type scriptedRows struct {
+	row  []driver.Value
+	done bool
+}
+
+func (r *scriptedRows) Columns() []string {
+	cols := make([]string, len(r.row))
+	for i := range cols {
+		cols[i] = fmt.Sprintf("column%d", i)
+	}
+	return cols
+}
+
+func (r *scriptedRows) Close() error { return nil }
+
+func (r *scriptedRows) Next(dest []driver.Value) error {
+	if r.done || r.row == nil {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.row)
+	return nil
+}
+
+// scriptedPubSub records published events in the database log, so that tests
+// can see whether they were sent before or after the commit.
+type scriptedPubSub struct{ db *scriptedDB }
+
+func (p scriptedPubSub) Subscribe(context.Context, ...string) <-chan events.Event { return nil }
+
+func (p scriptedPubSub) Publish(_ context.Context, channel string, _ fmt.Stringer) error {
+	p.db.log = append(p.db.log, "publish "+channel)
+	return nil
+}
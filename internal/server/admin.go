@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/taiidani/groceries/internal/db/models"
+	"github.com/taiidani/groceries/sdk"
 )
 
 type adminBag struct {
@@ -17,13 +18,13 @@ func (s *Server) adminHandler(w http.ResponseWriter, r *http.Request) {
 	bag := adminBag{baseBag: s.newBag(r.Context())}
 
 	var err error
-	bag.Users, err = s.db.ListUsers(r.Context())
+	bag.Users, err = s.repo.LoadUsers(r.Context())
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	bag.Groups, err = s.db.ListGroups(r.Context())
+	bag.Groups, err = s.repo.LoadGroups(r.Context())
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -40,19 +41,10 @@ func (s *Server) userUpdateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := s.db.GetUser(r.Context(), id)
-	if err != nil {
-		errorResponse(w, r, http.StatusInternalServerError, err)
-		return
-	}
-
-	user.Admin = r.FormValue("admin") == "on" || r.FormValue("admin") == "true"
-	user.Name = r.FormValue("name")
-
-	user, err = s.db.UpdateUser(r.Context(), models.UpdateUserParams{
-		ID:    id,
-		Name:  user.Name,
-		Admin: user.Admin,
+	apiClient := clientFromContext(r.Context())
+	_, err = apiClient.UpdateUser(r.Context(), int(id), sdk.UpdateUserRequest{
+		Name:  sdk.Ptr(r.FormValue("name")),
+		Admin: sdk.Ptr(r.FormValue("admin") == "on" || r.FormValue("admin") == "true"),
 	})
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
@@ -67,9 +59,10 @@ func (s *Server) userUpdateHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) userAddHandler(w http.ResponseWriter, r *http.Request) {
-	_, err := s.db.CreateUser(r.Context(), models.CreateUserParams{
+	apiClient := clientFromContext(r.Context())
+	_, err := apiClient.CreateUser(r.Context(), sdk.CreateUserRequest{
 		Name:  r.FormValue("name"),
-		Admin: r.FormValue("admin") == "on" || r.FormValue("admin") == "true",
+		Admin: sdk.Ptr(r.FormValue("admin") == "on" || r.FormValue("admin") == "true"),
 	})
 	if err != nil {
 		err = fmt.Errorf("could not add user: %w", err)
@@ -91,7 +84,8 @@ func (s *Server) userDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = s.db.DeleteUser(r.Context(), id)
+	apiClient := clientFromContext(r.Context())
+	err = apiClient.DeleteUser(r.Context(), int(id))
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -111,17 +105,9 @@ func (s *Server) groupUpdateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	group, err := s.db.GetGroup(r.Context(), id)
-	if err != nil {
-		errorResponse(w, r, http.StatusInternalServerError, err)
-		return
-	}
-
-	group.Name = r.FormValue("name")
-
-	group, err = s.db.UpdateGroup(r.Context(), models.UpdateGroupParams{
-		ID:   id,
-		Name: group.Name,
+	apiClient := clientFromContext(r.Context())
+	_, err = apiClient.UpdateGroup(r.Context(), int(id), sdk.UpdateGroupRequest{
+		Name: r.FormValue("name"),
 	})
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
@@ -136,7 +122,8 @@ func (s *Server) groupUpdateHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) groupAddHandler(w http.ResponseWriter, r *http.Request) {
-	_, err := s.db.CreateGroup(r.Context(), r.FormValue("name"))
+	apiClient := clientFromContext(r.Context())
+	_, err := apiClient.CreateGroup(r.Context(), sdk.CreateGroupRequest{Name: r.FormValue("name")})
 	if err != nil {
 		err = fmt.Errorf("could not add group: %w", err)
 		errorResponse(w, r, http.StatusInternalServerError, err)
@@ -157,7 +144,8 @@ func (s *Server) groupDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = s.db.DeleteGroup(r.Context(), id)
+	apiClient := clientFromContext(r.Context())
+	err = apiClient.DeleteGroup(r.Context(), int(id))
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
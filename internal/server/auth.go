@@ -26,7 +26,7 @@ func (s *Server) auth(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// They know the password! Load the user
-	user, err := s.db.GetUserByName(r.Context(), r.FormValue("username"))
+	user, err := s.repo.GetUserByName(r.Context(), r.FormValue("username"))
 	if err != nil {
 		errorResponse(w, r, http.StatusUnauthorized, fmt.Errorf("invalid credentials: %w", err))
 	}
@@ -4,27 +4,27 @@ import (
 	"net/http"
 	"strconv"
 
-	"github.com/taiidani/groceries/internal/client"
+	"github.com/taiidani/groceries/sdk"
 )
 
 func (s *Server) categoriesHandler(w http.ResponseWriter, r *http.Request) {
 	type data struct {
 		baseBag
 		Categories []storeWithCategories
-		Stores     []client.Store
+		Stores     []sdk.Store
 	}
 
 	bag := data{baseBag: s.newBag(r.Context())}
 
 	apiClient := clientFromContext(r.Context())
 
-	stores, err := apiClient.ListStores(r.Context())
+	stores, err := apiClient.ListStoresAll(r.Context(), nil)
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	categories, err := apiClient.ListCategories(r.Context())
+	categories, err := apiClient.ListCategoriesAll(r.Context(), nil)
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -39,9 +39,9 @@ func (s *Server) categoriesHandler(w http.ResponseWriter, r *http.Request) {
 func (s *Server) categoryHandler(w http.ResponseWriter, r *http.Request) {
 	type data struct {
 		baseBag
-		Category client.CategoryDetail
-		Items    []client.Item
-		Stores   []client.Store
+		Category sdk.GetCategoryResponse
+		Items    []sdk.Item
+		Stores   []sdk.Store
 	}
 
 	bag := data{baseBag: s.newBag(r.Context())}
@@ -62,7 +62,7 @@ func (s *Server) categoryHandler(w http.ResponseWriter, r *http.Request) {
 
 	bag.Items = bag.Category.Items
 
-	bag.Stores, err = apiClient.ListStores(r.Context())
+	bag.Stores, err = apiClient.ListStoresAll(r.Context(), nil)
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -79,19 +79,16 @@ func (s *Server) categoryAddHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	apiClient := clientFromContext(r.Context())
-	_, err = apiClient.CreateCategory(
-		r.Context(),
-		storeID,
-		r.FormValue("name"),
-		r.FormValue("description"),
-	)
+	_, err = apiClient.CreateCategory(r.Context(), sdk.CreateCategoryRequest{
+		StoreID:     storeID,
+		Name:        r.FormValue("name"),
+		Description: sdk.Ptr(r.FormValue("description")),
+	})
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	s.sseServer.Publish(r.Context(), sseEventCategory, nil)
-
 	http.Redirect(w, r, "/categories", http.StatusFound)
 }
 
@@ -109,20 +106,16 @@ func (s *Server) categoryEditHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	apiClient := clientFromContext(r.Context())
-	_, err = apiClient.UpdateCategory(
-		r.Context(),
-		id,
-		storeID,
-		r.FormValue("name"),
-		r.FormValue("description"),
-	)
+	_, err = apiClient.UpdateCategory(r.Context(), id, nil, sdk.UpdateCategoryRequest{
+		StoreID:     storeID,
+		Name:        r.FormValue("name"),
+		Description: sdk.Ptr(r.FormValue("description")),
+	})
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	s.sseServer.Publish(r.Context(), sseEventCategory, nil)
-
 	http.Redirect(w, r, "/categories", http.StatusFound)
 }
 
@@ -139,14 +132,12 @@ func (s *Server) categoryDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.sseServer.Publish(r.Context(), sseEventCategory, nil)
-
 	http.Redirect(w, r, "/categories", http.StatusFound)
 }
 
 // buildStoreHierarchy groups a flat list of categories under their parent stores,
 // producing the nested structure expected by the categories template.
-func buildStoreHierarchy(stores []client.Store, categories []client.Category) []storeWithCategories {
+func buildStoreHierarchy(stores []sdk.Store, categories []sdk.Category) []storeWithCategories {
 	ret := make([]storeWithCategories, 0, len(stores))
 
 	for _, store := range stores {
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/taiidani/groceries/sdk"
+)
+
+// exportFormatHTML is the print-optimized layout, which opens in its own tab
+// rather than downloading.
+const exportFormatHTML = "html"
+
+func (s *Server) listExportHandler(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	export, err := clientFromContext(r.Context()).ExportList(r.Context(), &sdk.ExportListParams{Format: format})
+
+	disposition := "attachment"
+	if format == exportFormatHTML {
+		disposition = "inline"
+	}
+	writeExport(w, r, disposition, export, err)
+}
+
+func (s *Server) catalogExportHandler(w http.ResponseWriter, r *http.Request) {
+	export, err := clientFromContext(r.Context()).ExportCatalog(r.Context(), &sdk.ExportCatalogParams{Format: r.URL.Query().Get("format")})
+	writeExport(w, r, "attachment", export, err)
+}
+
+// writeExport streams an export from the API to the browser, either as a
+// download or, with an inline disposition, as a page.
+func writeExport(w http.ResponseWriter, r *http.Request, disposition string, export sdk.Download, err error) {
+	if err != nil {
+		errorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+	defer export.Body.Close()
+
+	w.Header().Set("Content-Type", export.ContentType)
+	if export.Filename != "" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, export.Filename))
+	}
+
+	if _, err := io.Copy(w, export.Body); err != nil {
+		slog.Warn("Could not write export", "error", err)
+	}
+}
@@ -3,7 +3,7 @@ package server
 import (
 	"context"
 
-	"github.com/taiidani/groceries/internal/client"
+	"github.com/taiidani/groceries/sdk"
 )
 
 type storeHierarchyInput struct {
@@ -17,24 +17,24 @@ func loadStoreHierarchy(ctx context.Context, input storeHierarchyInput) ([]store
 
 	apiClient := clientFromContext(ctx)
 
-	stores, err := apiClient.ListStores(ctx)
+	stores, err := apiClient.ListStoresAll(ctx, nil)
 	if err != nil {
 		return ret, err
 	}
 
-	categories, err := apiClient.ListCategories(ctx)
+	categories, err := apiClient.ListCategoriesAll(ctx, nil)
 	if err != nil {
 		return ret, err
 	}
 
-	var items []client.Item
+	var items []sdk.Item
 	if input.OnlyListItems {
-		items, err = apiClient.ListShoppingList(ctx)
+		items, err = apiClient.ListItemsAll(ctx, &sdk.ListItemsParams{InList: sdk.Ptr(true)})
 		if err != nil {
 			return ret, err
 		}
 	} else {
-		items, err = apiClient.ListItems(ctx, nil)
+		items, err = apiClient.ListItemsAll(ctx, nil)
 		if err != nil {
 			return ret, err
 		}
@@ -48,7 +48,7 @@ func loadStoreHierarchy(ctx context.Context, input storeHierarchyInput) ([]store
 				continue
 			}
 
-			addItems := []client.Item{}
+			addItems := []sdk.Item{}
 			for _, item := range items {
 				if item.CategoryID != cat.ID {
 					continue
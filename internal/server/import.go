@@ -0,0 +1,69 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/taiidani/groceries/sdk"
+)
+
+// maxImportSize bounds pasted and uploaded lists.
+const maxImportSize = 1 << 20
+
+type listImportBag struct {
+	baseBag
+	Text    string
+	Format  string
+	Results []sdk.ImportListResponseResult
+}
+
+func (s *Server) listImportHandler(w http.ResponseWriter, r *http.Request) {
+	bag := listImportBag{baseBag: s.newBag(r.Context())}
+	renderHtml(w, http.StatusOK, "list_import.gohtml", bag)
+}
+
+func (s *Server) listImportSubmitHandler(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	if err := r.ParseMultipartForm(maxImportSize); err != nil {
+		errorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	bag := listImportBag{
+		baseBag: s.newBag(r.Context()),
+		Text:    r.FormValue("text"),
+		Format:  r.FormValue("format"),
+	}
+
+	// An uploaded file takes precedence over pasted text
+	file, _, err := r.FormFile("file")
+	if err == nil {
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		if err != nil {
+			errorResponse(w, r, http.StatusBadRequest, err)
+			return
+		}
+		bag.Text = string(content)
+	} else if !errors.Is(err, http.ErrMissingFile) {
+		errorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	req := sdk.ImportListRequest{Text: bag.Text}
+	if bag.Format != "" {
+		req.Format = sdk.Ptr(sdk.ImportListRequestFormat(bag.Format))
+	}
+
+	apiClient := clientFromContext(r.Context())
+	resp, err := apiClient.ImportList(r.Context(), req)
+	if err != nil {
+		errorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+	bag.Results = resp.Results
+
+	renderHtml(w, http.StatusOK, "list_import.gohtml", bag)
+}
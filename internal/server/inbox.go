@@ -0,0 +1,89 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/taiidani/groceries/sdk"
+)
+
+func (s *Server) inboxHandler(w http.ResponseWriter, r *http.Request) {
+	bag := struct {
+		baseBag
+		Items  []sdk.Item
+		Stores []storeWithCategories
+	}{baseBag: s.newBag(r.Context())}
+
+	apiClient := clientFromContext(r.Context())
+
+	var err error
+	bag.Items, err = apiClient.ListItemsAll(r.Context(), &sdk.ListItemsParams{
+		CategoryID: sdk.Ptr(sdk.UncategorizedCategoryID),
+	})
+	if err != nil {
+		errorResponse(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	bag.Stores, err = loadStoreHierarchy(r.Context(), storeHierarchyInput{})
+	if err != nil {
+		errorResponse(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	renderHtml(w, http.StatusOK, "inbox.gohtml", bag)
+}
+
+func (s *Server) inboxTriageHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		errorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	req := sdk.BatchItemsRequest{Action: sdk.BatchItemsRequestAction(r.FormValue("action"))}
+	for _, raw := range r.Form["ids"] {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			errorResponse(w, r, http.StatusBadRequest, err)
+			return
+		}
+		req.ItemIDs = append(req.ItemIDs, id)
+	}
+
+	var id int
+	var err error
+	switch req.Action {
+	case sdk.BatchItemsRequestActionAssign:
+		id, err = strconv.Atoi(r.FormValue("categoryID"))
+		req.CategoryID = &id
+	case sdk.BatchItemsRequestActionMerge:
+		id, err = strconv.Atoi(r.FormValue("targetID"))
+		req.TargetID = &id
+	}
+	if err != nil {
+		errorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	apiClient := clientFromContext(r.Context())
+	resp, err := apiClient.BatchItems(r.Context(), req)
+	if err != nil {
+		errorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var failures error
+	for _, result := range resp.Results {
+		if !result.OK && result.Error != nil {
+			failures = errors.Join(failures, fmt.Errorf("item %d: %s", result.ItemID, *result.Error))
+		}
+	}
+	if failures != nil {
+		errorResponse(w, r, http.StatusBadRequest, failures)
+		return
+	}
+
+	http.Redirect(w, r, "/inbox", http.StatusFound)
+}
@@ -4,7 +4,7 @@ import (
 	"net/http"
 	"sort"
 
-	"github.com/taiidani/groceries/internal/client"
+	"github.com/taiidani/groceries/sdk"
 )
 
 func (s *Server) indexHandler(w http.ResponseWriter, r *http.Request) {
@@ -22,13 +22,13 @@ func (s *Server) indexHandler(w http.ResponseWriter, r *http.Request) {
 
 	apiClient := clientFromContext(r.Context())
 
-	items, err := apiClient.ListItems(r.Context(), nil)
+	items, err := apiClient.ListItemsAll(r.Context(), nil)
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	categories, err := apiClient.ListCategories(r.Context())
+	categories, err := apiClient.ListCategoriesAll(r.Context(), nil)
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -77,7 +77,7 @@ func (s *Server) indexListHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	apiClient := clientFromContext(r.Context())
-	listItems, err := apiClient.ListShoppingList(r.Context())
+	listItems, err := apiClient.ListItemsAll(r.Context(), &sdk.ListItemsParams{InList: sdk.Ptr(true)})
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -103,20 +103,20 @@ func (s *Server) indexCartHandler(w http.ResponseWriter, r *http.Request) {
 
 	apiClient := clientFromContext(r.Context())
 
-	categories, err := apiClient.ListCategories(r.Context())
+	categories, err := apiClient.ListCategoriesAll(r.Context(), nil)
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	listItems, err := apiClient.ListShoppingList(r.Context())
+	listItems, err := apiClient.ListItemsAll(r.Context(), &sdk.ListItemsParams{InList: sdk.Ptr(true)})
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
 	for _, cat := range categories {
-		var done []client.Item
+		var done []sdk.Item
 		for _, item := range listItems {
 			if item.CategoryID == cat.ID && item.List != nil && item.List.Done {
 				done = append(done, item)
@@ -1,16 +1,20 @@
 package server
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
-	"github.com/taiidani/groceries/internal/client"
+	"github.com/taiidani/groceries/sdk"
 )
 
 type itemsBag struct {
 	baseBag
 	Stores []storeWithCategories
-	Item   client.Item
+	Item   sdk.Item
 }
 
 func (s *Server) itemsHandler(w http.ResponseWriter, r *http.Request) {
@@ -30,8 +34,12 @@ func (s *Server) itemHandler(w http.ResponseWriter, r *http.Request) {
 	bag := struct {
 		baseBag
 		Redirect   string
-		Categories []client.Category
-		Item       client.Item
+		Categories []sdk.Category
+		Item       sdk.Item
+		Aliases    string
+		Recurrence recurrenceForm
+		Weekdays   []time.Weekday
+		Stores     []storeWithCategories
 	}{baseBag: s.newBag(r.Context())}
 
 	bag.Redirect = r.URL.Query().Get("redirect")
@@ -44,7 +52,7 @@ func (s *Server) itemHandler(w http.ResponseWriter, r *http.Request) {
 
 	apiClient := clientFromContext(r.Context())
 
-	categories, err := apiClient.ListCategories(r.Context())
+	categories, err := apiClient.ListCategoriesAll(r.Context(), nil)
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -56,6 +64,27 @@ func (s *Server) itemHandler(w http.ResponseWriter, r *http.Request) {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
 	}
+	bag.Aliases = strings.Join(bag.Item.Aliases, ", ")
+
+	recurrence, err := apiClient.GetItemRecurrence(r.Context(), id)
+	if err == nil {
+		bag.Recurrence = newRecurrenceForm(&recurrence)
+	} else if sdk.IsNotFound(err) {
+		bag.Recurrence = newRecurrenceForm(nil)
+	} else {
+		errorResponse(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		bag.Weekdays = append(bag.Weekdays, day)
+	}
+
+	bag.Stores, err = loadStoreHierarchy(r.Context(), storeHierarchyInput{ExcludeEmptyGroupings: true})
+	if err != nil {
+		errorResponse(w, r, http.StatusInternalServerError, err)
+		return
+	}
 
 	renderHtml(w, http.StatusOK, "item_edit.gohtml", bag)
 }
@@ -68,7 +97,10 @@ func (s *Server) itemAddHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	apiClient := clientFromContext(r.Context())
-	_, err = apiClient.CreateItem(r.Context(), categoryID, r.FormValue("name"))
+	_, err = apiClient.CreateItem(r.Context(), sdk.CreateItemRequest{
+		CategoryID: categoryID,
+		Name:       r.FormValue("name"),
+	})
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -98,7 +130,17 @@ func (s *Server) itemEditHandler(w http.ResponseWriter, r *http.Request) {
 
 	apiClient := clientFromContext(r.Context())
 
-	_, err = apiClient.UpdateItem(r.Context(), id, categoryID, r.FormValue("name"))
+	// Aliases are entered as a comma separated list
+	var aliases []string
+	if r.Form.Has("aliases") {
+		aliases = strings.Split(r.FormValue("aliases"), ",")
+	}
+
+	_, err = apiClient.UpdateItem(r.Context(), id, nil, sdk.UpdateItemRequest{
+		CategoryID: categoryID,
+		Name:       r.FormValue("name"),
+		Aliases:    aliases,
+	})
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -111,12 +153,27 @@ func (s *Server) itemEditHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if existing.List != nil {
-		if err := apiClient.UpdateListItem(r.Context(), id, r.FormValue("quantity")); err != nil {
+		_, err := apiClient.UpdateListItem(r.Context(), id, nil, sdk.UpdateListItemRequest{
+			Quantity: sdk.Ptr(r.FormValue("quantity")),
+		})
+		if err != nil {
 			errorResponse(w, r, http.StatusInternalServerError, err)
 			return
 		}
 	}
 
+	if err := s.saveItemRecurrence(r, id); err != nil {
+		errorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if barcode := r.FormValue("barcode"); barcode != "" {
+		if _, err := apiClient.AddItemBarcode(r.Context(), id, sdk.AddBarcodeRequest{Code: barcode}); err != nil {
+			errorResponse(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
 	s.sseServer.Publish(r.Context(), sseEventList, nil)
 
 	redirect := r.FormValue("redirect")
@@ -147,3 +204,106 @@ func (s *Server) itemDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	http.Redirect(w, r, redirect, http.StatusFound)
 }
+
+func (s *Server) itemMergeHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	targetID, err := strconv.Atoi(r.FormValue("targetID"))
+	if err != nil {
+		errorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	apiClient := clientFromContext(r.Context())
+	if _, err := apiClient.MergeItem(r.Context(), id, sdk.MergeItemRequest{TargetID: targetID}); err != nil {
+		errorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	s.sseServer.Publish(r.Context(), sseEventList, nil)
+
+	http.Redirect(w, r, fmt.Sprintf("/item/%d?redirect=%s", targetID, url.QueryEscape(r.FormValue("redirect"))), http.StatusFound)
+}
+
+func (s *Server) itemBarcodeDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.FormValue("id"))
+	if err != nil {
+		errorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	apiClient := clientFromContext(r.Context())
+	if err := apiClient.DeleteItemBarcode(r.Context(), id, r.FormValue("code")); err != nil {
+		errorResponse(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/item/%d?redirect=%s", id, url.QueryEscape(r.FormValue("redirect"))), http.StatusFound)
+}
+
+// recurrenceForm flattens an sdk.ItemRecurrence into the values displayed by the
+// item edit form.
+type recurrenceForm struct {
+	Type         string
+	IntervalDays int
+	Weekday      time.Weekday
+	Quantity     string
+}
+
+func newRecurrenceForm(rec *sdk.ItemRecurrence) recurrenceForm {
+	ret := recurrenceForm{Type: "none", IntervalDays: 7}
+	if rec == nil {
+		return ret
+	}
+
+	ret.Quantity = rec.Quantity
+	switch {
+	case rec.IntervalDays != nil:
+		ret.Type = "interval"
+		ret.IntervalDays = *rec.IntervalDays
+	case rec.Weekday != nil:
+		ret.Type = "weekday"
+		ret.Weekday = time.Weekday(*rec.Weekday)
+	}
+
+	return ret
+}
+
+// saveItemRecurrence applies the recurrence fields of the item edit form.
+// The "recurrence" field selects the rule type: "none", "interval" or "weekday".
+func (s *Server) saveItemRecurrence(r *http.Request, id int) error {
+	apiClient := clientFromContext(r.Context())
+
+	req := sdk.SetItemRecurrenceRequest{Quantity: sdk.Ptr(r.FormValue("recurrenceQuantity"))}
+	switch r.FormValue("recurrence") {
+	case "interval":
+		days, err := strconv.Atoi(r.FormValue("intervalDays"))
+		if err != nil {
+			return fmt.Errorf("invalid number of days: %w", err)
+		}
+		req.IntervalDays = &days
+	case "weekday":
+		day, err := strconv.Atoi(r.FormValue("weekday"))
+		if err != nil {
+			return fmt.Errorf("invalid weekday: %w", err)
+		}
+		req.Weekday = &day
+	case "none":
+		err := apiClient.DeleteItemRecurrence(r.Context(), id)
+		if sdk.IsNotFound(err) {
+			// The item did not recur
+			return nil
+		}
+		return err
+	default:
+		// The form did not include recurrence fields
+		return nil
+	}
+
+	_, err := apiClient.SetItemRecurrence(r.Context(), id, req)
+	return err
+}
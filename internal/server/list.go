@@ -6,47 +6,50 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/taiidani/groceries/internal/models"
+	"github.com/taiidani/groceries/sdk"
 )
 
-func (s *Server) listAddHandler(w http.ResponseWriter, r *http.Request) {
-	var item models.Item
-	var err error
-	switch {
-	case r.FormValue("name") != "":
-		item, err = models.GetItemByName(r.Context(), r.FormValue("name"))
-		if errors.Is(err, sql.ErrNoRows) {
-			// The item doesn't exist yet. That's okay!
-			// Let's create a new one
-			item = models.Item{
-				Name:       r.FormValue("name"),
-				CategoryID: models.UncategorizedCategoryID,
-			}
-			err = models.AddItem(r.Context(), item)
-			if err != nil {
-				errorResponse(w, r, http.StatusInternalServerError, fmt.Errorf("unable to add item: %w", err))
-				return
-			}
+const (
+	// minSuggestLength matches the minimum length of the add form's name field.
+	minSuggestLength = 3
+	maxSuggestions   = 5
+)
 
-			item, err = models.GetItemByName(r.Context(), r.FormValue("name"))
-		}
-	case r.PathValue("id") != "":
-		id, convErr := strconv.Atoi(r.PathValue("id"))
-		if convErr != nil {
-			errorResponse(w, r, http.StatusBadRequest, convErr)
+func (s *Server) listAddHandler(w http.ResponseWriter, r *http.Request) {
+	var id int
+	if r.PathValue("id") != "" {
+		var err error
+		id, err = strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			errorResponse(w, r, http.StatusBadRequest, err)
 			return
 		}
-
-		item, err = models.GetItem(r.Context(), id)
 	}
 
-	if err != nil {
-		errorResponse(w, r, http.StatusInternalServerError, err)
-		return
-	}
+	// A new item is only kept if it made it onto the list
+	var item models.Item
+	err := s.repo.InTx(r.Context(), func(tx *models.Repository) error {
+		var err error
+		switch {
+		case r.FormValue("name") != "":
+			item, _, err = tx.FindItemByName(r.Context(), r.FormValue("name"))
+			if errors.Is(err, sql.ErrNoRows) {
+				// The item doesn't exist yet. That's okay!
+				// Let's create a new one, filed wherever similar items live
+				item, _, err = tx.AddItemByName(r.Context(), r.FormValue("name"))
+			}
+		case id != 0:
+			item, err = tx.GetItem(r.Context(), id)
+		}
+		if err != nil {
+			return err
+		}
 
-	err = models.ListAddItem(r.Context(), item.ID, r.FormValue("quantity"))
+		return tx.ListAddItem(r.Context(), item.ID, r.FormValue("quantity"))
+	})
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -62,8 +65,34 @@ func (s *Server) listAddHandler(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, redirect, http.StatusFound)
 }
 
+func (s *Server) listSuggestHandler(w http.ResponseWriter, r *http.Request) {
+	bag := struct {
+		Suggestions []sdk.ItemMatch
+	}{}
+
+	if name := strings.TrimSpace(r.FormValue("name")); len(name) >= minSuggestLength {
+		var err error
+		bag.Suggestions, err = clientFromContext(r.Context()).SearchItems(r.Context(), &sdk.SearchItemsParams{
+			Query: name,
+			Limit: sdk.Ptr(maxSuggestions),
+		})
+		if err != nil {
+			errorResponse(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	renderHtml(w, http.StatusOK, "list_suggest.gohtml", bag)
+}
+
 func (s *Server) listDeleteHandler(w http.ResponseWriter, r *http.Request) {
-	err := models.DeleteFromList(r.Context(), r.PathValue("id"))
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		errorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	err = s.repo.DeleteFromList(r.Context(), id)
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -80,7 +109,13 @@ func (s *Server) listDeleteHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) listDoneHandler(w http.ResponseWriter, r *http.Request) {
-	err := models.MarkItemDone(r.Context(), r.FormValue("id"), true)
+	id, err := strconv.Atoi(r.FormValue("id"))
+	if err != nil {
+		errorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	err = s.repo.MarkItemDone(r.Context(), id, true)
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -94,7 +129,13 @@ func (s *Server) listDoneHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) listUnDoneHandler(w http.ResponseWriter, r *http.Request) {
-	err := models.MarkItemDone(r.Context(), r.FormValue("id"), false)
+	id, err := strconv.Atoi(r.FormValue("id"))
+	if err != nil {
+		errorResponse(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	err = s.repo.MarkItemDone(r.Context(), id, false)
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -108,7 +149,7 @@ func (s *Server) listUnDoneHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) finishHandler(w http.ResponseWriter, r *http.Request) {
-	err := models.FinishShopping(r.Context())
+	err := s.repo.FinishShopping(r.Context())
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
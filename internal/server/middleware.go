@@ -7,8 +7,8 @@ import (
 	"net/http"
 
 	"github.com/taiidani/groceries/internal/authz"
-	"github.com/taiidani/groceries/internal/client"
 	"github.com/taiidani/groceries/internal/db/models"
+	"github.com/taiidani/groceries/sdk"
 )
 
 type contextKey string
@@ -53,7 +53,7 @@ func (s *Server) sessionMiddleware(next http.Handler) http.Handler {
 
 		// Got a session! Load the user
 		ctx := context.WithValue(r.Context(), sessionKey, sess)
-		user, err := s.db.GetUser(r.Context(), sess.UserID)
+		user, err := s.repo.GetUser(r.Context(), sess.UserID)
 		if err != nil {
 			slog.Warn("Failed to retrieve user", "error", err)
 			http.Redirect(w, r, "/login", http.StatusTemporaryRedirect)
@@ -63,13 +63,15 @@ func (s *Server) sessionMiddleware(next http.Handler) http.Handler {
 		ctx = context.WithValue(ctx, userKey, &user)
 
 		// Attach an API client scoped to this user's token so handlers can
-		// call the API on their behalf. If the token is missing the session
-		// pre-dates the API token field - treat it as expired and re-login.
+		// call the API on their behalf. Calls are dispatched in-process, so
+		// the public URL only sets the Host header. If the token is missing
+		// the session pre-dates the API token field - treat it as expired and
+		// re-login.
 		if sess.APIToken == "" {
 			http.Redirect(w, r, "/login", http.StatusTemporaryRedirect)
 			return
 		}
-		apiClient := client.New(s.publicURL, sess.APIToken)
+		apiClient := sdk.New(s.publicURL, sess.APIToken, sdk.WithTransport(s.apiTransport))
 		ctx = context.WithValue(ctx, clientKey, apiClient)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -78,8 +80,8 @@ func (s *Server) sessionMiddleware(next http.Handler) http.Handler {
 
 // clientFromContext retrieves the API client from the request context.
 // Returns nil if no client is present (e.g. session has no API token yet).
-func clientFromContext(ctx context.Context) *client.Client {
-	c, _ := ctx.Value(clientKey).(*client.Client)
+func clientFromContext(ctx context.Context) *sdk.Client {
+	c, _ := ctx.Value(clientKey).(*sdk.Client)
 	return c
 }
 
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/taiidani/groceries/internal/db/models"
+)
+
+// expiringItem is a pantry item shown in the expiration banner.
+type expiringItem struct {
+	Name       string
+	BestBefore time.Time
+	Expired    bool
+}
+
+// loadExpiring returns the pantry items nearing their best-before date. The
+// banner is informational, so failures are logged rather than surfaced.
+func (s *Server) loadExpiring(ctx context.Context) []expiringItem {
+	now := time.Now()
+	rows, err := s.repo.ExpiringPantryItems(ctx, now.AddDate(0, 0, models.ExpiringSoonDays))
+	if err != nil {
+		slog.WarnContext(ctx, "Could not load expiring pantry items", "error", err)
+		return nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	ret := make([]expiringItem, 0, len(rows))
+	for _, row := range rows {
+		bestBefore := row.PantryItem.BestBefore.Time
+		ret = append(ret, expiringItem{
+			Name:       row.ItemName,
+			BestBefore: bestBefore,
+			Expired:    bestBefore.Before(today),
+		})
+	}
+
+	return ret
+}
+
+func (s *Server) expiringHandler(w http.ResponseWriter, r *http.Request) {
+	renderHtml(w, http.StatusOK, "expiring_banner.gohtml", s.newBag(r.Context()))
+}
@@ -5,7 +5,6 @@ package server
 
 import (
 	"context"
-	"database/sql"
 	"embed"
 	"fmt"
 	"html/template"
@@ -21,15 +20,21 @@ import (
 	"github.com/taiidani/groceries/internal/cache"
 	"github.com/taiidani/groceries/internal/db/models"
 	"github.com/taiidani/groceries/internal/events"
+	legacy "github.com/taiidani/groceries/internal/models"
+	"github.com/taiidani/groceries/sdk"
 )
 
 type Server struct {
 	ctx       context.Context
-	db        *models.Queries
+	repo      *legacy.Repository
 	cache     cache.Cache
 	publicURL string
 	port      string
 	sseServer events.PubSub
+
+	// apiTransport serves the web server's API calls from the shared mux
+	// without a network round-trip.
+	apiTransport http.RoundTripper
 	*http.Server
 }
 
@@ -39,7 +44,7 @@ var templates embed.FS
 // DevMode can be toggled to pull rendered files from the filesystem or the embedded FS.
 var DevMode = os.Getenv("DEV") == "true"
 
-func NewServer(ctx context.Context, conn *sql.DB, rds *redis.Client, port string, mux *http.ServeMux) *Server {
+func NewServer(ctx context.Context, repo *legacy.Repository, rds *redis.Client, port string, mux *http.ServeMux) *Server {
 
 	publicURL := os.Getenv("PUBLIC_URL")
 	if publicURL == "" {
@@ -51,12 +56,13 @@ func NewServer(ctx context.Context, conn *sql.DB, rds *redis.Client, port string
 			Addr:    fmt.Sprintf(":%s", port),
 			Handler: mux,
 		},
-		ctx:       ctx,
-		db:        models.New(conn),
-		publicURL: publicURL,
-		port:      port,
-		cache:     cache.NewRedisCache(rds),
-		sseServer: events.NewRedisPubSub(rds),
+		ctx:          ctx,
+		repo:         repo,
+		publicURL:    publicURL,
+		port:         port,
+		cache:        cache.NewRedisCache(rds),
+		sseServer:    events.NewRedisPubSub(rds),
+		apiTransport: sdk.NewHandlerTransport(mux),
 	}
 	srv.addRoutes(mux)
 
@@ -84,9 +90,18 @@ func (s *Server) addRoutes(mux *http.ServeMux) {
 	mux.Handle("GET /item/{id}", sentryHandler.Handle(s.sessionMiddleware(s.redirectMiddleware(http.HandlerFunc(s.itemHandler)))))
 	mux.Handle("POST /item", sentryHandler.Handle(s.sessionMiddleware(s.redirectMiddleware(http.HandlerFunc(s.itemEditHandler)))))
 	mux.Handle("POST /item/add", sentryHandler.Handle(s.sessionMiddleware(s.redirectMiddleware(http.HandlerFunc(s.itemAddHandler)))))
+	mux.Handle("POST /item/barcode/delete", sentryHandler.Handle(s.sessionMiddleware(s.redirectMiddleware(http.HandlerFunc(s.itemBarcodeDeleteHandler)))))
+	mux.Handle("POST /item/merge/{id}", sentryHandler.Handle(s.sessionMiddleware(s.redirectMiddleware(http.HandlerFunc(s.itemMergeHandler)))))
 	mux.Handle("POST /item/delete/{id}", sentryHandler.Handle(s.sessionMiddleware(s.redirectMiddleware(http.HandlerFunc(s.itemDeleteHandler)))))
 
+	mux.Handle("GET /inbox", sentryHandler.Handle(s.sessionMiddleware(http.HandlerFunc(s.inboxHandler))))
+	mux.Handle("POST /inbox", sentryHandler.Handle(s.sessionMiddleware(http.HandlerFunc(s.inboxTriageHandler))))
+
 	mux.Handle("GET /list", sentryHandler.Handle(s.sessionMiddleware(s.redirectMiddleware(http.HandlerFunc(s.indexListHandler)))))
+	mux.Handle("GET /list/suggest", sentryHandler.Handle(s.sessionMiddleware(http.HandlerFunc(s.listSuggestHandler))))
+	mux.Handle("GET /list/export", sentryHandler.Handle(s.sessionMiddleware(http.HandlerFunc(s.listExportHandler))))
+	mux.Handle("GET /list/import", sentryHandler.Handle(s.sessionMiddleware(http.HandlerFunc(s.listImportHandler))))
+	mux.Handle("POST /list/import", sentryHandler.Handle(s.sessionMiddleware(http.HandlerFunc(s.listImportSubmitHandler))))
 	mux.Handle("POST /list/add", sentryHandler.Handle(s.sessionMiddleware(s.redirectMiddleware(http.HandlerFunc(s.listAddHandler)))))
 	mux.Handle("POST /list/add/{id}", sentryHandler.Handle(s.sessionMiddleware(s.redirectMiddleware(http.HandlerFunc(s.listAddHandler)))))
 	mux.Handle("POST /list/done", sentryHandler.Handle(s.sessionMiddleware(s.redirectMiddleware(http.HandlerFunc(s.listDoneHandler)))))
@@ -96,6 +111,8 @@ func (s *Server) addRoutes(mux *http.ServeMux) {
 
 	mux.Handle("GET /cart", sentryHandler.Handle(s.sessionMiddleware(http.HandlerFunc(s.indexCartHandler))))
 
+	mux.Handle("GET /catalog/export", sentryHandler.Handle(s.sessionMiddleware(http.HandlerFunc(s.catalogExportHandler))))
+
 	mux.Handle("GET /categories", sentryHandler.Handle(s.sessionMiddleware(s.redirectMiddleware(http.HandlerFunc(s.categoriesHandler)))))
 	mux.Handle("GET /category/{id}", sentryHandler.Handle(s.sessionMiddleware(s.redirectMiddleware(http.HandlerFunc(s.categoryHandler)))))
 	mux.Handle("POST /category", sentryHandler.Handle(s.sessionMiddleware(s.redirectMiddleware(http.HandlerFunc(s.categoryEditHandler)))))
@@ -108,6 +125,8 @@ func (s *Server) addRoutes(mux *http.ServeMux) {
 	mux.Handle("POST /store/add", sentryHandler.Handle(s.sessionMiddleware(s.redirectMiddleware(http.HandlerFunc(s.storeAddHandler)))))
 	mux.Handle("POST /store/delete", sentryHandler.Handle(s.sessionMiddleware(s.redirectMiddleware(http.HandlerFunc(s.storeDeleteHandler)))))
 
+	mux.Handle("GET /pantry/expiring", sentryHandler.Handle(s.sessionMiddleware(http.HandlerFunc(s.expiringHandler))))
+
 	mux.Handle("GET /sse", sentryHandler.Handle(s.sessionMiddleware(http.HandlerFunc(s.sseHandler))))
 
 	mux.Handle("/assets/", sentryHandler.Handle(http.HandlerFunc(s.assetsHandler)))
@@ -147,6 +166,7 @@ type baseBag struct {
 	Redirect string
 	Session  *authz.Session
 	User     *models.User
+	Expiring []expiringItem
 }
 
 func (s *Server) newBag(ctx context.Context) baseBag {
@@ -162,6 +182,7 @@ func (s *Server) newBag(ctx context.Context) baseBag {
 
 	if user, ok := ctx.Value(userKey).(*models.User); ok {
 		ret.User = user
+		ret.Expiring = s.loadExpiring(ctx)
 	}
 
 	return ret
@@ -17,6 +17,10 @@ const (
 
 	// sseEventCategory is triggered when a category is added or removed
 	sseEventCategory = "category"
+
+	// sseEventExpiring is triggered when the set of pantry items nearing their
+	// best-before date changes
+	sseEventExpiring = "expiring"
 )
 
 func (s *Server) sseHandler(w http.ResponseWriter, r *http.Request) {
@@ -27,6 +31,7 @@ func (s *Server) sseHandler(w http.ResponseWriter, r *http.Request) {
 		sseEventCart,
 		sseEventList,
 		sseEventCategory,
+		sseEventExpiring,
 	)
 
 	ping := time.NewTicker(time.Second * 2)
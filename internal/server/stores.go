@@ -3,8 +3,8 @@ package server
 import (
 	"net/http"
 
-	"github.com/taiidani/groceries/internal/client"
 	"github.com/taiidani/groceries/internal/db/models"
+	"github.com/taiidani/groceries/sdk"
 )
 
 func (s *Server) storesHandler(w http.ResponseWriter, r *http.Request) {
@@ -16,7 +16,7 @@ func (s *Server) storesHandler(w http.ResponseWriter, r *http.Request) {
 
 	bag := data{baseBag: s.newBag(r.Context())}
 
-	stores, err := s.db.ListStores(r.Context())
+	stores, err := s.repo.LoadStores(r.Context())
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -42,13 +42,13 @@ func (s *Server) storeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	store, err := s.db.GetStore(r.Context(), id)
+	store, err := s.repo.GetStore(r.Context(), id)
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	categories, err := s.db.ListCategoriesForStore(r.Context(), store.ID)
+	categories, err := s.repo.StoreCategories(r.Context(), store.ID)
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -62,7 +62,7 @@ func (s *Server) storeHandler(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) storeAddHandler(w http.ResponseWriter, r *http.Request) {
 	apiClient := clientFromContext(r.Context())
-	_, err := apiClient.CreateStore(r.Context(), r.FormValue("name"))
+	_, err := apiClient.CreateStore(r.Context(), sdk.CreateStoreRequest{Name: r.FormValue("name")})
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -78,10 +78,8 @@ func (s *Server) storeEditHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = s.db.UpdateStore(r.Context(), models.UpdateStoreParams{
-		ID:   id,
-		Name: r.FormValue("name"),
-	})
+	apiClient := clientFromContext(r.Context())
+	_, err = apiClient.UpdateStore(r.Context(), int(id), sdk.UpdateStoreRequest{Name: r.FormValue("name")})
 	if err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
@@ -97,7 +95,8 @@ func (s *Server) storeDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.db.DeleteStore(r.Context(), id); err != nil {
+	apiClient := clientFromContext(r.Context())
+	if err := apiClient.DeleteStore(r.Context(), int(id)); err != nil {
 		errorResponse(w, r, http.StatusInternalServerError, err)
 		return
 	}
@@ -106,11 +105,11 @@ func (s *Server) storeDeleteHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 type storeWithCategories struct {
-	client.Store
+	sdk.Store
 	Categories []categoryWithItems
 }
 
 type categoryWithItems struct {
-	client.Category
-	Items []client.Item
+	sdk.Category
+	Items []sdk.Item
 }
@@ -1,10 +1,12 @@
 // Package main provides the entry point for the groceries application.
 // It initializes logging, database connections, and the HTTP server with graceful shutdown handling.
+// Any other subcommand, such as "groceries migrate up", is handed to the cli package.
 package main
 
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
@@ -20,8 +22,11 @@ import (
 	"github.com/go-redis/redis/v8"
 	"github.com/taiidani/groceries/internal/api"
 	"github.com/taiidani/groceries/internal/cache"
+	"github.com/taiidani/groceries/internal/cli"
 	"github.com/taiidani/groceries/internal/db"
-	"github.com/taiidani/groceries/internal/models"
+	legacy "github.com/taiidani/groceries/internal/models"
+	"github.com/taiidani/groceries/internal/products"
+	"github.com/taiidani/groceries/internal/scheduler"
 	"github.com/taiidani/groceries/internal/server"
 )
 
@@ -30,6 +35,13 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
 	defer cancel()
 
+	// Run subcommands other than the server
+	if len(os.Args) > 1 && os.Args[1] != "serve" {
+		initLogging(ctx)
+		runCommand(ctx, os.Args[1:])
+		return
+	}
+
 	// Set up Sentry
 	err := sentry.Init(sentry.ClientOptions{
 		SampleRate:       1.0,
@@ -49,12 +61,6 @@ func main() {
 	rds := cache.NewClient(ctx)
 
 	// Set up the relational database
-	err = models.InitDB(ctx)
-	if err != nil {
-		slog.ErrorContext(ctx, "could not connect to database", "err", err)
-		os.Exit(2)
-	}
-
 	conn, err := db.New(ctx, os.Getenv("DATABASE_URL"))
 	if err != nil {
 		slog.ErrorContext(ctx, "could not connect to database", "err", err)
@@ -80,6 +86,16 @@ func main() {
 	slog.Info("Shutdown successful")
 }
 
+func runCommand(ctx context.Context, args []string) {
+	err := cli.Run(ctx, args, os.Stdout, os.Stderr)
+	if errors.Is(err, cli.ErrUsage) {
+		os.Exit(2)
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "groceries %s: %s\n", args[0], err)
+		os.Exit(1)
+	}
+}
+
 func initLogging(ctx context.Context) {
 	var logger *slog.Logger
 
@@ -119,11 +135,18 @@ func initServer(ctx context.Context, conn *sql.DB, rds *redis.Client) error {
 		return fmt.Errorf("required PORT environment variable not present")
 	}
 
+	// Every component reads and writes through the one repository, which
+	// shares the connection pool between them.
+	repo := legacy.NewRepository(conn)
+
 	// The web server owns the mux. The API server registers its routes onto
-	// the same mux so both share a single listener and connection pool.
+	// the same mux so both share a single listener.
 	mux := http.NewServeMux()
-	api.NewServer(ctx, conn, rds, mux)
-	srv := server.NewServer(ctx, conn, rds, port, mux)
+	api.NewServer(ctx, repo, rds, products.NewFromEnv(), mux)
+	srv := server.NewServer(ctx, repo, rds, port, mux)
+
+	// Background jobs share the server's lifetime and stop with the same context.
+	go scheduler.New(repo, rds).Run(ctx)
 
 	go func() {
 		slog.Info("Server starting", "port", port)
@@ -20,21 +20,7 @@ export async function verifyItems(items, settings) {
     throw new Error("Not connected. Open Settings → Aisle4 and click Connect.");
   }
 
-  const response = await requestUrl({
-    url: `${settings.apiBaseUrl}/api/v1/items`,
-    method: "GET",
-    headers: {
-      Authorization: `Bearer ${settings.token}`,
-    },
-    throw: false,
-  });
-
-  if (response.status !== 200) {
-    const body = response.json || {};
-    throw new Error(body.error || `Server returned ${response.status}`);
-  }
-
-  const catalog = response.json; // array of { id, name, list, ... }
+  const catalog = await fetchCatalog(settings); // array of { id, name, list, ... }
 
   return items.map((item) => {
     const nameLower = item.name.trim().toLowerCase();
@@ -57,6 +43,38 @@ export async function verifyItems(items, settings) {
   });
 }
 
+// Reads every page of the item catalog, following each page's cursor until
+// the server reports there are no more.
+async function fetchCatalog(settings) {
+  const catalog = [];
+  let cursor = "";
+
+  do {
+    const query = new URLSearchParams({ limit: "500" });
+    if (cursor) query.set("cursor", cursor);
+
+    const response = await requestUrl({
+      url: `${settings.apiBaseUrl}/api/v1/items?${query}`,
+      method: "GET",
+      headers: {
+        Authorization: `Bearer ${settings.token}`,
+      },
+      throw: false,
+    });
+
+    if (response.status !== 200) {
+      const body = response.json || {};
+      throw new Error(body.error || `Server returned ${response.status}`);
+    }
+
+    const page = response.json; // { data: [...], meta: { has_more, next_cursor } }
+    catalog.push(...page.data);
+    cursor = page.meta.has_more ? page.meta.next_cursor : "";
+  } while (cursor);
+
+  return catalog;
+}
+
 // Joins two free-form quantity strings with " + ". If one side is blank the
 // other is returned as-is, avoiding a spurious " + " when a quantity is empty.
 function appendQuantities(existing, addition) {
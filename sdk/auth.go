@@ -0,0 +1,13 @@
+package sdk
+
+import "context"
+
+// Login exchanges a username and password for an API token. It does not need
+// an existing token, so it is a function rather than a method on Client. The
+// options configure the client used to log in.
+func Login(ctx context.Context, baseURL, username, password string, opts ...Option) (LoginResponse, error) {
+	return New(baseURL, "", opts...).AuthLogin(ctx, LoginRequest{
+		Username: username,
+		Password: password,
+	})
+}
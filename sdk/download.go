@@ -0,0 +1,12 @@
+package sdk
+
+import "io"
+
+// Download is a response handed over as a file rather than decoded, such as
+// an export or backup. The caller must close Body.
+type Download struct {
+	Body        io.ReadCloser
+	ContentType string
+	// Filename is the name the server suggested for the file, if any.
+	Filename string
+}
@@ -0,0 +1,90 @@
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxErrorBody is the most of an error response that is read into Error.
+const maxErrorBody = 64 << 10
+
+// Error is an error response from the API.
+type Error struct {
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+	// Code identifies the problem, or is empty when the response did not
+	// describe one.
+	Code ProblemCode
+	// Message is the message the API gave, or the status text when the
+	// response did not include one.
+	Message string
+	// Fields lists the fields at fault when Code is
+	// ProblemCodeValidationFailed.
+	Fields []FieldProblem
+	// Body is the raw response body, for responses that carry more than a
+	// message, such as the current state sent with 412 Precondition Failed.
+	Body []byte
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("sdk: %d %s", e.StatusCode, e.Message)
+}
+
+// newError reads an error response. It does not close the body.
+func newError(resp *http.Response) *Error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	ret := &Error{StatusCode: resp.StatusCode, Body: body}
+
+	var decoded Problem
+	if err := json.Unmarshal(body, &decoded); err == nil {
+		ret.Code, ret.Fields = decoded.Code, decoded.Errors
+		ret.Message = decoded.Detail
+		if ret.Message == "" {
+			ret.Message = decoded.Error
+		}
+	}
+	if ret.Message == "" {
+		ret.Message = http.StatusText(resp.StatusCode)
+	}
+	return ret
+}
+
+// StatusCode returns the HTTP status of err if it is or wraps an *Error, and
+// 0 otherwise.
+func StatusCode(err error) int {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.StatusCode
+	}
+	return 0
+}
+
+// Code returns the problem code of err if it is or wraps an *Error, and ""
+// otherwise.
+func Code(err error) ProblemCode {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+// IsNotFound reports whether err is an API error for a missing resource.
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+// IsConflict reports whether err is an API error for a request that
+// conflicts with the current state, such as a duplicate name.
+func IsConflict(err error) bool {
+	return StatusCode(err) == http.StatusConflict
+}
+
+// IsPreconditionFailed reports whether err is an API error for an update
+// whose If-Match no longer matches the stored version.
+func IsPreconditionFailed(err error) bool {
+	return StatusCode(err) == http.StatusPreconditionFailed
+}
@@ -0,0 +1,86 @@
+package sdk
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Event is a change notification from the server. Name is "list", "cart" or
+// "category" when the corresponding data has changed; "ping" events keep the
+// stream open and "close" announces that the server is shutting down.
+type Event struct {
+	Name string
+	Data string
+}
+
+// Subscribe opens the server's event stream. Events are delivered on the
+// returned channel, which is closed when ctx is cancelled or the connection
+// is lost. Callers that want to keep receiving events should subscribe again.
+func (c *Client) Subscribe(ctx context.Context) (<-chan Event, error) {
+	resp, err := c.do(ctx, newRequest(http.MethodGet, "/api/v1/events"))
+	if err != nil {
+		return nil, err
+	} else if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, newError(resp)
+	}
+
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+
+		readEvents(resp.Body, func(evt Event) bool {
+			select {
+			case ch <- evt:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+
+	return ch, nil
+}
+
+// readEvents parses a text/event-stream, calling emit for each event until
+// the stream ends or emit returns false.
+func readEvents(r io.Reader, emit func(Event) bool) error {
+	scanner := bufio.NewScanner(r)
+
+	var evt Event
+	var data []string
+	pending := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if pending {
+				evt.Data = strings.Join(data, "\n")
+				if !emit(evt) {
+					return nil
+				}
+			}
+			evt, data, pending = Event{}, nil, false
+			continue
+		} else if strings.HasPrefix(line, ":") {
+			// Comment
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			evt.Name = value
+			pending = true
+		case "data":
+			data = append(data, value)
+			pending = true
+		}
+	}
+
+	return scanner.Err()
+}
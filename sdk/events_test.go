@@ -0,0 +1,55 @@
+package sdk
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadEvents(t *testing.T) {
+	stream := strings.Join([]string{
+		"event: ping",
+		"data: ",
+		"",
+		": a comment",
+		"event: list",
+		"data: first",
+		"data: second",
+		"",
+		"",
+		"data: unnamed",
+		"",
+		"event: truncated",
+	}, "\n")
+
+	got := []Event{}
+	err := readEvents(strings.NewReader(stream), func(evt Event) bool {
+		got = append(got, evt)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("readEvents() error = %v", err)
+	}
+
+	want := []Event{
+		{Name: "ping", Data: ""},
+		{Name: "list", Data: "first\nsecond"},
+		{Name: "", Data: "unnamed"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readEvents() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadEventsStop(t *testing.T) {
+	stream := "event: list\ndata: \n\nevent: cart\ndata: \n\n"
+
+	got := 0
+	readEvents(strings.NewReader(stream), func(evt Event) bool {
+		got++
+		return false
+	})
+	if got != 1 {
+		t.Errorf("readEvents() emitted %d events after being stopped, want 1", got)
+	}
+}
@@ -0,0 +1,679 @@
+// Command gen generates the SDK's types and operations from the API's OpenAPI
+// document. It is run by go generate in the sdk package:
+//
+//	gen -spec ../internal/api/openapi.yaml -out .
+//
+// Every schema in the document becomes a type, and every operation a method
+// on Client. Operations that stream events are left to hand-written code.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+const header = "// Code generated by sdk/internal/gen from openapi.yaml. DO NOT EDIT.\n\npackage sdk\n\n"
+
+func main() {
+	specPath := flag.String("spec", "", "OpenAPI document to generate from")
+	out := flag.String("out", ".", "directory to write the generated files to")
+	flag.Parse()
+
+	raw, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	files, err := generate(raw)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(*out, name), src, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// generate returns the source of each generated file by name.
+func generate(raw []byte) (map[string][]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse OpenAPI document: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("parse OpenAPI document: empty document")
+	}
+
+	g := &generator{doc: node{doc.Content[0]}, declared: map[string]bool{}}
+	for _, schema := range g.doc.get("components").get("schemas").pairs() {
+		g.declare(typeName(schema.key), schema.val, "the "+schema.key+" schema")
+	}
+	types := g.flush()
+
+	for _, item := range g.doc.get("paths").pairs() {
+		for _, op := range item.val.pairs() {
+			if op.key == "parameters" {
+				continue
+			}
+			if err := g.operation(strings.ToUpper(op.key), item.key, item.val, op.val); err != nil {
+				return nil, err
+			}
+		}
+	}
+	ops := g.flush()
+
+	ret := map[string][]byte{}
+	for name, body := range map[string]string{
+		"types_gen.go":      header + "import (\n\"encoding/json\"\n\"time\"\n)\n\n" + types + typeUses,
+		"operations_gen.go": header + "import (\n\"context\"\n\"net/http\"\n\"net/url\"\n\"strconv\"\n)\n\n" + ops + opUses,
+	} {
+		src, err := format.Source([]byte(body))
+		if err != nil {
+			return nil, fmt.Errorf("format %s: %w", name, err)
+		}
+		ret[name] = src
+	}
+
+	return ret, nil
+}
+
+// typeUses and opUses keep the generated imports in use whatever the
+// document contains.
+const (
+	typeUses = "var (\n_ json.RawMessage\n_ time.Time\n)\n"
+	opUses   = "var (\n_ = url.PathEscape\n_ = strconv.Itoa\n)\n"
+)
+
+// generator accumulates the declarations generated from a document.
+type generator struct {
+	doc      node
+	declared map[string]bool
+
+	// decls holds declarations in the order they were started, so that a
+	// type is followed by the inline types of its fields.
+	decls []*bytes.Buffer
+}
+
+func (g *generator) start() *bytes.Buffer {
+	b := &bytes.Buffer{}
+	g.decls = append(g.decls, b)
+	return b
+}
+
+func (g *generator) flush() string {
+	var b strings.Builder
+	for _, d := range g.decls {
+		b.Write(d.Bytes())
+		b.WriteString("\n")
+	}
+	g.decls = nil
+	return b.String()
+}
+
+// resolve follows a local $ref.
+func (g *generator) resolve(n node) node {
+	for {
+		ref := n.get("$ref").str()
+		if !strings.HasPrefix(ref, "#/") {
+			return n
+		}
+		n = g.doc
+		for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			n = n.get(part)
+		}
+	}
+}
+
+// goType returns the Go type of a schema, declaring a type named hint for
+// schemas that need one. what describes the schema in the type's comment.
+func (g *generator) goType(s node, hint, what string) string {
+	if ref := s.get("$ref").str(); ref != "" {
+		return typeName(path.Base(ref))
+	}
+
+	if alts := s.get("oneOf").items(); len(alts) > 0 {
+		var others []node
+		for _, alt := range alts {
+			if alt.get("type").str() != "null" {
+				others = append(others, alt)
+			}
+		}
+		if len(others) == 1 {
+			return nullable(g.goType(others[0], hint, what))
+		}
+		return "json.RawMessage"
+	}
+
+	if s.get("allOf").ok() || len(s.get("properties").pairs()) > 0 || len(s.get("enum").items()) > 0 {
+		g.declare(hint, s, what)
+		return hint
+	}
+
+	types := s.get("type").strs()
+	isNullable := slices.Contains(types, "null")
+	types = slices.DeleteFunc(types, func(t string) bool { return t == "null" })
+	if len(types) != 1 {
+		return "any"
+	}
+
+	var t string
+	switch types[0] {
+	case "string":
+		t = "string"
+		if s.get("format").str() == "date-time" {
+			t = "time.Time"
+		}
+	case "integer":
+		t = "int"
+		if s.get("format").str() == "int64" {
+			t = "int64"
+		}
+	case "number":
+		t = "float64"
+	case "boolean":
+		t = "bool"
+	case "array":
+		t = "[]" + g.goType(s.get("items"), singular(hint), "an element of "+what)
+	case "object":
+		t = "map[string]any"
+		if extra := s.get("additionalProperties"); extra.isMap() {
+			t = "map[string]" + g.goType(extra, hint+"Value", "a value of "+what)
+		}
+	default:
+		t = "any"
+	}
+
+	if isNullable {
+		return nullable(t)
+	}
+	return t
+}
+
+// declare declares a named type for a schema. Objects become structs, with
+// the members of an allOf embedded, and string enums become string types with
+// a constant for each value.
+func (g *generator) declare(name string, s node, what string) {
+	if g.declared[name] {
+		return
+	}
+	g.declared[name] = true
+
+	if !s.get("properties").ok() && !s.get("allOf").ok() && !s.get("enum").ok() {
+		b := g.start()
+		typeComment(b, name, what, s.get("description").str())
+		fmt.Fprintf(b, "type %s = %s\n", name, g.goType(s, name+"Value", what))
+		return
+	}
+
+	b := g.start()
+	typeComment(b, name, what, s.get("description").str())
+
+	if values := s.get("enum").strs(); len(values) > 0 {
+		fmt.Fprintf(b, "type %s string\n\nconst (\n", name)
+		for _, v := range values {
+			if v == "" {
+				continue
+			}
+			fmt.Fprintf(b, "%s %s = %q\n", name+enumName(v), name, v)
+		}
+		b.WriteString(")\n")
+		return
+	}
+
+	fmt.Fprintf(b, "type %s struct {\n", name)
+	parts := s.get("allOf").items()
+	if len(parts) == 0 {
+		parts = []node{s}
+	}
+	for _, part := range parts {
+		if ref := part.get("$ref").str(); ref != "" {
+			fmt.Fprintf(b, "%s\n", typeName(path.Base(ref)))
+			continue
+		}
+
+		required := part.get("required").strs()
+		for _, prop := range part.get("properties").pairs() {
+			field := fieldName(prop.key)
+			t := g.goType(prop.val, name+field, name+"."+field)
+			tag := prop.key
+			if !slices.Contains(required, prop.key) {
+				t = optional(t)
+				// An empty slice or map is a value, such as a list cleared
+				// of its entries, so only nil ones are left out
+				if strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") {
+					tag += ",omitzero"
+				} else {
+					tag += ",omitempty"
+				}
+			}
+
+			desc := prop.val.get("description").str()
+			if desc == "" {
+				desc = g.resolve(prop.val).get("description").str()
+			}
+			if prop.val.get("deprecated").str() == "true" {
+				desc += "\n\nDeprecated: This field may be removed in a future version of the API."
+			}
+			comment(b, "\t", desc)
+			fmt.Fprintf(b, "%s %s `json:%q`\n", field, t, tag)
+		}
+	}
+	b.WriteString("}\n")
+}
+
+// param is a path, query or header parameter of an operation.
+type param struct {
+	name   string
+	in     string
+	field  string
+	goType string
+	desc   string
+}
+
+// operation declares a method on Client for an operation, along with its
+// parameters type and any inline response type.
+func (g *generator) operation(method, route string, item, op node) error {
+	id := op.get("operationId").str()
+	if id == "" {
+		return fmt.Errorf("%s %s has no operationId", method, route)
+	}
+	name := upperFirst(id)
+
+	// Find the successful response
+	var result string
+	var download, paged bool
+	var pageElem string
+	for _, resp := range op.get("responses").pairs() {
+		if !strings.HasPrefix(resp.key, "2") {
+			continue
+		}
+		resp := g.resolve(resp.val)
+		content := resp.get("content")
+		if content.get("text/event-stream").ok() {
+			// Streams are hand-written
+			return nil
+		}
+		// Attachments are handed over as they are, rather than decoded
+		download = resp.get("headers").get("Content-Disposition").ok()
+		for _, media := range content.pairs() {
+			if media.key != "application/json" {
+				download = true
+			}
+		}
+		if download {
+			result = "Download"
+		} else if schema := content.get("application/json").get("schema"); schema.ok() {
+			result = g.goType(schema, name+"Response", "the response of "+name)
+			data := schema.get("properties").get("data")
+			if schema.get("properties").get("meta").get("$ref").str() == "#/components/schemas/PageMeta" && data.ok() {
+				paged = true
+				pageElem = strings.TrimPrefix(g.goType(data, name+"Item", ""), "[]")
+			}
+		}
+		break
+	}
+
+	// Collect the parameters
+	var pathParams, otherParams []param
+	for _, p := range append(item.get("parameters").items(), op.get("parameters").items()...) {
+		p = g.resolve(p)
+		prm := param{
+			name: p.get("name").str(),
+			in:   p.get("in").str(),
+			desc: p.get("description").str(),
+		}
+		prm.goType = paramType(p.get("schema"))
+		switch prm.in {
+		case "path":
+			pathParams = append(pathParams, prm)
+		case "query", "header":
+			prm.field = fieldName(prm.name)
+			if prm.name == "q" {
+				prm.field = "Query"
+			}
+			otherParams = append(otherParams, prm)
+		}
+	}
+	paged = paged && slices.ContainsFunc(otherParams, func(p param) bool { return p.name == "cursor" })
+
+	if len(otherParams) > 0 {
+		b := g.start()
+		fmt.Fprintf(b, "// %sParams holds the optional parameters of %s. Unset fields are not sent.\n", name, name)
+		fmt.Fprintf(b, "type %sParams struct {\n", name)
+		for _, p := range otherParams {
+			comment(b, "\t", p.desc)
+			t := p.goType
+			if t != "string" {
+				t = "*" + t
+			}
+			fmt.Fprintf(b, "%s %s\n", p.field, t)
+		}
+		b.WriteString("}\n")
+	}
+
+	// Build the method signature
+	args := []string{"ctx context.Context"}
+	for _, p := range pathParams {
+		args = append(args, lowerFirst(fieldName(p.name))+" "+p.goType)
+	}
+	if len(otherParams) > 0 {
+		args = append(args, "params *"+name+"Params")
+	}
+	var bodyType string
+	if schema := g.resolve(op.get("requestBody")).get("content").get("application/json").get("schema"); schema.ok() {
+		bodyType = g.goType(schema, name+"Request", "the request body of "+name)
+		args = append(args, "body "+bodyType)
+	}
+
+	b := g.start()
+	fmt.Fprintf(b, "// %s sends %s %s.\n", name, method, route)
+	if summary := op.get("summary").str(); summary != "" {
+		b.WriteString("//\n")
+		comment(b, "", summary+".")
+	}
+	if desc := op.get("description").str(); desc != "" {
+		b.WriteString("//\n")
+		comment(b, "", desc)
+	}
+
+	ret := "error"
+	if result != "" {
+		ret = "(" + result + ", error)"
+	}
+	fmt.Fprintf(b, "func (c *Client) %s(%s) %s {\n", name, strings.Join(args, ", "), ret)
+	fmt.Fprintf(b, "req := newRequest(http.Method%s, %s)\n", methodConst(method), pathExpr(route, pathParams))
+	if len(otherParams) > 0 {
+		b.WriteString("if params != nil {\n")
+		for _, p := range otherParams {
+			target := "req.query"
+			if p.in == "header" {
+				target = "req.header"
+			}
+			if p.goType == "string" {
+				fmt.Fprintf(b, "if params.%s != \"\" {\n%s.Set(%q, params.%s)\n}\n", p.field, target, p.name, p.field)
+			} else {
+				fmt.Fprintf(b, "if params.%s != nil {\n%s.Set(%q, %s)\n}\n", p.field, target, p.name, formatValue(p.goType, "*params."+p.field))
+			}
+		}
+		b.WriteString("}\n")
+	}
+	if bodyType != "" {
+		b.WriteString("req.body = body\n")
+	}
+	if result == "" {
+		b.WriteString("return c.send(ctx, req, nil)\n}\n")
+	} else {
+		fmt.Fprintf(b, "var ret %s\nerr := c.send(ctx, req, &ret)\nreturn ret, err\n}\n", result)
+	}
+
+	if paged {
+		b := g.start()
+		fmt.Fprintf(b, "// %sAll calls %s for every page, following the cursor of each, and\n// returns the entries of all of them. Pages are as large as the API allows\n// unless params sets a limit.\n", name, name)
+		fmt.Fprintf(b, "func (c *Client) %sAll(ctx context.Context, params *%sParams) ([]%s, error) {\n", name, name, pageElem)
+		fmt.Fprintf(b, "var p %sParams\nif params != nil {\np = *params\n}\nif p.Limit == nil {\np.Limit = Ptr(pageLimit)\n}\n", name)
+		fmt.Fprintf(b, "return collect(func(cursor string) ([]%s, PageMeta, error) {\np.Cursor = cursor\npage, err := c.%s(ctx, &p)\nreturn page.Data, page.Meta, err\n})\n}\n", pageElem, name)
+	}
+
+	return nil
+}
+
+// pathExpr returns an expression building a route with its path parameters
+// filled in.
+func pathExpr(route string, params []param) string {
+	var parts []string
+	for {
+		before, rest, found := strings.Cut(route, "{")
+		if !found {
+			break
+		}
+		name, after, _ := strings.Cut(rest, "}")
+		parts = append(parts, fmt.Sprintf("%q", before))
+
+		value := lowerFirst(fieldName(name))
+		for _, p := range params {
+			if p.name == name {
+				value = formatValue(p.goType, value)
+			}
+		}
+		parts = append(parts, "url.PathEscape("+value+")")
+		route = after
+	}
+	if route != "" || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%q", route))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// paramType returns the Go type of a parameter. Enums are sent as strings.
+func paramType(s node) string {
+	switch s.get("type").str() {
+	case "integer":
+		if s.get("format").str() == "int64" {
+			return "int64"
+		}
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	}
+	return "string"
+}
+
+// formatValue returns an expression formatting a value of a Go type as a
+// parameter.
+func formatValue(goType, value string) string {
+	switch goType {
+	case "int":
+		return "strconv.Itoa(" + value + ")"
+	case "int64":
+		return "strconv.FormatInt(" + value + ", 10)"
+	case "bool":
+		return "strconv.FormatBool(" + value + ")"
+	case "float64":
+		return "strconv.FormatFloat(" + value + ", 'f', -1, 64)"
+	case "string":
+		return value
+	}
+	return "string(" + value + ")"
+}
+
+// typeComment writes the doc comment of a type, naming the schema it was
+// generated from.
+func typeComment(b *bytes.Buffer, name, what, desc string) {
+	if !strings.HasPrefix(what, "the ") && !strings.HasPrefix(what, "an ") {
+		what = "the type of " + what
+	}
+	fmt.Fprintf(b, "// %s is %s.\n", name, what)
+	if strings.TrimSpace(desc) != "" {
+		b.WriteString("//\n")
+		comment(b, "", desc)
+	}
+}
+
+// comment writes text as a comment.
+func comment(b *bytes.Buffer, indent, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(b, "%s// %s\n", indent, strings.TrimRight(line, " "))
+	}
+}
+
+// typeName returns the Go name of a component schema. The Error schema
+// becomes ErrorResponse, leaving Error for the Go error the SDK returns.
+func typeName(schema string) string {
+	if schema == "Error" {
+		return "ErrorResponse"
+	}
+	return schema
+}
+
+// initialisms are written in upper case in Go names.
+var initialisms = map[string]string{
+	"id":   "ID",
+	"ok":   "OK",
+	"ids":  "IDs",
+	"url":  "URL",
+	"api":  "API",
+	"csv":  "CSV",
+	"json": "JSON",
+	"html": "HTML",
+}
+
+// fieldName returns the Go name of a JSON property or parameter name.
+func fieldName(s string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		if upper, ok := initialisms[strings.ToLower(word)]; ok {
+			b.WriteString(upper)
+		} else {
+			b.WriteString(upperFirst(word))
+		}
+	}
+	return b.String()
+}
+
+// enumName returns the suffix of the constant for an enum value. Values
+// starting with "-" are descending sort orders.
+func enumName(v string) string {
+	if desc, ok := strings.CutPrefix(v, "-"); ok {
+		return fieldName(desc) + "Desc"
+	}
+	return fieldName(v)
+}
+
+func methodConst(method string) string {
+	return upperFirst(strings.ToLower(method))
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	if s == strings.ToUpper(s) {
+		return strings.ToLower(s)
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// singular returns the name of an element of a list type name.
+func singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies"):
+		return strings.TrimSuffix(s, "ies") + "y"
+	case strings.HasSuffix(s, "ss"):
+		return s
+	case strings.HasSuffix(s, "s"):
+		return strings.TrimSuffix(s, "s")
+	}
+	return s
+}
+
+// nullable returns a type that can hold null as well as t.
+func nullable(t string) string {
+	if strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || strings.HasPrefix(t, "*") || t == "json.RawMessage" || t == "any" {
+		return t
+	}
+	return "*" + t
+}
+
+// optional returns a type that tells an unset field from a zero value.
+func optional(t string) string {
+	return nullable(t)
+}
+
+// node wraps a YAML node with lookups that tolerate missing values, which
+// return a node for which ok is false.
+type node struct {
+	*yaml.Node
+}
+
+type pair struct {
+	key string
+	val node
+}
+
+func (n node) ok() bool {
+	return n.Node != nil
+}
+
+func (n node) isMap() bool {
+	return n.ok() && n.Kind == yaml.MappingNode
+}
+
+func (n node) get(key string) node {
+	if !n.isMap() {
+		return node{}
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return node{n.Content[i+1]}
+		}
+	}
+	return node{}
+}
+
+func (n node) pairs() []pair {
+	if !n.isMap() {
+		return nil
+	}
+	ret := make([]pair, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		ret = append(ret, pair{key: n.Content[i].Value, val: node{n.Content[i+1]}})
+	}
+	return ret
+}
+
+func (n node) items() []node {
+	if !n.ok() || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	ret := make([]node, 0, len(n.Content))
+	for _, c := range n.Content {
+		ret = append(ret, node{c})
+	}
+	return ret
+}
+
+func (n node) str() string {
+	if !n.ok() || n.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return n.Value
+}
+
+// strs returns a sequence of scalars, or a single scalar as a sequence of
+// one.
+func (n node) strs() []string {
+	if n.ok() && n.Kind == yaml.ScalarNode {
+		return []string{n.Value}
+	}
+	var ret []string
+	for _, item := range n.items() {
+		ret = append(ret, item.str())
+	}
+	return ret
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGenerated checks that the generated files are up to date with the
+// OpenAPI description. Run go generate ./sdk after changing it.
+func TestGenerated(t *testing.T) {
+	raw, err := os.ReadFile("../../../internal/api/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := generate(raw)
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join("../..", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("sdk/%s is out of date with openapi.yaml; run go generate ./sdk", name)
+		}
+	}
+}
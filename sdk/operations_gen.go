@@ -0,0 +1,1039 @@
+// Code generated by sdk/internal/gen from openapi.yaml. DO NOT EDIT.
+
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// GetOpenAPISpec sends GET /api/v1/openapi.yaml.
+//
+// Download this API description.
+func (c *Client) GetOpenAPISpec(ctx context.Context) (Download, error) {
+	req := newRequest(http.MethodGet, "/api/v1/openapi.yaml")
+	var ret Download
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// GetDocs sends GET /api/v1/docs.
+//
+// Browse the API documentation.
+func (c *Client) GetDocs(ctx context.Context) (Download, error) {
+	req := newRequest(http.MethodGet, "/api/v1/docs")
+	var ret Download
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// AuthLogin sends POST /api/v1/auth/login.
+//
+// Log in and obtain an API token.
+//
+// Validates credentials and returns a Bearer token. The token is also stored
+// in Redis alongside any existing web session, sharing the same 720-hour TTL.
+func (c *Client) AuthLogin(ctx context.Context, body LoginRequest) (LoginResponse, error) {
+	req := newRequest(http.MethodPost, "/api/v1/auth/login")
+	req.body = body
+	var ret LoginResponse
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// AuthLogout sends POST /api/v1/auth/logout.
+//
+// Invalidate the current API token.
+//
+// Deletes the token from Redis, rendering it immediately invalid.
+func (c *Client) AuthLogout(ctx context.Context) error {
+	req := newRequest(http.MethodPost, "/api/v1/auth/logout")
+	return c.send(ctx, req, nil)
+}
+
+// AuthMe sends GET /api/v1/auth/me.
+//
+// Get the currently authenticated user.
+func (c *Client) AuthMe(ctx context.Context) (User, error) {
+	req := newRequest(http.MethodGet, "/api/v1/auth/me")
+	var ret User
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// Backup sends GET /api/v1/admin/backup.
+//
+// Download a backup archive (admin only).
+func (c *Client) Backup(ctx context.Context) (Download, error) {
+	req := newRequest(http.MethodGet, "/api/v1/admin/backup")
+	var ret Download
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// Restore sends POST /api/v1/admin/restore.
+//
+// Restore a backup archive (admin only).
+//
+// Loads the archive in a single transaction. Groups, users, stores and items
+// are matched to existing records by name, and categories by name within
+// their store; matched records are left unchanged and everything else is
+// created with new IDs. Every record is validated with the same rules as the
+// rest of the API, and any failure rolls back the whole restore.
+func (c *Client) Restore(ctx context.Context, body BackupArchive) (RestoreSummary, error) {
+	req := newRequest(http.MethodPost, "/api/v1/admin/restore")
+	req.body = body
+	var ret RestoreSummary
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// ListUsersResponse is the response of ListUsers.
+type ListUsersResponse struct {
+	Data []User `json:"data"`
+	// Describes a page of a listing. While `has_more` is true, pass
+	// `next_cursor` as the `cursor` of the next request, keeping the same
+	// `sort`, `q` and filters, to read the following page.
+	Meta PageMeta `json:"meta"`
+}
+
+// ListUsersParams holds the optional parameters of ListUsers. Unset fields are not sent.
+type ListUsersParams struct {
+	// Largest number of entries to return
+	Limit *int
+	// Order of the listing. A leading `-` sorts descending. Entries with the
+	// same name are ordered by ID.
+	Sort string
+	// Only return entries whose names contain this text, ignoring case
+	Query string
+	// `next_cursor` of the previous page. A cursor is only valid with the
+	// `sort` it was returned for.
+	Cursor string
+}
+
+// ListUsers sends GET /api/v1/users.
+//
+// List users.
+func (c *Client) ListUsers(ctx context.Context, params *ListUsersParams) (ListUsersResponse, error) {
+	req := newRequest(http.MethodGet, "/api/v1/users")
+	if params != nil {
+		if params.Limit != nil {
+			req.query.Set("limit", strconv.Itoa(*params.Limit))
+		}
+		if params.Sort != "" {
+			req.query.Set("sort", params.Sort)
+		}
+		if params.Query != "" {
+			req.query.Set("q", params.Query)
+		}
+		if params.Cursor != "" {
+			req.query.Set("cursor", params.Cursor)
+		}
+	}
+	var ret ListUsersResponse
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// ListUsersAll calls ListUsers for every page, following the cursor of each, and
+// returns the entries of all of them. Pages are as large as the API allows
+// unless params sets a limit.
+func (c *Client) ListUsersAll(ctx context.Context, params *ListUsersParams) ([]User, error) {
+	var p ListUsersParams
+	if params != nil {
+		p = *params
+	}
+	if p.Limit == nil {
+		p.Limit = Ptr(pageLimit)
+	}
+	return collect(func(cursor string) ([]User, PageMeta, error) {
+		p.Cursor = cursor
+		page, err := c.ListUsers(ctx, &p)
+		return page.Data, page.Meta, err
+	})
+}
+
+// CreateUser sends POST /api/v1/users.
+//
+// Create a new user.
+func (c *Client) CreateUser(ctx context.Context, body CreateUserRequest) (User, error) {
+	req := newRequest(http.MethodPost, "/api/v1/users")
+	req.body = body
+	var ret User
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// GetUser sends GET /api/v1/users/{id}.
+//
+// Get a user by ID.
+func (c *Client) GetUser(ctx context.Context, id int) (User, error) {
+	req := newRequest(http.MethodGet, "/api/v1/users/"+url.PathEscape(strconv.Itoa(id)))
+	var ret User
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// UpdateUser sends PUT /api/v1/users/{id}.
+//
+// Update a user.
+func (c *Client) UpdateUser(ctx context.Context, id int, body UpdateUserRequest) (User, error) {
+	req := newRequest(http.MethodPut, "/api/v1/users/"+url.PathEscape(strconv.Itoa(id)))
+	req.body = body
+	var ret User
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// DeleteUser sends DELETE /api/v1/users/{id}.
+//
+// Delete a user.
+func (c *Client) DeleteUser(ctx context.Context, id int) error {
+	req := newRequest(http.MethodDelete, "/api/v1/users/"+url.PathEscape(strconv.Itoa(id)))
+	return c.send(ctx, req, nil)
+}
+
+// ListGroups sends GET /api/v1/groups.
+//
+// List all groups.
+func (c *Client) ListGroups(ctx context.Context) ([]Group, error) {
+	req := newRequest(http.MethodGet, "/api/v1/groups")
+	var ret []Group
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// CreateGroup sends POST /api/v1/groups.
+//
+// Create a new group.
+func (c *Client) CreateGroup(ctx context.Context, body CreateGroupRequest) (Group, error) {
+	req := newRequest(http.MethodPost, "/api/v1/groups")
+	req.body = body
+	var ret Group
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// GetGroup sends GET /api/v1/groups/{id}.
+//
+// Get a group by ID.
+func (c *Client) GetGroup(ctx context.Context, id int) (Group, error) {
+	req := newRequest(http.MethodGet, "/api/v1/groups/"+url.PathEscape(strconv.Itoa(id)))
+	var ret Group
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// UpdateGroup sends PUT /api/v1/groups/{id}.
+//
+// Update a group.
+func (c *Client) UpdateGroup(ctx context.Context, id int, body UpdateGroupRequest) (Group, error) {
+	req := newRequest(http.MethodPut, "/api/v1/groups/"+url.PathEscape(strconv.Itoa(id)))
+	req.body = body
+	var ret Group
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// DeleteGroup sends DELETE /api/v1/groups/{id}.
+//
+// Delete a group.
+//
+// Fails if any users are still assigned to the group.
+func (c *Client) DeleteGroup(ctx context.Context, id int) error {
+	req := newRequest(http.MethodDelete, "/api/v1/groups/"+url.PathEscape(strconv.Itoa(id)))
+	return c.send(ctx, req, nil)
+}
+
+// ListStoresResponse is the response of ListStores.
+type ListStoresResponse struct {
+	Data []Store `json:"data"`
+	// Describes a page of a listing. While `has_more` is true, pass
+	// `next_cursor` as the `cursor` of the next request, keeping the same
+	// `sort`, `q` and filters, to read the following page.
+	Meta PageMeta `json:"meta"`
+}
+
+// ListStoresParams holds the optional parameters of ListStores. Unset fields are not sent.
+type ListStoresParams struct {
+	// Largest number of entries to return
+	Limit *int
+	// Order of the listing. A leading `-` sorts descending. Entries with the
+	// same name are ordered by ID.
+	Sort string
+	// Only return entries whose names contain this text, ignoring case
+	Query string
+	// `next_cursor` of the previous page. A cursor is only valid with the
+	// `sort` it was returned for.
+	Cursor string
+}
+
+// ListStores sends GET /api/v1/stores.
+//
+// List stores.
+func (c *Client) ListStores(ctx context.Context, params *ListStoresParams) (ListStoresResponse, error) {
+	req := newRequest(http.MethodGet, "/api/v1/stores")
+	if params != nil {
+		if params.Limit != nil {
+			req.query.Set("limit", strconv.Itoa(*params.Limit))
+		}
+		if params.Sort != "" {
+			req.query.Set("sort", params.Sort)
+		}
+		if params.Query != "" {
+			req.query.Set("q", params.Query)
+		}
+		if params.Cursor != "" {
+			req.query.Set("cursor", params.Cursor)
+		}
+	}
+	var ret ListStoresResponse
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// ListStoresAll calls ListStores for every page, following the cursor of each, and
+// returns the entries of all of them. Pages are as large as the API allows
+// unless params sets a limit.
+func (c *Client) ListStoresAll(ctx context.Context, params *ListStoresParams) ([]Store, error) {
+	var p ListStoresParams
+	if params != nil {
+		p = *params
+	}
+	if p.Limit == nil {
+		p.Limit = Ptr(pageLimit)
+	}
+	return collect(func(cursor string) ([]Store, PageMeta, error) {
+		p.Cursor = cursor
+		page, err := c.ListStores(ctx, &p)
+		return page.Data, page.Meta, err
+	})
+}
+
+// CreateStore sends POST /api/v1/stores.
+//
+// Create a new store.
+func (c *Client) CreateStore(ctx context.Context, body CreateStoreRequest) (Store, error) {
+	req := newRequest(http.MethodPost, "/api/v1/stores")
+	req.body = body
+	var ret Store
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// GetStoreResponse is the response of GetStore.
+type GetStoreResponse struct {
+	Store
+	Categories []Category `json:"categories"`
+}
+
+// GetStore sends GET /api/v1/stores/{id}.
+//
+// Get a store by ID, including its categories.
+func (c *Client) GetStore(ctx context.Context, id int) (GetStoreResponse, error) {
+	req := newRequest(http.MethodGet, "/api/v1/stores/"+url.PathEscape(strconv.Itoa(id)))
+	var ret GetStoreResponse
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// UpdateStore sends PUT /api/v1/stores/{id}.
+//
+// Update a store.
+func (c *Client) UpdateStore(ctx context.Context, id int, body UpdateStoreRequest) (Store, error) {
+	req := newRequest(http.MethodPut, "/api/v1/stores/"+url.PathEscape(strconv.Itoa(id)))
+	req.body = body
+	var ret Store
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// DeleteStore sends DELETE /api/v1/stores/{id}.
+//
+// Delete a store.
+//
+// Fails if any categories are still assigned to the store.
+func (c *Client) DeleteStore(ctx context.Context, id int) error {
+	req := newRequest(http.MethodDelete, "/api/v1/stores/"+url.PathEscape(strconv.Itoa(id)))
+	return c.send(ctx, req, nil)
+}
+
+// ListCategoriesResponse is the response of ListCategories.
+type ListCategoriesResponse struct {
+	Data []Category `json:"data"`
+	// Describes a page of a listing. While `has_more` is true, pass
+	// `next_cursor` as the `cursor` of the next request, keeping the same
+	// `sort`, `q` and filters, to read the following page.
+	Meta PageMeta `json:"meta"`
+}
+
+// ListCategoriesParams holds the optional parameters of ListCategories. Unset fields are not sent.
+type ListCategoriesParams struct {
+	// Largest number of entries to return
+	Limit *int
+	// Order of the listing. A leading `-` sorts descending. Entries with the
+	// same name are ordered by ID.
+	Sort string
+	// Only return entries whose names contain this text, ignoring case
+	Query string
+	// `next_cursor` of the previous page. A cursor is only valid with the
+	// `sort` it was returned for.
+	Cursor string
+}
+
+// ListCategories sends GET /api/v1/categories.
+//
+// List categories.
+func (c *Client) ListCategories(ctx context.Context, params *ListCategoriesParams) (ListCategoriesResponse, error) {
+	req := newRequest(http.MethodGet, "/api/v1/categories")
+	if params != nil {
+		if params.Limit != nil {
+			req.query.Set("limit", strconv.Itoa(*params.Limit))
+		}
+		if params.Sort != "" {
+			req.query.Set("sort", params.Sort)
+		}
+		if params.Query != "" {
+			req.query.Set("q", params.Query)
+		}
+		if params.Cursor != "" {
+			req.query.Set("cursor", params.Cursor)
+		}
+	}
+	var ret ListCategoriesResponse
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// ListCategoriesAll calls ListCategories for every page, following the cursor of each, and
+// returns the entries of all of them. Pages are as large as the API allows
+// unless params sets a limit.
+func (c *Client) ListCategoriesAll(ctx context.Context, params *ListCategoriesParams) ([]Category, error) {
+	var p ListCategoriesParams
+	if params != nil {
+		p = *params
+	}
+	if p.Limit == nil {
+		p.Limit = Ptr(pageLimit)
+	}
+	return collect(func(cursor string) ([]Category, PageMeta, error) {
+		p.Cursor = cursor
+		page, err := c.ListCategories(ctx, &p)
+		return page.Data, page.Meta, err
+	})
+}
+
+// CreateCategory sends POST /api/v1/categories.
+//
+// Create a new category.
+func (c *Client) CreateCategory(ctx context.Context, body CreateCategoryRequest) (Category, error) {
+	req := newRequest(http.MethodPost, "/api/v1/categories")
+	req.body = body
+	var ret Category
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// GetCategoryResponse is the response of GetCategory.
+type GetCategoryResponse struct {
+	Category
+	Items []Item `json:"items"`
+}
+
+// GetCategory sends GET /api/v1/categories/{id}.
+//
+// Get a category by ID, including its items.
+func (c *Client) GetCategory(ctx context.Context, id int) (GetCategoryResponse, error) {
+	req := newRequest(http.MethodGet, "/api/v1/categories/"+url.PathEscape(strconv.Itoa(id)))
+	var ret GetCategoryResponse
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// UpdateCategoryParams holds the optional parameters of UpdateCategory. Unset fields are not sent.
+type UpdateCategoryParams struct {
+	// ETag of the version the change was based on. When it no longer matches
+	// the stored version the update is rejected with 412 and the current
+	// state, so that concurrent edits are not silently overwritten. Omit it
+	// to update unconditionally.
+	IfMatch string
+}
+
+// UpdateCategory sends PUT /api/v1/categories/{id}.
+//
+// Update a category.
+func (c *Client) UpdateCategory(ctx context.Context, id int, params *UpdateCategoryParams, body UpdateCategoryRequest) (Category, error) {
+	req := newRequest(http.MethodPut, "/api/v1/categories/"+url.PathEscape(strconv.Itoa(id)))
+	if params != nil {
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	req.body = body
+	var ret Category
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// DeleteCategory sends DELETE /api/v1/categories/{id}.
+//
+// Delete a category.
+//
+// Fails if any items are still assigned to the category.
+func (c *Client) DeleteCategory(ctx context.Context, id int) error {
+	req := newRequest(http.MethodDelete, "/api/v1/categories/"+url.PathEscape(strconv.Itoa(id)))
+	return c.send(ctx, req, nil)
+}
+
+// ListItemsResponse is the response of ListItems.
+type ListItemsResponse struct {
+	Data []Item `json:"data"`
+	// Describes a page of a listing. While `has_more` is true, pass
+	// `next_cursor` as the `cursor` of the next request, keeping the same
+	// `sort`, `q` and filters, to read the following page.
+	Meta PageMeta `json:"meta"`
+}
+
+// ListItemsParams holds the optional parameters of ListItems. Unset fields are not sent.
+type ListItemsParams struct {
+	// Filter items by category. Use `0` to list the uncategorized items
+	// awaiting triage.
+	CategoryID *int
+	// Filter to only items that are (or are not) on the shopping list
+	InList *bool
+	// Largest number of entries to return
+	Limit *int
+	// Order of the listing. A leading `-` sorts descending. Entries with the
+	// same name are ordered by ID.
+	Sort string
+	// Only return entries whose names contain this text, ignoring case
+	Query string
+	// `next_cursor` of the previous page. A cursor is only valid with the
+	// `sort` it was returned for.
+	Cursor string
+}
+
+// ListItems sends GET /api/v1/items.
+//
+// List items.
+func (c *Client) ListItems(ctx context.Context, params *ListItemsParams) (ListItemsResponse, error) {
+	req := newRequest(http.MethodGet, "/api/v1/items")
+	if params != nil {
+		if params.CategoryID != nil {
+			req.query.Set("category_id", strconv.Itoa(*params.CategoryID))
+		}
+		if params.InList != nil {
+			req.query.Set("in_list", strconv.FormatBool(*params.InList))
+		}
+		if params.Limit != nil {
+			req.query.Set("limit", strconv.Itoa(*params.Limit))
+		}
+		if params.Sort != "" {
+			req.query.Set("sort", params.Sort)
+		}
+		if params.Query != "" {
+			req.query.Set("q", params.Query)
+		}
+		if params.Cursor != "" {
+			req.query.Set("cursor", params.Cursor)
+		}
+	}
+	var ret ListItemsResponse
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// ListItemsAll calls ListItems for every page, following the cursor of each, and
+// returns the entries of all of them. Pages are as large as the API allows
+// unless params sets a limit.
+func (c *Client) ListItemsAll(ctx context.Context, params *ListItemsParams) ([]Item, error) {
+	var p ListItemsParams
+	if params != nil {
+		p = *params
+	}
+	if p.Limit == nil {
+		p.Limit = Ptr(pageLimit)
+	}
+	return collect(func(cursor string) ([]Item, PageMeta, error) {
+		p.Cursor = cursor
+		page, err := c.ListItems(ctx, &p)
+		return page.Data, page.Meta, err
+	})
+}
+
+// CreateItem sends POST /api/v1/items.
+//
+// Create a new item.
+func (c *Client) CreateItem(ctx context.Context, body CreateItemRequest) (Item, error) {
+	req := newRequest(http.MethodPost, "/api/v1/items")
+	req.body = body
+	var ret Item
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// GetItem sends GET /api/v1/items/{id}.
+//
+// Get an item by ID.
+func (c *Client) GetItem(ctx context.Context, id int) (Item, error) {
+	req := newRequest(http.MethodGet, "/api/v1/items/"+url.PathEscape(strconv.Itoa(id)))
+	var ret Item
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// UpdateItemParams holds the optional parameters of UpdateItem. Unset fields are not sent.
+type UpdateItemParams struct {
+	// ETag of the version the change was based on. When it no longer matches
+	// the stored version the update is rejected with 412 and the current
+	// state, so that concurrent edits are not silently overwritten. Omit it
+	// to update unconditionally.
+	IfMatch string
+}
+
+// UpdateItem sends PUT /api/v1/items/{id}.
+//
+// Update an item.
+func (c *Client) UpdateItem(ctx context.Context, id int, params *UpdateItemParams, body UpdateItemRequest) (Item, error) {
+	req := newRequest(http.MethodPut, "/api/v1/items/"+url.PathEscape(strconv.Itoa(id)))
+	if params != nil {
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	req.body = body
+	var ret Item
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// DeleteItem sends DELETE /api/v1/items/{id}.
+//
+// Delete an item.
+//
+// Fails if the item is used in any recipes.
+func (c *Client) DeleteItem(ctx context.Context, id int) error {
+	req := newRequest(http.MethodDelete, "/api/v1/items/"+url.PathEscape(strconv.Itoa(id)))
+	return c.send(ctx, req, nil)
+}
+
+// BatchItems sends POST /api/v1/items/batch.
+//
+// Assign, merge or delete several items.
+//
+// Intended for triaging uncategorized items. Each item is processed
+// independently, so the response reports a result per item rather than
+// failing the whole request. Items on the shopping list cannot be deleted.
+func (c *Client) BatchItems(ctx context.Context, body BatchItemsRequest) (BatchItemsResponse, error) {
+	req := newRequest(http.MethodPost, "/api/v1/items/batch")
+	req.body = body
+	var ret BatchItemsResponse
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// SearchItemsParams holds the optional parameters of SearchItems. Unset fields are not sent.
+type SearchItemsParams struct {
+	// Search text
+	Query string
+	// Maximum number of results
+	Limit *int
+}
+
+// SearchItems sends GET /api/v1/items/search.
+//
+// Search items by name.
+//
+// Case-insensitive, typo-tolerant search ranked by trigram similarity. Use it
+// to suggest existing items before creating new ones.
+func (c *Client) SearchItems(ctx context.Context, params *SearchItemsParams) ([]ItemMatch, error) {
+	req := newRequest(http.MethodGet, "/api/v1/items/search")
+	if params != nil {
+		if params.Query != "" {
+			req.query.Set("q", params.Query)
+		}
+		if params.Limit != nil {
+			req.query.Set("limit", strconv.Itoa(*params.Limit))
+		}
+	}
+	var ret []ItemMatch
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// GetItemByBarcode sends GET /api/v1/items/by-barcode/{code}.
+//
+// Resolve a UPC/EAN barcode.
+//
+// Returns the item the barcode is assigned to. Unknown barcodes are passed to
+// the product lookup provider, in which case `item` is null and `product`
+// describes the product so that a client can offer to create it.
+func (c *Client) GetItemByBarcode(ctx context.Context, code string) (BarcodeLookup, error) {
+	req := newRequest(http.MethodGet, "/api/v1/items/by-barcode/"+url.PathEscape(code))
+	var ret BarcodeLookup
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// MergeItem sends POST /api/v1/items/{id}/merge.
+//
+// Merge an item into another item.
+//
+// Moves the item's list entry, barcodes, aliases, staple schedule and pantry
+// stock to the target item in a single transaction, then deletes the item.
+// When both items are on the list their quantities are combined. The merged
+// item's name becomes an alias of the target.
+func (c *Client) MergeItem(ctx context.Context, id int, body MergeItemRequest) (Item, error) {
+	req := newRequest(http.MethodPost, "/api/v1/items/"+url.PathEscape(strconv.Itoa(id))+"/merge")
+	req.body = body
+	var ret Item
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// AddItemBarcode sends POST /api/v1/items/{id}/barcodes.
+//
+// Assign a barcode to an item.
+//
+// Assigning a barcode the item already carries succeeds without changes.
+func (c *Client) AddItemBarcode(ctx context.Context, id int, body AddBarcodeRequest) (Barcode, error) {
+	req := newRequest(http.MethodPost, "/api/v1/items/"+url.PathEscape(strconv.Itoa(id))+"/barcodes")
+	req.body = body
+	var ret Barcode
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// DeleteItemBarcode sends DELETE /api/v1/items/{id}/barcodes/{code}.
+//
+// Remove a barcode from an item.
+func (c *Client) DeleteItemBarcode(ctx context.Context, id int, code string) error {
+	req := newRequest(http.MethodDelete, "/api/v1/items/"+url.PathEscape(strconv.Itoa(id))+"/barcodes/"+url.PathEscape(code))
+	return c.send(ctx, req, nil)
+}
+
+// GetList sends GET /api/v1/list.
+//
+// Get the current shopping list.
+func (c *Client) GetList(ctx context.Context) (ShoppingList, error) {
+	req := newRequest(http.MethodGet, "/api/v1/list")
+	var ret ShoppingList
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// AddToList sends POST /api/v1/list/items.
+//
+// Add an item to the shopping list.
+//
+// Supply either `item_id` for an existing item, `barcode` for a scanned
+// product, or `name` to create a new uncategorized item and add it in one step.
+// A `name` matching an item's name or alias adds that item. Any other name
+// creates a new item, even a close misspelling such as "bannana"; the
+// response then lists similar existing items in `suggestions` so the client
+// can ask whether one of those was meant.
+// New items are filed under the category of similar catalog items when the
+// match is confident, and are otherwise left uncategorized.
+func (c *Client) AddToList(ctx context.Context, body AddToListRequest) (AddToListResponse, error) {
+	req := newRequest(http.MethodPost, "/api/v1/list/items")
+	req.body = body
+	var ret AddToListResponse
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// AddToListBatch sends POST /api/v1/list/items:batch.
+//
+// Add several items to the shopping list.
+//
+// Adds every entry in a single transaction and publishes a single list
+// event. Names are resolved the same way as `addToList`. Entries that
+// cannot be added, because the item does not exist or is already on the
+// list, are reported in their result without failing the others.
+func (c *Client) AddToListBatch(ctx context.Context, body AddToListBatchRequest) (AddToListBatchResponse, error) {
+	req := newRequest(http.MethodPost, "/api/v1/list/items:batch")
+	req.body = body
+	var ret AddToListBatchResponse
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// ImportList sends POST /api/v1/list/import.
+//
+// Import a list from text, Markdown or CSV.
+//
+// Parses the list and adds every item in a single transaction, resolving
+// names the same way as `addToList`. The results report each line,
+// including those that created new uncategorized items.
+func (c *Client) ImportList(ctx context.Context, body ImportListRequest) (ImportListResponse, error) {
+	req := newRequest(http.MethodPost, "/api/v1/list/import")
+	req.body = body
+	var ret ImportListResponse
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// UpdateListItemParams holds the optional parameters of UpdateListItem. Unset fields are not sent.
+type UpdateListItemParams struct {
+	// ETag of the version the change was based on. When it no longer matches
+	// the stored version the update is rejected with 412 and the current
+	// state, so that concurrent edits are not silently overwritten. Omit it
+	// to update unconditionally.
+	IfMatch string
+}
+
+// UpdateListItem sends PUT /api/v1/list/items/{id}.
+//
+// Update a list item's quantity or done status.
+func (c *Client) UpdateListItem(ctx context.Context, id int, params *UpdateListItemParams, body UpdateListItemRequest) (ListItem, error) {
+	req := newRequest(http.MethodPut, "/api/v1/list/items/"+url.PathEscape(strconv.Itoa(id)))
+	if params != nil {
+		if params.IfMatch != "" {
+			req.header.Set("If-Match", params.IfMatch)
+		}
+	}
+	req.body = body
+	var ret ListItem
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// RemoveFromList sends DELETE /api/v1/list/items/{id}.
+//
+// Remove an item from the shopping list.
+func (c *Client) RemoveFromList(ctx context.Context, id int) error {
+	req := newRequest(http.MethodDelete, "/api/v1/list/items/"+url.PathEscape(strconv.Itoa(id)))
+	return c.send(ctx, req, nil)
+}
+
+// FinishShopping sends POST /api/v1/list/finish.
+//
+// Finish shopping - remove all done items from the list.
+//
+// Each purchased item is added to the pantry. Quantities that start with a
+// whole number ("2", "12 eggs") add that many units; anything else adds one.
+func (c *Client) FinishShopping(ctx context.Context) error {
+	req := newRequest(http.MethodPost, "/api/v1/list/finish")
+	return c.send(ctx, req, nil)
+}
+
+// ExportListParams holds the optional parameters of ExportList. Unset fields are not sent.
+type ExportListParams struct {
+	Format string
+}
+
+// ExportList sends GET /api/v1/list/export.
+//
+// Export the shopping list.
+//
+// Downloads the list grouped by store and category. The Markdown checklist
+// and CSV exports can be imported again with `importList`. The `html`
+// export is a print-optimized page listing only the items still to buy.
+func (c *Client) ExportList(ctx context.Context, params *ExportListParams) (Download, error) {
+	req := newRequest(http.MethodGet, "/api/v1/list/export")
+	if params != nil {
+		if params.Format != "" {
+			req.query.Set("format", params.Format)
+		}
+	}
+	var ret Download
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// Sync sends POST /api/v1/sync.
+//
+// Apply offline changes and fetch server changes.
+//
+// Applies a batch of shopping list changes made while offline in a single
+// transaction, and returns every change made on the server since the
+// client's cursor.
+//
+// Conflicts are resolved by the time each change was made, so the outcome
+// does not depend on which client synced first. Operations are applied
+// oldest first. Checking an item off and changing its quantity never
+// conflict with each other, while adding and removing affect the whole
+// entry. An operation is superseded when a later change has been made to
+// anything it affects. Timestamps ahead of the server's clock are treated
+// as the current time.
+func (c *Client) Sync(ctx context.Context, body SyncRequest) (SyncResponse, error) {
+	req := newRequest(http.MethodPost, "/api/v1/sync")
+	req.body = body
+	var ret SyncResponse
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// ListChangesParams holds the optional parameters of ListChanges. Unset fields are not sent.
+type ListChangesParams struct {
+	// The cursor returned by the previous read
+	Since *int64
+	// Maximum number of entities to return
+	Limit *int
+}
+
+// ListChanges sends GET /api/v1/changes.
+//
+// Read the change feed.
+//
+// Returns the stores, categories, items and list items created, updated or
+// deleted since a cursor, with the current state of each, so that clients
+// can keep a local copy up to date without reloading everything. Deleted
+// entities are returned as tombstones without `data`.
+//
+// Cursors come from a sequence that only ever grows in commit order, so a
+// client that has read up to a cursor has seen every earlier change.
+// Start from `0` to receive everything, and keep reading while
+// `has_more` is true.
+func (c *Client) ListChanges(ctx context.Context, params *ListChangesParams) (ChangesPage, error) {
+	req := newRequest(http.MethodGet, "/api/v1/changes")
+	if params != nil {
+		if params.Since != nil {
+			req.query.Set("since", strconv.FormatInt(*params.Since, 10))
+		}
+		if params.Limit != nil {
+			req.query.Set("limit", strconv.Itoa(*params.Limit))
+		}
+	}
+	var ret ChangesPage
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// ExportCatalogParams holds the optional parameters of ExportCatalog. Unset fields are not sent.
+type ExportCatalogParams struct {
+	Format string
+}
+
+// ExportCatalog sends GET /api/v1/catalog/export.
+//
+// Export every store, category and item.
+func (c *Client) ExportCatalog(ctx context.Context, params *ExportCatalogParams) (Download, error) {
+	req := newRequest(http.MethodGet, "/api/v1/catalog/export")
+	if params != nil {
+		if params.Format != "" {
+			req.query.Set("format", params.Format)
+		}
+	}
+	var ret Download
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// ListPantry sends GET /api/v1/pantry.
+//
+// List all pantry items.
+func (c *Client) ListPantry(ctx context.Context) ([]PantryItem, error) {
+	req := newRequest(http.MethodGet, "/api/v1/pantry")
+	var ret []PantryItem
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// ListExpiringPantryItemsParams holds the optional parameters of ListExpiringPantryItems. Unset fields are not sent.
+type ListExpiringPantryItemsParams struct {
+	// How many days ahead to look
+	Days *int
+}
+
+// ListExpiringPantryItems sends GET /api/v1/pantry/expiring.
+//
+// List pantry items nearing their best-before date.
+//
+// Returns in-stock pantry items whose best-before date falls within the
+// next `days` days, including items that have already expired. Results
+// are ordered soonest first.
+func (c *Client) ListExpiringPantryItems(ctx context.Context, params *ListExpiringPantryItemsParams) ([]PantryItem, error) {
+	req := newRequest(http.MethodGet, "/api/v1/pantry/expiring")
+	if params != nil {
+		if params.Days != nil {
+			req.query.Set("days", strconv.Itoa(*params.Days))
+		}
+	}
+	var ret []PantryItem
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// GetPantryItem sends GET /api/v1/pantry/{id}.
+//
+// Get the pantry stock for an item.
+func (c *Client) GetPantryItem(ctx context.Context, id int) (PantryItem, error) {
+	req := newRequest(http.MethodGet, "/api/v1/pantry/"+url.PathEscape(strconv.Itoa(id)))
+	var ret PantryItem
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// UpdatePantryItem sends PUT /api/v1/pantry/{id}.
+//
+// Create or replace the pantry stock for an item.
+func (c *Client) UpdatePantryItem(ctx context.Context, id int, body UpdatePantryItemRequest) (PantryItem, error) {
+	req := newRequest(http.MethodPut, "/api/v1/pantry/"+url.PathEscape(strconv.Itoa(id)))
+	req.body = body
+	var ret PantryItem
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// DeletePantryItem sends DELETE /api/v1/pantry/{id}.
+//
+// Stop tracking an item in the pantry.
+func (c *Client) DeletePantryItem(ctx context.Context, id int) error {
+	req := newRequest(http.MethodDelete, "/api/v1/pantry/"+url.PathEscape(strconv.Itoa(id)))
+	return c.send(ctx, req, nil)
+}
+
+// ConsumePantryItem sends POST /api/v1/pantry/{id}/consume.
+//
+// Use up some of an item's pantry stock.
+//
+// Decrements the stock on hand, never going below zero. When the remaining
+// stock falls below the item's restock threshold and the item is not
+// already on the shopping list, it is added to the list.
+func (c *Client) ConsumePantryItem(ctx context.Context, id int, body ConsumePantryItemRequest) (ConsumePantryItemResponse, error) {
+	req := newRequest(http.MethodPost, "/api/v1/pantry/"+url.PathEscape(strconv.Itoa(id))+"/consume")
+	req.body = body
+	var ret ConsumePantryItemResponse
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// GetItemRecurrence sends GET /api/v1/recurrences/{id}.
+//
+// Get the recurrence rule for a staple item.
+func (c *Client) GetItemRecurrence(ctx context.Context, id int) (ItemRecurrence, error) {
+	req := newRequest(http.MethodGet, "/api/v1/recurrences/"+url.PathEscape(strconv.Itoa(id)))
+	var ret ItemRecurrence
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// SetItemRecurrence sends PUT /api/v1/recurrences/{id}.
+//
+// Create or replace the recurrence rule for an item.
+//
+// A background job adds due items to the shopping list, unless they are
+// already on it.
+func (c *Client) SetItemRecurrence(ctx context.Context, id int, body SetItemRecurrenceRequest) (ItemRecurrence, error) {
+	req := newRequest(http.MethodPut, "/api/v1/recurrences/"+url.PathEscape(strconv.Itoa(id)))
+	req.body = body
+	var ret ItemRecurrence
+	err := c.send(ctx, req, &ret)
+	return ret, err
+}
+
+// DeleteItemRecurrence sends DELETE /api/v1/recurrences/{id}.
+//
+// Stop an item from recurring.
+func (c *Client) DeleteItemRecurrence(ctx context.Context, id int) error {
+	req := newRequest(http.MethodDelete, "/api/v1/recurrences/"+url.PathEscape(strconv.Itoa(id)))
+	return c.send(ctx, req, nil)
+}
+
+var (
+	_ = url.PathEscape
+	_ = strconv.Itoa
+)
@@ -0,0 +1,275 @@
+// Package sdk is a Go client for the groceries REST API.
+//
+// The request and response types and most Client methods are generated from
+// the API's OpenAPI description, so every documented operation is available
+// with the names and fields the description gives it. Errors returned by the
+// API are reported as *Error.
+//
+// The web server hosts the API and calls it in-process through
+// NewHandlerTransport, on behalf of the user whose token is in the session.
+package sdk
+
+//go:generate go run ./internal/gen -spec ../internal/api/openapi.yaml -out .
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// UncategorizedCategoryID is the category ID of items without a category.
+const UncategorizedCategoryID = 0
+
+// pageLimit is the page size requested when collecting every page of a
+// listing. It is the largest the API accepts.
+const pageLimit = 500
+
+// Client is an API client for the groceries REST API.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	retries    int
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithTransport sends requests through rt instead of http.DefaultTransport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = rt
+	}
+}
+
+// WithRetries sets how many times a request is retried after a transient
+// failure. Requests that may not be safe to repeat are only retried when the
+// server turned them away before acting on them. The default is 2.
+func WithRetries(n int) Option {
+	return func(c *Client) {
+		c.retries = max(n, 0)
+	}
+}
+
+// New creates a new Client targeting baseURL and authenticating with token.
+func New(baseURL, token string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    baseURL,
+		token:      token,
+		httpClient: &http.Client{},
+		retries:    2,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Ptr returns a pointer to v, for setting optional fields.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Deref returns the value p points to, or the zero value when p is nil, for
+// reading optional fields.
+func Deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
+
+// request is an API request under construction.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   any
+}
+
+func newRequest(method, path string) *request {
+	return &request{
+		method: method,
+		path:   path,
+		query:  url.Values{},
+		header: http.Header{},
+	}
+}
+
+// send executes req and stores the response in out. When out is nil the
+// response body is discarded, and when it is a *Download the body is handed
+// over unread. Any other out is decoded from JSON. Responses outside the 2xx
+// range are returned as *Error.
+func (c *Client) send(ctx context.Context, req *request, out any) error {
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return err
+	} else if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		return newError(resp)
+	}
+
+	switch out := out.(type) {
+	case nil:
+		resp.Body.Close()
+	case *Download:
+		*out = Download{
+			Body:        resp.Body,
+			ContentType: resp.Header.Get("Content-Type"),
+		}
+		if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+			out.Filename = params["filename"]
+		}
+	default:
+		defer resp.Body.Close()
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("sdk: %s %s: decode response: %w", req.method, req.path, err)
+		}
+	}
+
+	return nil
+}
+
+// do executes req, attaching the Bearer token, and retries it after
+// transient failures. The caller is responsible for closing resp.Body.
+func (c *Client) do(ctx context.Context, req *request) (*http.Response, error) {
+	var body []byte
+	if req.body != nil {
+		var err error
+		body, err = json.Marshal(req.body)
+		if err != nil {
+			return nil, fmt.Errorf("sdk: marshal request body: %w", err)
+		}
+	}
+
+	target := c.baseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		var r io.Reader
+		if body != nil {
+			r = bytes.NewReader(body)
+		}
+		hr, err := http.NewRequestWithContext(ctx, req.method, target, r)
+		if err != nil {
+			return nil, fmt.Errorf("sdk: build request: %w", err)
+		}
+		for k, v := range req.header {
+			hr.Header[k] = v
+		}
+		if c.token != "" {
+			hr.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		if body != nil {
+			hr.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.httpClient.Do(hr)
+		if attempt >= c.retries || !retryable(req.method, resp, err) {
+			if err != nil {
+				return nil, fmt.Errorf("sdk: %s %s: %w", req.method, req.path, err)
+			}
+			return resp, nil
+		}
+
+		wait := backoff(attempt)
+		if resp != nil {
+			if d, ok := retryAfter(resp); ok {
+				wait = d
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, fmt.Errorf("sdk: %s %s: %w", req.method, req.path, ctx.Err())
+		case <-t.C:
+		}
+	}
+}
+
+// retryable reports whether a request that failed with resp or err may be
+// sent again. Requests that are not idempotent are only repeated when the
+// server asked the client to slow down, since it did not act on them.
+func retryable(method string, resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return idempotent(method)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent(method)
+	}
+	return false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// backoff returns how long to wait before retry number attempt+1: an
+// exponentially growing delay with jitter, so clients that failed together
+// do not retry together.
+func backoff(attempt int) time.Duration {
+	d := 100 * time.Millisecond << min(attempt, 6)
+	return d/2 + rand.N(d/2)
+}
+
+// retryAfter reads the delay a response asks for in its Retry-After header.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// collect reads every page of a listing by calling page with the cursor of
+// each page in turn, starting with none.
+func collect[T any](page func(cursor string) ([]T, PageMeta, error)) ([]T, error) {
+	var ret []T
+	cursor := ""
+	for {
+		data, meta, err := page(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, data...)
+		if !meta.HasMore || meta.NextCursor == nil {
+			return ret, nil
+		}
+		cursor = *meta.NextCursor
+	}
+}
@@ -0,0 +1,145 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestClient_ListItemsAll(t *testing.T) {
+	pages := map[string]any{
+		"": map[string]any{
+			"data": []Item{{ID: 1, Name: "Apples"}, {ID: 2, Name: "Bread"}},
+			"meta": map[string]any{"count": 2, "has_more": true, "next_cursor": "after-bread"},
+		},
+		"after-bread": map[string]any{
+			"data": []Item{{ID: 3, Name: "Milk"}},
+			"meta": map[string]any{"count": 1, "has_more": false},
+		},
+	}
+
+	var filters []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/items", func(w http.ResponseWriter, r *http.Request) {
+		filters = append(filters, r.URL.Query().Get("category_id")+"/"+r.URL.Query().Get("limit"))
+		page, ok := pages[r.URL.Query().Get("cursor")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(page)
+	})
+
+	c := New("http://groceries.test", "secret", WithTransport(NewHandlerTransport(mux)))
+
+	got, err := c.ListItemsAll(context.Background(), &ListItemsParams{CategoryID: Ptr(4)})
+	if err != nil {
+		t.Fatalf("ListItemsAll() error = %v", err)
+	}
+
+	want := []Item{{ID: 1, Name: "Apples"}, {ID: 2, Name: "Bread"}, {ID: 3, Name: "Milk"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListItemsAll() = %+v, want %+v", got, want)
+	}
+	if !reflect.DeepEqual(filters, []string{"4/500", "4/500"}) {
+		t.Errorf("category_id/limit of each page = %q, want the filter and largest limit on every page", filters)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"type": "about:blank", "title": "Not Found", "status": 404, "detail": "item not found", "code": "not_found", "error": "item not found"}`))
+	})
+	mux.HandleFunc("POST /api/v1/items", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status": 400, "detail": "name is required", "code": "validation_failed", "errors": [{"field": "name", "code": "required", "message": "name is required"}]}`))
+	})
+	mux.HandleFunc("PUT /api/v1/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"error": "alias is taken"}`))
+	})
+	mux.HandleFunc("DELETE /api/v1/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	c := New("http://groceries.test", "", WithTransport(NewHandlerTransport(mux)), WithRetries(0))
+
+	_, err := c.GetItem(context.Background(), 7)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("GetItem() error = %v, want an *Error", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Code != ProblemCodeNotFound || apiErr.Message != "item not found" {
+		t.Errorf("GetItem() error = %+v, want 404 not_found item not found", apiErr)
+	}
+	if !IsNotFound(err) || IsConflict(err) {
+		t.Errorf("IsNotFound() = %v, IsConflict() = %v, want only not found", IsNotFound(err), IsConflict(err))
+	}
+
+	_, err = c.CreateItem(context.Background(), CreateItemRequest{})
+	if got := Code(err); got != ProblemCodeValidationFailed {
+		t.Errorf("Code() = %q, want %q", got, ProblemCodeValidationFailed)
+	}
+	wantFields := []FieldProblem{{Field: Ptr("name"), Code: FieldProblemCodeRequired, Message: "name is required"}}
+	if !errors.As(err, &apiErr) || !reflect.DeepEqual(apiErr.Fields, wantFields) {
+		t.Errorf("CreateItem() error = %+v, want the fields at fault", err)
+	}
+
+	// Servers that predate problem details send only an error message
+	_, err = c.UpdateItem(context.Background(), 7, nil, UpdateItemRequest{})
+	if !errors.As(err, &apiErr) || apiErr.Message != "alias is taken" || apiErr.Code != "" {
+		t.Errorf("UpdateItem() error = %+v, want the message without a code", err)
+	}
+
+	err = c.DeleteItem(context.Background(), 7)
+	if got := StatusCode(err); got != http.StatusBadGateway {
+		t.Errorf("StatusCode() = %d, want %d", got, http.StatusBadGateway)
+	}
+	if !errors.As(err, &apiErr) || apiErr.Message != http.StatusText(http.StatusBadGateway) {
+		t.Errorf("DeleteItem() error = %v, want the status text as the message", err)
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		status    int
+		wantCalls int
+	}{
+		{name: "idempotent request on a bad gateway", method: http.MethodGet, status: http.StatusBadGateway, wantCalls: 3},
+		{name: "other request on a bad gateway", method: http.MethodPost, status: http.StatusBadGateway, wantCalls: 1},
+		{name: "other request when rate limited", method: http.MethodPost, status: http.StatusTooManyRequests, wantCalls: 3},
+		{name: "client error", method: http.MethodGet, status: http.StatusNotFound, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			mux := http.NewServeMux()
+			mux.HandleFunc("/flaky", func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(tt.status)
+			})
+
+			c := New("http://groceries.test", "", WithTransport(NewHandlerTransport(mux)))
+
+			err := c.send(context.Background(), newRequest(tt.method, "/flaky"), nil)
+			if StatusCode(err) != tt.status {
+				t.Errorf("send() error = %v, want status %d", err, tt.status)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
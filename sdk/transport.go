@@ -0,0 +1,129 @@
+package sdk
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// NewHandlerTransport returns a RoundTripper that serves requests by calling
+// h directly, without a network connection. It lets a process that hosts the
+// API call it through a Client without leaving the process.
+//
+// Requests reach h with their headers, including the Authorization token, and
+// their context, so cancellation and request-scoped values carry over.
+// Responses are streamed: the response is returned as soon as h writes its
+// headers, and the body delivers writes as h makes them.
+func NewHandlerTransport(h http.Handler) http.RoundTripper {
+	return handlerTransport{handler: h}
+}
+
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Build the request the handler would have received from a server
+	in := req.Clone(req.Context())
+	in.RequestURI = req.URL.RequestURI()
+	in.RemoteAddr = "in-process"
+	if in.Host == "" {
+		in.Host = req.URL.Host
+	}
+	if in.Body == nil {
+		in.Body = http.NoBody
+	}
+
+	body, pw := io.Pipe()
+	w := &pipeResponseWriter{
+		header: http.Header{},
+		body:   pw,
+		ready:  make(chan struct{}),
+	}
+
+	go func() {
+		defer in.Body.Close()
+		defer func() {
+			if p := recover(); p != nil {
+				// Mirror net/http, which recovers handler panics per request
+				if !w.wroteHeader() {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+				pw.CloseWithError(fmt.Errorf("sdk: handler panic: %v", p))
+				return
+			}
+
+			if !w.wroteHeader() {
+				w.WriteHeader(http.StatusOK)
+			}
+			pw.Close()
+		}()
+
+		t.handler.ServeHTTP(w, in)
+	}()
+
+	select {
+	case <-w.ready:
+	case <-req.Context().Done():
+		body.CloseWithError(req.Context().Err())
+		return nil, req.Context().Err()
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", w.status, http.StatusText(w.status)),
+		StatusCode:    w.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        w.sent,
+		Body:          body,
+		ContentLength: -1,
+		Request:       req,
+	}, nil
+}
+
+// pipeResponseWriter is an http.ResponseWriter that streams the body to the
+// Response returned by handlerTransport.
+type pipeResponseWriter struct {
+	header http.Header
+	body   *io.PipeWriter
+
+	once   sync.Once
+	ready  chan struct{}
+	status int
+	sent   http.Header
+}
+
+func (w *pipeResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *pipeResponseWriter) WriteHeader(code int) {
+	w.once.Do(func() {
+		// Later changes to the header map must not affect the response
+		w.status = code
+		w.sent = w.header.Clone()
+		close(w.ready)
+	})
+}
+
+func (w *pipeResponseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+// Flush implements http.Flusher. Writes are delivered unbuffered, so it only
+// needs to send the header.
+func (w *pipeResponseWriter) Flush() {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (w *pipeResponseWriter) wroteHeader() bool {
+	select {
+	case <-w.ready:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,157 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testContextKey struct{}
+
+func TestHandlerTransport(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/echo/{id}", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		value, _ := r.Context().Value(testContextKey{}).(string)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{
+			"id":            r.PathValue("id"),
+			"authorization": r.Header.Get("Authorization"),
+			"body":          string(body),
+			"context":       value,
+			"query":         r.URL.Query().Get("q"),
+		})
+	})
+
+	c := New("http://groceries.test", "secret", WithTransport(NewHandlerTransport(mux)))
+	ctx := context.WithValue(context.Background(), testContextKey{}, "from the caller")
+
+	req := newRequest(http.MethodPost, "/api/v1/echo/7")
+	req.query.Set("q", "milk")
+	req.body = map[string]string{"name": "Milk"}
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		t.Fatalf("do() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	defer resp.Body.Close()
+
+	var got map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"id":            "7",
+		"authorization": "Bearer secret",
+		"body":          `{"name":"Milk"}`,
+		"context":       "from the caller",
+		"query":         "milk",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestHandlerTransportErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /empty", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("GET /panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	c := New("http://groceries.test", "", WithTransport(NewHandlerTransport(mux)))
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/empty", want: http.StatusOK},
+		{path: "/panic", want: http.StatusInternalServerError},
+		{path: "/missing", want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := c.do(context.Background(), newRequest(http.MethodGet, tt.path))
+			if err != nil {
+				t.Fatalf("do() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlerTransportStreaming(t *testing.T) {
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "event: list\ndata: \n\n")
+		w.(http.Flusher).Flush()
+
+		// Hold the stream open until the test has read the first event
+		<-release
+		io.WriteString(w, "event: cart\ndata: \n\n")
+	})
+
+	c := New("http://groceries.test", "", WithTransport(NewHandlerTransport(mux)))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, err := c.Subscribe(ctx)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	if evt := <-events; evt.Name != "list" {
+		t.Errorf("first event = %q, want list", evt.Name)
+	}
+
+	close(release)
+	if evt := <-events; evt.Name != "cart" {
+		t.Errorf("second event = %q, want cart", evt.Name)
+	}
+
+	if _, ok := <-events; ok {
+		t.Error("events channel still open after the handler returned")
+	}
+}
+
+func TestHandlerTransportCancel(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /slow", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	c := New("http://groceries.test", "", WithTransport(NewHandlerTransport(mux)))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.do(ctx, newRequest(http.MethodGet, "/slow"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("do() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if err != nil && !strings.Contains(err.Error(), "/slow") {
+		t.Errorf("do() error = %v, want the path in the message", err)
+	}
+}
@@ -0,0 +1,848 @@
+// Code generated by sdk/internal/gen from openapi.yaml. DO NOT EDIT.
+
+package sdk
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Problem is the Problem schema.
+//
+// An error response, as RFC 9457 problem details. Tell problems apart by
+// `code` rather than by the wording of `detail`, which may change.
+type Problem struct {
+	// URI identifying the problem type; always `about:blank`
+	Type string `json:"type"`
+	// Summary of the HTTP status
+	Title string `json:"title"`
+	// HTTP status code of the response
+	Status int `json:"status"`
+	// Human-readable explanation of this occurrence
+	Detail string `json:"detail"`
+	// Stable, machine-readable identifier of a problem. Codes are not removed
+	// or renamed, but new ones may be added, so clients should treat an
+	// unknown code like the generic problem for the response status.
+	Code ProblemCode `json:"code"`
+	// The fields at fault, present when `code` is `validation_failed`
+	Errors []FieldProblem `json:"errors,omitzero"`
+	// The same as `detail`, kept for clients written before problem details
+	//
+	// Deprecated: This field may be removed in a future version of the API.
+	Error string `json:"error"`
+}
+
+// ProblemCode is the ProblemCode schema.
+//
+// Stable, machine-readable identifier of a problem. Codes are not removed
+// or renamed, but new ones may be added, so clients should treat an
+// unknown code like the generic problem for the response status.
+type ProblemCode string
+
+const (
+	ProblemCodeBadRequest          ProblemCode = "bad_request"
+	ProblemCodeValidationFailed    ProblemCode = "validation_failed"
+	ProblemCodeUnauthorized        ProblemCode = "unauthorized"
+	ProblemCodeInvalidCredentials  ProblemCode = "invalid_credentials"
+	ProblemCodeForbidden           ProblemCode = "forbidden"
+	ProblemCodeNotFound            ProblemCode = "not_found"
+	ProblemCodeInUse               ProblemCode = "in_use"
+	ProblemCodeAlreadyListed       ProblemCode = "already_listed"
+	ProblemCodeAliasConflict       ProblemCode = "alias_conflict"
+	ProblemCodeBarcodeAssigned     ProblemCode = "barcode_assigned"
+	ProblemCodeMergeIntoSelf       ProblemCode = "merge_into_self"
+	ProblemCodeUpstreamUnavailable ProblemCode = "upstream_unavailable"
+	ProblemCodeInternalError       ProblemCode = "internal_error"
+)
+
+// FieldProblem is the FieldProblem schema.
+//
+// A problem with one field of the request
+type FieldProblem struct {
+	// JSON name of the field, absent when the problem concerns the request as a whole
+	Field *string `json:"field,omitempty"`
+	// Stable, machine-readable kind of a field problem
+	Code FieldProblemCode `json:"code"`
+	// Human-readable explanation
+	Message string `json:"message"`
+}
+
+// FieldProblemCode is the FieldProblemCode schema.
+//
+// Stable, machine-readable kind of a field problem
+type FieldProblemCode string
+
+const (
+	FieldProblemCodeRequired         FieldProblemCode = "required"
+	FieldProblemCodeTooShort         FieldProblemCode = "too_short"
+	FieldProblemCodeTooLong          FieldProblemCode = "too_long"
+	FieldProblemCodeOutOfRange       FieldProblemCode = "out_of_range"
+	FieldProblemCodeInvalidChoice    FieldProblemCode = "invalid_choice"
+	FieldProblemCodeInvalidFormat    FieldProblemCode = "invalid_format"
+	FieldProblemCodeDuplicate        FieldProblemCode = "duplicate"
+	FieldProblemCodeUnknownReference FieldProblemCode = "unknown_reference"
+	FieldProblemCodeExclusive        FieldProblemCode = "exclusive"
+	FieldProblemCodeInvalid          FieldProblemCode = "invalid"
+)
+
+// PageMeta is the PageMeta schema.
+//
+// Describes a page of a listing. While `has_more` is true, pass
+// `next_cursor` as the `cursor` of the next request, keeping the same
+// `sort`, `q` and filters, to read the following page.
+type PageMeta struct {
+	// Number of entries in this page
+	Count int `json:"count"`
+	// Largest number of entries a page may hold
+	Limit int `json:"limit"`
+	// Order of the listing
+	Sort PageMetaSort `json:"sort"`
+	// Whether further entries follow this page
+	HasMore bool `json:"has_more"`
+	// Opaque cursor for the next page, present when `has_more` is true
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// PageMetaSort is the type of PageMeta.Sort.
+//
+// Order of the listing
+type PageMetaSort string
+
+const (
+	PageMetaSortName     PageMetaSort = "name"
+	PageMetaSortNameDesc PageMetaSort = "-name"
+	PageMetaSortID       PageMetaSort = "id"
+	PageMetaSortIDDesc   PageMetaSort = "-id"
+)
+
+// LoginRequest is the LoginRequest schema.
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LoginResponse is the LoginResponse schema.
+type LoginResponse struct {
+	// Bearer token to use in subsequent requests
+	Token string `json:"token"`
+	// ISO-8601 timestamp when the token expires
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// User is the User schema.
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Whether this user has administrator privileges
+	Admin bool `json:"admin"`
+}
+
+// CreateUserRequest is the CreateUserRequest schema.
+type CreateUserRequest struct {
+	Name  string `json:"name"`
+	Admin *bool  `json:"admin,omitempty"`
+}
+
+// UpdateUserRequest is the UpdateUserRequest schema.
+type UpdateUserRequest struct {
+	Name  *string `json:"name,omitempty"`
+	Admin *bool   `json:"admin,omitempty"`
+}
+
+// Group is the Group schema.
+type Group struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// CreateGroupRequest is the CreateGroupRequest schema.
+type CreateGroupRequest struct {
+	Name string `json:"name"`
+}
+
+// UpdateGroupRequest is the UpdateGroupRequest schema.
+type UpdateGroupRequest struct {
+	Name string `json:"name"`
+}
+
+// Store is the Store schema.
+type Store struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// CreateStoreRequest is the CreateStoreRequest schema.
+type CreateStoreRequest struct {
+	Name string `json:"name"`
+}
+
+// UpdateStoreRequest is the UpdateStoreRequest schema.
+type UpdateStoreRequest struct {
+	Name string `json:"name"`
+}
+
+// Category is the Category schema.
+type Category struct {
+	ID          int    `json:"id"`
+	StoreID     int    `json:"store_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Number of items assigned to this category
+	ItemCount int `json:"item_count"`
+	// Incremented on every change. Sent as the ETag of the category.
+	Version int `json:"version"`
+}
+
+// CreateCategoryRequest is the CreateCategoryRequest schema.
+type CreateCategoryRequest struct {
+	StoreID     int     `json:"store_id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
+
+// UpdateCategoryRequest is the UpdateCategoryRequest schema.
+type UpdateCategoryRequest struct {
+	StoreID     int     `json:"store_id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
+
+// ListItemSummary is the ListItemSummary schema.
+//
+// Presence of this object on an Item indicates the item is on the shopping list
+type ListItemSummary struct {
+	// ID of the list entry (not the item itself)
+	ID       int    `json:"id"`
+	Quantity string `json:"quantity"`
+	// Whether this item has been picked up during the current shopping trip
+	Done bool `json:"done"`
+	// Incremented on every change to the list entry. The ETag of the list item is the entry ID and this version, as `"<id>-<version>"`, since the version starts over when the item is added to the list again.
+	Version int `json:"version"`
+}
+
+// Item is the Item schema.
+type Item struct {
+	ID           int    `json:"id"`
+	CategoryID   int    `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	Name         string `json:"name"`
+	// Set when this item is currently on the shopping list, and null otherwise
+	List *ListItemSummary `json:"list,omitempty"`
+	// UPC/EAN barcodes assigned to the item. Only returned for single items.
+	Barcodes []string `json:"barcodes,omitzero"`
+	// Alternative names that resolve to this item when adding it to the list by
+	// name. Only returned for single items.
+	Aliases []string `json:"aliases,omitzero"`
+	// Incremented on every change to the item. Sent as the ETag of the item.
+	Version int `json:"version"`
+}
+
+// ItemMatch is the ItemMatch schema.
+type ItemMatch struct {
+	ID           int    `json:"id"`
+	CategoryID   int    `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	Name         string `json:"name"`
+	// Match quality. Exact, prefix and substring matches score 1, 0.9 and 0.8
+	// respectively; other matches score their trigram similarity to the query.
+	Score float64 `json:"score"`
+}
+
+// BatchItemsRequest is the BatchItemsRequest schema.
+//
+// Apply one action to several items. `assign` files the items under
+// `category_id`, `merge` folds them into `target_id`, and `delete` removes them.
+type BatchItemsRequest struct {
+	Action  BatchItemsRequestAction `json:"action"`
+	ItemIDs []int                   `json:"item_ids"`
+	// Required by `assign`
+	CategoryID *int `json:"category_id,omitempty"`
+	// Required by `merge`
+	TargetID *int `json:"target_id,omitempty"`
+}
+
+// BatchItemsRequestAction is the type of BatchItemsRequest.Action.
+type BatchItemsRequestAction string
+
+const (
+	BatchItemsRequestActionAssign BatchItemsRequestAction = "assign"
+	BatchItemsRequestActionMerge  BatchItemsRequestAction = "merge"
+	BatchItemsRequestActionDelete BatchItemsRequestAction = "delete"
+)
+
+// BatchItemsResponse is the BatchItemsResponse schema.
+type BatchItemsResponse struct {
+	Results []BatchItemResult `json:"results"`
+}
+
+// BatchItemResult is the BatchItemResult schema.
+type BatchItemResult struct {
+	ItemID int  `json:"item_id"`
+	OK     bool `json:"ok"`
+	// Why the action failed for this item
+	Error *string `json:"error,omitempty"`
+}
+
+// MergeItemRequest is the MergeItemRequest schema.
+type MergeItemRequest struct {
+	// Item to merge into
+	TargetID int `json:"target_id"`
+}
+
+// CreateItemRequest is the CreateItemRequest schema.
+type CreateItemRequest struct {
+	CategoryID int    `json:"category_id"`
+	Name       string `json:"name"`
+}
+
+// UpdateItemRequest is the UpdateItemRequest schema.
+type UpdateItemRequest struct {
+	CategoryID int    `json:"category_id"`
+	Name       string `json:"name"`
+	// Replaces the item's aliases. Omit to leave them unchanged. Aliases are
+	// matched case-insensitively and must not be the name or alias of another item.
+	Aliases []string `json:"aliases,omitzero"`
+}
+
+// ItemRecurrence is the ItemRecurrence schema.
+//
+// A rule that automatically places a staple item back on the shopping list.
+// Exactly one of `interval_days` or `weekday` is set.
+type ItemRecurrence struct {
+	ItemID int `json:"item_id"`
+	// Add the item again this many days after it was last added
+	IntervalDays *int `json:"interval_days"`
+	// Add the item every week on this day (0 = Sunday)
+	Weekday *int `json:"weekday"`
+	// Quantity to use when the item is added
+	Quantity string `json:"quantity"`
+	// When the scheduler last added the item to the list
+	LastAddedAt *time.Time `json:"last_added_at"`
+}
+
+// SetItemRecurrenceRequest is the SetItemRecurrenceRequest schema.
+//
+// Supply exactly one of `interval_days` or `weekday`.
+type SetItemRecurrenceRequest struct {
+	IntervalDays *int    `json:"interval_days,omitempty"`
+	Weekday      *int    `json:"weekday,omitempty"`
+	Quantity     *string `json:"quantity,omitempty"`
+}
+
+// Barcode is the Barcode schema.
+type Barcode struct {
+	ItemID int `json:"item_id"`
+	// Normalized barcode. UPC-A codes are stored as their EAN-13 equivalent.
+	Code string `json:"code"`
+}
+
+// AddBarcodeRequest is the AddBarcodeRequest schema.
+type AddBarcodeRequest struct {
+	// An 8, 12, 13 or 14 digit UPC/EAN barcode with a valid check digit
+	Code string `json:"code"`
+}
+
+// Product is the Product schema.
+type Product struct {
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Brand string `json:"brand"`
+}
+
+// BarcodeLookup is the BarcodeLookup schema.
+//
+// The result of resolving a barcode. `item` is set when the barcode belongs to
+// a known item; otherwise `product` holds the details reported by the product
+// lookup provider.
+type BarcodeLookup struct {
+	Code    string   `json:"code"`
+	Item    *Item    `json:"item"`
+	Product *Product `json:"product"`
+}
+
+// ListItem is the ListItem schema.
+type ListItem struct {
+	// ID of the list entry
+	ID         int    `json:"id"`
+	ItemID     int    `json:"item_id"`
+	ItemName   string `json:"item_name"`
+	CategoryID int    `json:"category_id"`
+	Quantity   string `json:"quantity"`
+	// Whether this item has been picked up during the current shopping trip
+	Done bool `json:"done"`
+	// Incremented on every change to the list entry. The ETag of the list item is the entry ID and this version, as `"<id>-<version>"`, since the version starts over when the item is added to the list again.
+	Version int `json:"version"`
+}
+
+// AddToListRequest is the AddToListRequest schema.
+//
+// Add an item to the shopping list. Supply either `item_id` to reference an existing
+// item, `barcode` to reference a scanned product, or `name` to create a new
+// uncategorized item on-the-fly (matching the current web app behaviour).
+type AddToListRequest struct {
+	ItemID *int `json:"item_id,omitempty"`
+	// UPC/EAN barcode of the item. Unknown barcodes are assigned to the item
+	// named by `name`, or to an item named after the product reported by the
+	// lookup provider.
+	Barcode *string `json:"barcode,omitempty"`
+	// Name of a new item to create and immediately add to the list
+	Name     *string `json:"name,omitempty"`
+	Quantity *string `json:"quantity,omitempty"`
+}
+
+// AddToListResponse is the AddToListResponse schema.
+type AddToListResponse struct {
+	ListItem
+	// Present when a new item was created: existing items with similar names
+	// that the caller may have meant instead.
+	Suggestions []ItemMatch `json:"suggestions,omitzero"`
+	// Present when a new item was created and similar catalog items are already
+	// categorized. The category is learned from the names of those items; when
+	// `assigned` is true the new item was filed under it, otherwise it was left
+	// uncategorized.
+	CategorySuggestion *CategorySuggestion `json:"category_suggestion,omitempty"`
+}
+
+// AddToListBatchRequest is the AddToListBatchRequest schema.
+type AddToListBatchRequest struct {
+	Items []AddToListBatchRequestItem `json:"items"`
+}
+
+// AddToListBatchRequestItem is an element of AddToListBatchRequest.Items.
+//
+// Supply either `item_id` for an existing item or `name` to find or create one.
+type AddToListBatchRequestItem struct {
+	ItemID   *int    `json:"item_id,omitempty"`
+	Name     *string `json:"name,omitempty"`
+	Quantity *string `json:"quantity,omitempty"`
+}
+
+// AddToListBatchResponse is the AddToListBatchResponse schema.
+type AddToListBatchResponse struct {
+	// One result per requested entry, in request order
+	Results []AddToListBatchResponseResult `json:"results"`
+}
+
+// AddToListBatchResponseResult is an element of AddToListBatchResponse.Results.
+type AddToListBatchResponseResult struct {
+	OK bool `json:"ok"`
+	// Why the entry was not added, when `ok` is false
+	Error *string   `json:"error,omitempty"`
+	Item  *ListItem `json:"item,omitempty"`
+	// Whether a new item was created for the entry's name
+	Created     bool        `json:"created"`
+	Suggestions []ItemMatch `json:"suggestions,omitzero"`
+	// Present when a new item was created and similar catalog items are already
+	// categorized. The category is learned from the names of those items; when
+	// `assigned` is true the new item was filed under it, otherwise it was left
+	// uncategorized.
+	CategorySuggestion *CategorySuggestion `json:"category_suggestion,omitempty"`
+}
+
+// ImportListRequest is the ImportListRequest schema.
+type ImportListRequest struct {
+	// The list to import: one item per line, a Markdown checklist, or CSV with
+	// a header naming the `name` (or `item`) column and optionally `quantity`
+	// and `unit` columns. Leading amounts such as "2 lbs" are read as the
+	// quantity, and checked-off Markdown items are skipped.
+	Text string `json:"text"`
+	// Format of `text`; detected from the content when empty
+	Format *ImportListRequestFormat `json:"format,omitempty"`
+}
+
+// ImportListRequestFormat is the type of ImportListRequest.Format.
+//
+// Format of `text`; detected from the content when empty
+type ImportListRequestFormat string
+
+const (
+	ImportListRequestFormatText     ImportListRequestFormat = "text"
+	ImportListRequestFormatMarkdown ImportListRequestFormat = "markdown"
+	ImportListRequestFormatCSV      ImportListRequestFormat = "csv"
+)
+
+// ImportListResponse is the ImportListResponse schema.
+type ImportListResponse struct {
+	// One result per parsed line, in the order they appear
+	Results []ImportListResponseResult `json:"results"`
+}
+
+// ImportListResponseResult is an element of ImportListResponse.Results.
+type ImportListResponseResult struct {
+	// 1-based line or CSV record the item was read from
+	Line     int    `json:"line"`
+	Name     string `json:"name"`
+	Quantity string `json:"quantity"`
+	OK       bool   `json:"ok"`
+	// Why the line was not added, when `ok` is false
+	Error *string   `json:"error,omitempty"`
+	Item  *ListItem `json:"item,omitempty"`
+	// Whether a new item was created for the line
+	Created bool `json:"created"`
+	// Whether the new item was left uncategorized and needs triage
+	Uncategorized bool `json:"uncategorized"`
+	// Present when a new item was created and similar catalog items are already
+	// categorized. The category is learned from the names of those items; when
+	// `assigned` is true the new item was filed under it, otherwise it was left
+	// uncategorized.
+	CategorySuggestion *CategorySuggestion `json:"category_suggestion,omitempty"`
+}
+
+// ListExport is the ListExport schema.
+type ListExport struct {
+	ExportedAt time.Time `json:"exported_at"`
+	// Stores with items on the list, each with its non-empty categories
+	Stores []ListExportStore `json:"stores"`
+}
+
+// ListExportStore is an element of ListExport.Stores.
+type ListExportStore struct {
+	Name       string                    `json:"name"`
+	Categories []ListExportStoreCategory `json:"categories"`
+}
+
+// ListExportStoreCategory is an element of ListExportStore.Categories.
+type ListExportStoreCategory struct {
+	Name  string                        `json:"name"`
+	Items []ListExportStoreCategoryItem `json:"items"`
+}
+
+// ListExportStoreCategoryItem is an element of ListExportStoreCategory.Items.
+type ListExportStoreCategoryItem struct {
+	ItemID   int    `json:"item_id"`
+	Name     string `json:"name"`
+	Quantity string `json:"quantity"`
+	Done     bool   `json:"done"`
+}
+
+// CatalogExport is the CatalogExport schema.
+type CatalogExport struct {
+	ExportedAt time.Time           `json:"exported_at"`
+	Stores     []Store             `json:"stores"`
+	Categories []Category          `json:"categories"`
+	Items      []CatalogExportItem `json:"items"`
+}
+
+// CatalogExportItem is an element of CatalogExport.Items.
+type CatalogExportItem struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	CategoryID int    `json:"category_id"`
+}
+
+// BackupArchive is the BackupArchive schema.
+//
+// A complete copy of the database. Records reference each other by the IDs
+// they had on the server that wrote the archive; they are remapped on restore.
+type BackupArchive struct {
+	Format string `json:"format"`
+	// Archive format version. Servers restore their own version and every earlier one.
+	Version    int                     `json:"version"`
+	CreatedAt  time.Time               `json:"created_at"`
+	Groups     []BackupArchiveGroup    `json:"groups,omitzero"`
+	Users      []BackupArchiveUser     `json:"users,omitzero"`
+	Stores     []BackupArchiveStore    `json:"stores,omitzero"`
+	Categories []BackupArchiveCategory `json:"categories,omitzero"`
+	Items      []BackupArchiveItem     `json:"items,omitzero"`
+	List       []BackupArchiveList     `json:"list,omitzero"`
+	Staples    []BackupArchiveStaple   `json:"staples,omitzero"`
+	Pantry     []BackupArchivePantry   `json:"pantry,omitzero"`
+}
+
+// BackupArchiveGroup is an element of BackupArchive.Groups.
+type BackupArchiveGroup struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// BackupArchiveUser is an element of BackupArchive.Users.
+type BackupArchiveUser struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Admin    bool   `json:"admin"`
+	GroupIDs []int  `json:"group_ids,omitzero"`
+}
+
+// BackupArchiveStore is an element of BackupArchive.Stores.
+type BackupArchiveStore struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// BackupArchiveCategory is an element of BackupArchive.Categories.
+type BackupArchiveCategory struct {
+	ID          int     `json:"id"`
+	StoreID     int     `json:"store_id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
+
+// BackupArchiveItem is an element of BackupArchive.Items.
+type BackupArchiveItem struct {
+	ID         int      `json:"id"`
+	CategoryID int      `json:"category_id"`
+	Name       string   `json:"name"`
+	Aliases    []string `json:"aliases,omitzero"`
+	Barcodes   []string `json:"barcodes,omitzero"`
+}
+
+// BackupArchiveList is an element of BackupArchive.List.
+type BackupArchiveList struct {
+	ItemID   int     `json:"item_id"`
+	Quantity *string `json:"quantity,omitempty"`
+	Done     *bool   `json:"done,omitempty"`
+}
+
+// BackupArchiveStaple is an element of BackupArchive.Staples.
+type BackupArchiveStaple struct {
+	ItemID       int        `json:"item_id"`
+	IntervalDays *int       `json:"interval_days,omitempty"`
+	Weekday      *int       `json:"weekday,omitempty"`
+	Quantity     *string    `json:"quantity,omitempty"`
+	LastAddedAt  *time.Time `json:"last_added_at,omitempty"`
+}
+
+// BackupArchivePantry is an element of BackupArchive.Pantry.
+type BackupArchivePantry struct {
+	ItemID           int        `json:"item_id"`
+	Quantity         int        `json:"quantity"`
+	Location         string     `json:"location"`
+	BestBefore       *time.Time `json:"best_before,omitempty"`
+	RestockThreshold *int       `json:"restock_threshold,omitempty"`
+}
+
+// RestoreCounts is the RestoreCounts schema.
+type RestoreCounts struct {
+	// Records created by the restore
+	Created int `json:"created"`
+	// Records matched by name to an existing record, which was left unchanged
+	Existing int `json:"existing"`
+}
+
+// RestoreSummary is the RestoreSummary schema.
+type RestoreSummary struct {
+	Groups     RestoreCounts `json:"groups"`
+	Users      RestoreCounts `json:"users"`
+	Stores     RestoreCounts `json:"stores"`
+	Categories RestoreCounts `json:"categories"`
+	Items      RestoreCounts `json:"items"`
+}
+
+// CategorySuggestion is the CategorySuggestion schema.
+//
+// Present when a new item was created and similar catalog items are already
+// categorized. The category is learned from the names of those items; when
+// `assigned` is true the new item was filed under it, otherwise it was left
+// uncategorized.
+type CategorySuggestion struct {
+	CategoryID   int    `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	// Share of the most similar catalog items filed under this category, weighted by similarity. Few or weak matches lower it, so a single loose match is never confident.
+	Confidence float64 `json:"confidence"`
+	// Whether the new item was filed under the suggested category
+	Assigned bool `json:"assigned"`
+}
+
+// UpdateListItemRequest is the UpdateListItemRequest schema.
+type UpdateListItemRequest struct {
+	Quantity *string `json:"quantity,omitempty"`
+	Done     *bool   `json:"done,omitempty"`
+}
+
+// ShoppingList is the ShoppingList schema.
+type ShoppingList struct {
+	Items []ListItem `json:"items"`
+	// Total number of items on the list
+	Total int `json:"total"`
+	// Number of items already marked done
+	TotalDone int `json:"total_done"`
+}
+
+// SyncOperation is the SyncOperation schema.
+//
+// A change made to the shopping list while offline. The item is identified
+// by `item_id` or, for items the client only knows by name, by `name`.
+// Adding a name that matches no item creates it.
+type SyncOperation struct {
+	// `add` puts the item on the list, `check` sets `done`, `quantity` sets
+	// `quantity` and `remove` takes the item off the list.
+	Type   SyncOperationType `json:"type"`
+	ItemID *int              `json:"item_id,omitempty"`
+	Name   *string           `json:"name,omitempty"`
+	// Quantity for `add` and `quantity` operations
+	Quantity *string `json:"quantity,omitempty"`
+	// Whether the item is in the cart, for `check` operations
+	Done *bool `json:"done,omitempty"`
+	// When the change was made on the client
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SyncOperationType is the type of SyncOperation.Type.
+//
+// `add` puts the item on the list, `check` sets `done`, `quantity` sets
+// `quantity` and `remove` takes the item off the list.
+type SyncOperationType string
+
+const (
+	SyncOperationTypeAdd      SyncOperationType = "add"
+	SyncOperationTypeCheck    SyncOperationType = "check"
+	SyncOperationTypeQuantity SyncOperationType = "quantity"
+	SyncOperationTypeRemove   SyncOperationType = "remove"
+)
+
+// SyncRequest is the SyncRequest schema.
+type SyncRequest struct {
+	// The `cursor` returned by the previous sync or changes request. Omit
+	// it on the first sync to receive every recorded change.
+	Cursor     *int64          `json:"cursor,omitempty"`
+	Operations []SyncOperation `json:"operations,omitzero"`
+}
+
+// SyncResult is the SyncResult schema.
+type SyncResult struct {
+	// `applied` when the change was made or the list was already as asked,
+	// `superseded` when a later change to the same entry won, and
+	// `not_found` when the item does not exist or is not on the list.
+	Status SyncResultStatus `json:"status"`
+	// The item the operation resolved to, including items created by `add`
+	ItemID *int `json:"item_id,omitempty"`
+}
+
+// SyncResultStatus is the type of SyncResult.Status.
+//
+// `applied` when the change was made or the list was already as asked,
+// `superseded` when a later change to the same entry won, and
+// `not_found` when the item does not exist or is not on the list.
+type SyncResultStatus string
+
+const (
+	SyncResultStatusApplied    SyncResultStatus = "applied"
+	SyncResultStatusSuperseded SyncResultStatus = "superseded"
+	SyncResultStatusNotFound   SyncResultStatus = "not_found"
+	SyncResultStatusInvalid    SyncResultStatus = "invalid"
+)
+
+// Change is the Change schema.
+type Change struct {
+	// Position of the change in the change log
+	Seq    int64        `json:"seq"`
+	Entity ChangeEntity `json:"entity"`
+	// ID of the changed entity. List items are identified by their item ID.
+	ID int `json:"id"`
+	// Summarizes everything that happened to the entity since the cursor.
+	// `delete` is a tombstone for an entity deleted last. `create` means it
+	// was created since the cursor, but an entity whose creation fell in an
+	// earlier page is reported as `update`, so clients should apply both
+	// as an upsert.
+	Op ChangeOp `json:"op"`
+	// Current state of the entity, absent when it was deleted. An Item,
+	// Category, ListItem or Store according to `entity`.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// ChangeEntity is the type of Change.Entity.
+type ChangeEntity string
+
+const (
+	ChangeEntityItem     ChangeEntity = "item"
+	ChangeEntityCategory ChangeEntity = "category"
+	ChangeEntityListItem ChangeEntity = "list_item"
+	ChangeEntityStore    ChangeEntity = "store"
+)
+
+// ChangeOp is the type of Change.Op.
+//
+// Summarizes everything that happened to the entity since the cursor.
+// `delete` is a tombstone for an entity deleted last. `create` means it
+// was created since the cursor, but an entity whose creation fell in an
+// earlier page is reported as `update`, so clients should apply both
+// as an upsert.
+type ChangeOp string
+
+const (
+	ChangeOpCreate ChangeOp = "create"
+	ChangeOpUpdate ChangeOp = "update"
+	ChangeOpDelete ChangeOp = "delete"
+)
+
+// ChangesPage is the ChangesPage schema.
+type ChangesPage struct {
+	// The latest change to each entity changed since the cursor, in `seq` order
+	Changes []Change `json:"changes"`
+	// Cursor to read from next. It is the `seq` of the last change, or the
+	// requested cursor when nothing has changed.
+	Cursor int64 `json:"cursor"`
+	// Whether further changes are waiting beyond `cursor`
+	HasMore bool `json:"has_more"`
+}
+
+// SyncResponse is the SyncResponse schema.
+type SyncResponse struct {
+	// One result per operation, in request order
+	Results []SyncResult `json:"results"`
+	// The latest change to each entity changed since the request's cursor,
+	// including the client's own operations, in `seq` order. At most 500
+	// entities are returned.
+	Changes []Change `json:"changes"`
+	// Cursor to send with the next sync
+	Cursor int64 `json:"cursor"`
+	// Whether further changes are waiting beyond `cursor`. Read them from
+	// `GET /api/v1/changes` before the next sync.
+	HasMore bool `json:"has_more"`
+}
+
+// PantryItem is the PantryItem schema.
+type PantryItem struct {
+	ItemID   int    `json:"item_id"`
+	ItemName string `json:"item_name"`
+	// Units currently on hand
+	Quantity   int                `json:"quantity"`
+	Location   PantryItemLocation `json:"location"`
+	BestBefore *string            `json:"best_before"`
+	// The item is added to the shopping list when consuming it leaves
+	// fewer than this many units on hand. Zero disables restocking.
+	RestockThreshold int `json:"restock_threshold"`
+}
+
+// PantryItemLocation is the type of PantryItem.Location.
+type PantryItemLocation string
+
+const (
+	PantryItemLocationFridge  PantryItemLocation = "fridge"
+	PantryItemLocationFreezer PantryItemLocation = "freezer"
+	PantryItemLocationPantry  PantryItemLocation = "pantry"
+)
+
+// UpdatePantryItemRequest is the UpdatePantryItemRequest schema.
+type UpdatePantryItemRequest struct {
+	Quantity         *int                             `json:"quantity,omitempty"`
+	Location         *UpdatePantryItemRequestLocation `json:"location,omitempty"`
+	BestBefore       *string                          `json:"best_before,omitempty"`
+	RestockThreshold *int                             `json:"restock_threshold,omitempty"`
+}
+
+// UpdatePantryItemRequestLocation is the type of UpdatePantryItemRequest.Location.
+type UpdatePantryItemRequestLocation string
+
+const (
+	UpdatePantryItemRequestLocationFridge  UpdatePantryItemRequestLocation = "fridge"
+	UpdatePantryItemRequestLocationFreezer UpdatePantryItemRequestLocation = "freezer"
+	UpdatePantryItemRequestLocationPantry  UpdatePantryItemRequestLocation = "pantry"
+)
+
+// ConsumePantryItemRequest is the ConsumePantryItemRequest schema.
+type ConsumePantryItemRequest struct {
+	Amount *int `json:"amount,omitempty"`
+}
+
+// ConsumePantryItemResponse is the ConsumePantryItemResponse schema.
+type ConsumePantryItemResponse struct {
+	PantryItem
+	// Whether the item was added to the shopping list because it ran low
+	AddedToList bool `json:"added_to_list"`
+}
+
+var (
+	_ json.RawMessage
+	_ time.Time
+)